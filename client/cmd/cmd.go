@@ -1,8 +1,24 @@
 package cmd
 
+import (
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
 // Runner representa all required behaviors that a command needs to have.
 type Runner interface {
 	Run() error
 	Init([]string) error
 	Name() string
 }
+
+// trackerOptions returns the options of a tracker connection sending the namespaces,
+// comma separated, with every call. Without namespaces the default one is used.
+func trackerOptions(namespaces string) []grpc.DialOption {
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if namespaces != "" {
+		opts = append(opts, namespace.DialOptions(namespace.Parse(namespaces)...)...)
+	}
+	return opts
+}
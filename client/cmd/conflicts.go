@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
+	"google.golang.org/grpc"
+)
+
+// ListConflictsCommand represents a command and all required info to list the file names
+// peers keep different contents under.
+type ListConflictsCommand struct {
+	fs        *flag.FlagSet
+	tracker   string
+	namespace string
+	pageSize  int
+}
+
+func NewListConflictsCommand() *ListConflictsCommand {
+	c := ListConflictsCommand{
+		fs: flag.NewFlagSet("conflicts", flag.ContinueOnError),
+	}
+
+	//set all args
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service.")
+	c.fs.StringVar(&c.namespace, "namespace", "", "namespace is the swarm to look into, a comma separated list for several, the default one when empty.")
+	c.fs.IntVar(&c.pageSize, "page-size", 100, "page-size is the number of conflicts fetched per request.")
+	return &c
+}
+
+func (lc *ListConflictsCommand) Name() string {
+	return lc.fs.Name()
+}
+
+func (lc *ListConflictsCommand) Init(args []string) error {
+	return lc.fs.Parse(args)
+}
+
+func (lc *ListConflictsCommand) Run() error {
+	if lc.tracker == "" {
+		return errors.New("'tracker' is required arg")
+	}
+
+	trackerConn, err := grpc.NewClient(lc.tracker, trackerOptions(lc.namespace)...)
+	if err != nil {
+		return fmt.Errorf("new tracker client: %w", err)
+	}
+	defer trackerConn.Close()
+
+	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
+
+	var token string
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		resp, err := trackerClient.ListConflicts(ctx, &tracker.ListConflictsRequest{
+			PageSize:  int32(lc.pageSize),
+			PageToken: token,
+		})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("list conflicts: %w", err)
+		}
+
+		for _, conflict := range resp.Conflicts {
+			fmt.Printf("file[%s]\n", conflict.GetFileName())
+			for _, v := range conflict.Versions {
+				fmt.Printf("\tchecksum[%s] size[%d]----> %d peers\n", v.GetFile().GetChecksum(), v.GetFile().GetSize(), v.GetPeerCount())
+			}
+			fmt.Println("============================================================")
+		}
+
+		token = resp.GetNextPageToken()
+		if token == "" {
+			return nil
+		}
+	}
+}
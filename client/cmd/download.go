@@ -3,14 +3,17 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -19,9 +22,14 @@ import (
 
 // DownloadFileCommand represents a command and all required info to download a file from a peer.
 type DownlaodFileCommand struct {
-	fs       *flag.FlagSet
-	peer     string
-	filename string
+	fs        *flag.FlagSet
+	peer      string
+	filename  string
+	checksum  string
+	tracker   string
+	ring      string
+	namespace string
+	mostRepl  bool
 }
 
 func NewDownloadFileCommand() *DownlaodFileCommand {
@@ -32,6 +40,11 @@ func NewDownloadFileCommand() *DownlaodFileCommand {
 	//set all args
 	c.fs.StringVar(&c.peer, "peer", "", "peer is the host:ip used to connect to a peer")
 	c.fs.StringVar(&c.filename, "filename", "", "filename is the name of the file you want to download")
+	c.fs.StringVar(&c.checksum, "checksum", "", "checksum downloads the exact content matching it, saved as 'filename'")
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is used to check whether peers keep different contents under 'filename' and to report a peer sending corrupted content")
+	c.fs.StringVar(&c.ring, "ring", "", "ring is the file defining the tracker shards, the shard owning 'filename' is used as 'tracker'")
+	c.fs.StringVar(&c.namespace, "namespace", "", "namespace is the swarm to look into, a comma separated list for several, the default one when empty.")
+	c.fs.BoolVar(&c.mostRepl, "most-replicated", false, "most-replicated downloads the content most peers keep under 'filename', requires 'tracker'")
 	return &c
 }
 
@@ -48,6 +61,19 @@ func (df *DownlaodFileCommand) Run() error {
 	if df.filename == "" || df.peer == "" {
 		return errors.New("both 'filename' and 'peer' args are required")
 	}
+	if df.mostRepl && df.tracker == "" && df.ring == "" {
+		return errors.New("'most-replicated' requires the 'tracker' or 'ring' arg")
+	}
+
+	//pick a content up front when peers disagree on what 'filename' is.
+	if (df.tracker != "" || df.ring != "") && df.checksum == "" {
+		checksum, err := df.chooseVersion()
+		if err != nil {
+			return err
+		}
+		df.checksum = checksum
+	}
+
 	peerConn, err := grpc.NewClient(df.peer, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		return fmt.Errorf("new peer client: %w", err)
@@ -69,11 +95,18 @@ func (df *DownlaodFileCommand) Run() error {
 		return fmt.Errorf("ping: %w", err)
 	}
 
-	in := peer.DownloadFileRequest{
-		FileName: df.filename,
+	var stream grpc.ServerStreamingClient[peer.FileChunk]
+	if df.checksum != "" {
+		in := peer.DownloadFileByChecksumRequest{
+			Checksum: df.checksum,
+		}
+		stream, err = peerClient.DownloadFileByChecksum(context.Background(), &in)
+	} else {
+		in := peer.DownloadFileRequest{
+			FileName: df.filename,
+		}
+		stream, err = peerClient.DownloadFile(context.Background(), &in)
 	}
-
-	stream, err := peerClient.DownloadFile(context.Background(), &in)
 	if err != nil {
 		return fmt.Errorf("download file: %w", err)
 	}
@@ -94,6 +127,7 @@ func (df *DownlaodFileCommand) Run() error {
 
 	//do the download logic
 	bufWriter := bufio.NewWriter(file)
+	hash := sha256.New()
 
 	for {
 		chunk, err := stream.Recv()
@@ -128,11 +162,91 @@ func (df *DownlaodFileCommand) Run() error {
 			return fmt.Errorf("recv: %w", err)
 		}
 		//write
+		hash.Write(chunk.Data)
 		if _, err := bufWriter.Write(chunk.Data); err != nil {
 			return fmt.Errorf("write: %w", err)
 		}
 		fmt.Printf("downloaded[%d/%d]\n", chunk.ChunkNumber, chunk.TotalChunks)
 	}
 
+	//a known content can be checked, corrupted or truncated downloads are thrown away.
+	if df.checksum != "" {
+		checksum := fmt.Sprintf("%X", hash.Sum(nil))
+		if checksum != df.checksum {
+			if err := os.Remove(static + "/" + df.filename); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("remove: %w", err)
+			}
+			if df.tracker != "" || df.ring != "" {
+				df.report(tracker.ReportReason_REPORT_REASON_CHECKSUM_MISMATCH)
+			}
+			return fmt.Errorf("peer [%s] sent content with checksum [%s], expected [%s]", df.peer, checksum, df.checksum)
+		}
+	}
+
 	return nil
 }
+
+// report tells the tracker that the peer misbehaved, failing to do so only gets logged.
+func (df *DownlaodFileCommand) report(reason tracker.ReportReason) {
+	trackerConn, err := dialOwner(df.tracker, df.ring, df.filename, df.namespace)
+	if err != nil {
+		fmt.Printf("report peer [%s]: %s\n", df.peer, err)
+		return
+	}
+	defer trackerConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
+	in := tracker.ReportPeerRequest{Host: df.peer, Reason: reason, FileName: df.filename}
+	resp, err := trackerClient.ReportPeer(ctx, &in)
+	if err != nil {
+		fmt.Printf("report peer [%s]: %s\n", df.peer, err)
+		return
+	}
+
+	if resp.GetBannedUntil() != nil {
+		fmt.Printf("reported peer [%s], banned until %s\n", df.peer, resp.GetBannedUntil().AsTime().Local().Format(time.RFC3339))
+		return
+	}
+	fmt.Printf("reported peer [%s], penalty %.1f\n", df.peer, resp.GetPenalty())
+}
+
+// chooseVersion returns the checksum to download when peers keep different contents
+// under the file name, or an empty checksum when they all agree.
+func (df *DownlaodFileCommand) chooseVersion() (string, error) {
+	trackerConn, err := dialOwner(df.tracker, df.ring, df.filename, df.namespace)
+	if err != nil {
+		return "", fmt.Errorf("new tracker client: %w", err)
+	}
+	defer trackerConn.Close()
+
+	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := trackerClient.GetFileVersions(ctx, &tracker.GetFileVersionsRequest{FileName: df.filename})
+	if err != nil {
+		return "", fmt.Errorf("get file versions: %w", err)
+	}
+
+	versions := resp.GetVersions()
+	if len(versions) < 2 {
+		return "", nil
+	}
+
+	if df.mostRepl {
+		checksum := versions[0].GetFile().GetChecksum()
+		fmt.Printf("file [%s] has %d versions, downloading the most replicated [%s]\n", df.filename, len(versions), checksum)
+		return checksum, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "file [%s] has %d versions, pass 'checksum' or 'most-replicated':", df.filename, len(versions))
+	for _, v := range versions {
+		fmt.Fprintf(&b, "\n\tchecksum[%s] size[%d]----> %d peers", v.GetFile().GetChecksum(), v.GetFile().GetSize(), v.GetPeerCount())
+	}
+	return "", errors.New(b.String())
+}
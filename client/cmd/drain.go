@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// DrainPeerCommand represents a command and all required info to take a peer out of the
+// network without losing its files.
+type DrainPeerCommand struct {
+	fs         *flag.FlagSet
+	tracker    string
+	ring       string
+	host       string
+	tokenFile  string
+	adminToken string
+}
+
+func NewDrainPeerCommand() *DrainPeerCommand {
+	c := DrainPeerCommand{
+		fs: flag.NewFlagSet("drain", flag.ContinueOnError),
+	}
+
+	//set all args
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service.")
+	c.fs.StringVar(&c.ring, "ring", "", "ring is the file defining the tracker shards, the peer is drained from every shard instead of 'tracker'.")
+	c.fs.StringVar(&c.host, "host", "", "host is the peer to drain.")
+	c.fs.StringVar(&c.tokenFile, "token-file", "", "token-file is the file the peer keeps its tracker token in.")
+	c.fs.StringVar(&c.adminToken, "admin-token-file", "", "admin-token-file is the file keeping the admin token of the tracker, admins drain any peer without its token.")
+	return &c
+}
+
+func (dp *DrainPeerCommand) Name() string {
+	return dp.fs.Name()
+}
+
+func (dp *DrainPeerCommand) Init(args []string) error {
+	return dp.fs.Parse(args)
+}
+
+func (dp *DrainPeerCommand) Run() error {
+	if (dp.tracker == "" && dp.ring == "") || dp.host == "" || (dp.tokenFile == "" && dp.adminToken == "") {
+		return errors.New("'tracker' or 'ring', 'host' and 'token-file' or 'admin-token-file' are required args")
+	}
+
+	var token []byte
+	if dp.tokenFile != "" {
+		var err error
+		token, err = os.ReadFile(dp.tokenFile)
+		if err != nil {
+			return fmt.Errorf("read token: %w", err)
+		}
+	}
+
+	ctx, err := withAdminToken(context.Background(), dp.adminToken)
+	if err != nil {
+		return err
+	}
+
+	if dp.ring == "" {
+		trackerConn, err := grpc.NewClient(dp.tracker, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return fmt.Errorf("new tracker client: %w", err)
+		}
+		defer trackerConn.Close()
+
+		return dp.drain(ctx, tracker.NewTrackerServiceClient(trackerConn), strings.TrimSpace(string(token)))
+	}
+
+	shards, err := dialRing(dp.ring, "")
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+
+	//every shard copies the files it owns, with the token the peer has there.
+	tokens, err := url.ParseQuery(strings.TrimSpace(string(token)))
+	if err != nil {
+		return fmt.Errorf("parse token: %w", err)
+	}
+	for _, addr := range shards.ring.Shards() {
+		fmt.Printf("draining peer[%s] from shard[%s]\n", dp.host, addr)
+		if err := dp.drain(ctx, shards.clients[addr], tokens.Get(addr)); err != nil {
+			return fmt.Errorf("shard %s: %w", addr, err)
+		}
+	}
+	return nil
+}
+
+// drain drains the peer from a single tracker and prints the progress.
+func (dp *DrainPeerCommand) drain(ctx context.Context, trackerClient tracker.TrackerServiceClient, token string) error {
+	in := tracker.DrainPeerRequest{
+		Host:  dp.host,
+		Token: token,
+	}
+	stream, err := trackerClient.DrainPeer(ctx, &in)
+	if err != nil {
+		return fmt.Errorf("drain peer: %w", err)
+	}
+
+	for {
+		progress, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("drain peer: %w", err)
+		}
+
+		switch {
+		case progress.GetDone():
+			fmt.Printf("peer[%s] drained and unregistered, copied[%d/%d]\n", dp.host, progress.GetCopiedFiles(), progress.GetTotalFiles())
+		case progress.GetFile() != nil:
+			fmt.Printf("copied[%d/%d] file[%s]----> %s\n", progress.GetCopiedFiles(), progress.GetTotalFiles(), progress.GetFile().GetName(), progress.GetTarget())
+		default:
+			fmt.Printf("peer[%s] draining, %d files only it serves\n", dp.host, progress.GetTotalFiles())
+		}
+	}
+}
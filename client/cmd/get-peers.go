@@ -5,16 +5,21 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/hamidoujand/P2P-file-sharing-network/failover"
 )
 
 type GetPeersCommand struct {
-	fs      *flag.FlagSet
-	tracker string
+	fs        *flag.FlagSet
+	tracker   string
+	ring      string
+	namespace string
+	pageSize  int
+	stream    bool
 }
 
 func NewGetPeersCommand() *GetPeersCommand {
@@ -23,7 +28,11 @@ func NewGetPeersCommand() *GetPeersCommand {
 	}
 
 	//set all args
-	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service.")
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service, a comma separated list to fail over between trackers.")
+	c.fs.StringVar(&c.ring, "ring", "", "ring is the file defining the tracker shards, peers are gathered from every shard instead of 'tracker'.")
+	c.fs.StringVar(&c.namespace, "namespace", "", "namespace is the swarm to look into, a comma separated list for several, the default one when empty.")
+	c.fs.IntVar(&c.pageSize, "page-size", 100, "page-size is the number of peers fetched per request.")
+	c.fs.BoolVar(&c.stream, "stream", false, "stream receives peers one at a time instead of in pages.")
 	return &c
 }
 
@@ -36,12 +45,16 @@ func (gp *GetPeersCommand) Init(args []string) error {
 }
 
 func (gp *GetPeersCommand) Run() error {
-	if gp.tracker == "" {
-		return errors.New("'tracker' is required arg")
+	if gp.tracker == "" && gp.ring == "" {
+		return errors.New("'tracker' or 'ring' is required arg")
+	}
+
+	if gp.ring != "" {
+		return gp.ringPeers()
 	}
 
 	//check the peer conn
-	trackerConn, err := grpc.NewClient(gp.tracker, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	trackerConn, err := failover.New(strings.Split(gp.tracker, ","), trackerOptions(gp.namespace)...)
 	if err != nil {
 		return fmt.Errorf("new peer client: %w", err)
 	}
@@ -50,27 +63,96 @@ func (gp *GetPeersCommand) Run() error {
 	//peer client
 	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
 
-	getPeersReq := &tracker.GetPeersRequest{}
+	if gp.stream {
+		return gp.streamPeers(trackerClient)
+	}
+
+	in := &tracker.GetPeersRequest{
+		PageSize: int32(gp.pageSize),
+	}
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		resp, err := trackerClient.GetPeers(ctx, in)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("get peers: %w", err)
+		}
+
+		for _, peer := range resp.Peers {
+			printPeer(peer)
+		}
+
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		in.PageToken = resp.NextPageToken
+	}
+}
+
+// ringPeers prints the peers of every shard, with the files of every shard.
+func (gp *GetPeersCommand) ringPeers() error {
+	shards, err := dialRing(gp.ring, gp.namespace)
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 
-	resp, err := trackerClient.GetPeers(ctx, getPeersReq)
+	peers, err := shards.peers(ctx)
 	if err != nil {
 		return fmt.Errorf("get peers: %w", err)
 	}
 
-	for _, peer := range resp.Peers {
-		fmt.Println("============================================================")
-		fmt.Printf("peer[%s]\n", peer.Host)
-		if len(peer.Files) == 0 {
-			continue
+	for _, peer := range peers {
+		printPeer(peer)
+	}
+	return nil
+}
+
+// streamPeers prints the peers as the tracker streams them.
+func (gp *GetPeersCommand) streamPeers(trackerClient tracker.TrackerServiceClient) error {
+	stream, err := trackerClient.ListPeers(context.Background(), &tracker.ListPeersRequest{})
+	if err != nil {
+		return fmt.Errorf("list peers: %w", err)
+	}
+
+	for {
+		peer, err := stream.Recv()
+		if err == io.EOF {
+			return nil
 		}
-		for _, file := range peer.Files {
-			fmt.Printf("\tfile[%s]----> %s\n", file.GetName(), file.GetChecksum())
+		if err != nil {
+			return fmt.Errorf("recv: %w", err)
 		}
-		fmt.Println("============================================================")
+		printPeer(peer)
 	}
+}
 
-	return nil
+func printPeer(peer *tracker.Peer) {
+	fmt.Println("============================================================")
+	fmt.Printf("peer[%s] health[%s] rtt[%dms] streams[%d] rate[%dB/s]\n", peer.Host, peer.GetHealth(), peer.GetRttMillis(), peer.GetLoad().GetActiveStreams(), peer.GetLoad().GetBytesPerSecond())
+	if len(peer.Labels) > 0 {
+		fmt.Printf("\tlabels%v\n", peer.Labels)
+	}
+	if peer.GetDraining() {
+		fmt.Println("\tdraining")
+	}
+	if peer.GetBannedUntil() != nil {
+		fmt.Printf("\tbanned until %s, penalty[%.1f]\n", peer.GetBannedUntil().AsTime().Local().Format(time.RFC3339), peer.GetPenalty())
+	} else if peer.GetPenalty() >= 0.1 {
+		fmt.Printf("\treported, penalty[%.1f]\n", peer.GetPenalty())
+	}
+	if peer.GetRemote() {
+		fmt.Printf("\tremote, registered with tracker[%s]\n", peer.GetTracker())
+	}
+	if len(peer.Files) == 0 {
+		return
+	}
+	for _, file := range peer.Files {
+		fmt.Printf("\tfile[%s]----> %s\n", file.GetName(), file.GetChecksum())
+	}
+	fmt.Println("============================================================")
 }
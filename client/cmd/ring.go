@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/ring"
+	"google.golang.org/grpc"
+)
+
+const (
+	// defaultPageSize and maxPageSize page merged results like a single tracker does.
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// ringClients are the tracker shards of a ring, every shard indexes the files whose
+// names it owns and knows every peer.
+type ringClients struct {
+	ring    *ring.Ring
+	conns   []*grpc.ClientConn
+	clients map[string]tracker.TrackerServiceClient
+}
+
+// dialRing connects to every shard of the ring defined in the file at path, the same
+// file the peers use as TRACKER_RING_FILE.
+func dialRing(path string, namespaces string) (*ringClients, error) {
+	r, err := ring.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("load ring: %w", err)
+	}
+
+	rc := ringClients{
+		ring:    r,
+		clients: make(map[string]tracker.TrackerServiceClient),
+	}
+	for _, addr := range r.Shards() {
+		conn, err := grpc.NewClient(addr, trackerOptions(namespaces)...)
+		if err != nil {
+			rc.Close()
+			return nil, fmt.Errorf("new tracker client %s: %w", addr, err)
+		}
+		rc.conns = append(rc.conns, conn)
+		rc.clients[addr] = tracker.NewTrackerServiceClient(conn)
+	}
+	return &rc, nil
+}
+
+func (rc *ringClients) Close() error {
+	for _, conn := range rc.conns {
+		conn.Close()
+	}
+	return nil
+}
+
+// dialOwner connects to the tracker answering for a file name: the shard owning the
+// name when a ring file is given, the tracker otherwise.
+func dialOwner(addr string, ringFile string, name string, namespaces string) (*grpc.ClientConn, error) {
+	if ringFile != "" {
+		r, err := ring.Load(ringFile)
+		if err != nil {
+			return nil, fmt.Errorf("load ring: %w", err)
+		}
+		addr = r.Owner(name)
+	}
+	return grpc.NewClient(addr, trackerOptions(namespaces)...)
+}
+
+// searchFiles searches every shard and pages the merged results like a single tracker,
+// a file found on two shards while it moves between them is listed once.
+func (rc *ringClients) searchFiles(ctx context.Context, in *tracker.SearchFilesRequest) (*tracker.SearchFilesResponse, error) {
+	var results []*tracker.FileSummary
+	for _, addr := range rc.ring.Shards() {
+		req := tracker.SearchFilesRequest{
+			Pattern:   in.GetPattern(),
+			MatchMode: in.GetMatchMode(),
+			MinSize:   in.GetMinSize(),
+			MaxSize:   in.GetMaxSize(),
+			PageSize:  maxPageSize,
+		}
+		for {
+			resp, err := rc.clients[addr].SearchFiles(ctx, &req)
+			if err != nil {
+				return nil, fmt.Errorf("shard %s: %w", addr, err)
+			}
+			results = append(results, resp.GetFiles()...)
+			if resp.GetNextPageToken() == "" {
+				break
+			}
+			req.PageToken = resp.GetNextPageToken()
+		}
+	}
+
+	slices.SortFunc(results, func(a, b *tracker.FileSummary) int {
+		return cmp.Or(
+			cmp.Compare(a.GetFile().GetName(), b.GetFile().GetName()),
+			cmp.Compare(a.GetFile().GetChecksum(), b.GetFile().GetChecksum()),
+			cmp.Compare(b.GetPeerCount(), a.GetPeerCount()),
+		)
+	})
+	results = slices.CompactFunc(results, func(a, b *tracker.FileSummary) bool {
+		return a.GetFile().GetName() == b.GetFile().GetName() && a.GetFile().GetChecksum() == b.GetFile().GetChecksum()
+	})
+	if in.GetOrder() == tracker.SearchOrder_SEARCH_ORDER_AVAILABILITY {
+		slices.SortStableFunc(results, func(a, b *tracker.FileSummary) int {
+			return cmp.Compare(b.GetPeerCount(), a.GetPeerCount())
+		})
+	}
+
+	offset, err := decodePageToken(in.GetPageToken())
+	if err != nil {
+		return nil, fmt.Errorf("invalid page token: %w", err)
+	}
+	size := int(in.GetPageSize())
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	start := min(offset, len(results))
+	end := min(start+size, len(results))
+
+	resp := tracker.SearchFilesResponse{Files: results[start:end]}
+	if end < len(results) {
+		resp.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+	}
+	return &resp, nil
+}
+
+// peers returns every peer of the ring by host, with the files of every shard.
+func (rc *ringClients) peers(ctx context.Context) ([]*tracker.Peer, error) {
+	byHost := make(map[string]*tracker.Peer)
+	for _, addr := range rc.ring.Shards() {
+		in := tracker.GetPeersRequest{PageSize: maxPageSize}
+		for {
+			resp, err := rc.clients[addr].GetPeers(ctx, &in)
+			if err != nil {
+				return nil, fmt.Errorf("shard %s: %w", addr, err)
+			}
+
+			for _, p := range resp.GetPeers() {
+				if found, ok := byHost[p.GetHost()]; ok {
+					found.Files = append(found.Files, p.GetFiles()...)
+					continue
+				}
+				byHost[p.GetHost()] = p
+			}
+
+			if resp.GetNextPageToken() == "" {
+				break
+			}
+			in.PageToken = resp.GetNextPageToken()
+		}
+	}
+
+	peers := make([]*tracker.Peer, 0, len(byHost))
+	for _, p := range byHost {
+		slices.SortFunc(p.Files, func(a, b *tracker.File) int { return cmp.Compare(a.GetName(), b.GetName()) })
+		peers = append(peers, p)
+	}
+	slices.SortFunc(peers, func(a, b *tracker.Peer) int { return cmp.Compare(a.GetHost(), b.GetHost()) })
+	return peers, nil
+}
+
+// shardFile returns the file in dir holding the snapshot of a shard.
+func shardFile(dir string, addr string) string {
+	return filepath.Join(dir, strings.NewReplacer(":", "_", "/", "_").Replace(addr)+".json")
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return offset, nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
+	"google.golang.org/grpc"
+)
+
+// SearchFilesCommand represents a command and all required info to search files on the network.
+type SearchFilesCommand struct {
+	fs        *flag.FlagSet
+	tracker   string
+	ring      string
+	namespace string
+	pattern   string
+	match     string
+	minSize   int64
+	maxSize   int64
+	order     string
+	pageSize  int
+	pageToken string
+}
+
+func NewSearchFilesCommand() *SearchFilesCommand {
+	c := SearchFilesCommand{
+		fs: flag.NewFlagSet("search", flag.ContinueOnError),
+	}
+
+	//set all args
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service.")
+	c.fs.StringVar(&c.ring, "ring", "", "ring is the file defining the tracker shards, every shard is searched instead of 'tracker'.")
+	c.fs.StringVar(&c.namespace, "namespace", "", "namespace is the swarm to look into, a comma separated list for several, the default one when empty.")
+	c.fs.StringVar(&c.pattern, "pattern", "", "pattern to match file names against, empty matches all files.")
+	c.fs.StringVar(&c.match, "match", "substring", "match is how the pattern is matched: substring, prefix or glob.")
+	c.fs.Int64Var(&c.minSize, "min-size", 0, "min-size is the minimum file size in bytes.")
+	c.fs.Int64Var(&c.maxSize, "max-size", 0, "max-size is the maximum file size in bytes, 0 means no limit.")
+	c.fs.StringVar(&c.order, "sort", "name", "sort orders results by: name or availability.")
+	c.fs.IntVar(&c.pageSize, "page-size", 20, "page-size is the number of results per page.")
+	c.fs.StringVar(&c.pageToken, "page-token", "", "page-token continues a previous search.")
+	return &c
+}
+
+func (sf *SearchFilesCommand) Name() string {
+	return sf.fs.Name()
+}
+
+func (sf *SearchFilesCommand) Init(args []string) error {
+	return sf.fs.Parse(args)
+}
+
+func (sf *SearchFilesCommand) Run() error {
+	if sf.tracker == "" && sf.ring == "" {
+		return errors.New("'tracker' or 'ring' is required arg")
+	}
+
+	modes := map[string]tracker.MatchMode{
+		"substring": tracker.MatchMode_MATCH_MODE_SUBSTRING,
+		"prefix":    tracker.MatchMode_MATCH_MODE_PREFIX,
+		"glob":      tracker.MatchMode_MATCH_MODE_GLOB,
+	}
+	mode, ok := modes[sf.match]
+	if !ok {
+		return fmt.Errorf("unknown match mode: %q", sf.match)
+	}
+
+	orders := map[string]tracker.SearchOrder{
+		"name":         tracker.SearchOrder_SEARCH_ORDER_NAME,
+		"availability": tracker.SearchOrder_SEARCH_ORDER_AVAILABILITY,
+	}
+	order, ok := orders[sf.order]
+	if !ok {
+		return fmt.Errorf("unknown sort order: %q", sf.order)
+	}
+
+	in := tracker.SearchFilesRequest{
+		Pattern:   sf.pattern,
+		MatchMode: mode,
+		MinSize:   sf.minSize,
+		MaxSize:   sf.maxSize,
+		Order:     order,
+		PageSize:  int32(sf.pageSize),
+		PageToken: sf.pageToken,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := sf.search(ctx, &in)
+	if err != nil {
+		return fmt.Errorf("search files: %w", err)
+	}
+
+	if len(resp.Files) == 0 {
+		fmt.Println("no files found")
+		return nil
+	}
+
+	for _, summary := range resp.Files {
+		file := summary.GetFile()
+		fmt.Printf("file[%s] size[%d] peers[%d]----> %s\n", file.GetName(), file.GetSize(), summary.GetPeerCount(), file.GetChecksum())
+	}
+
+	if resp.NextPageToken != "" {
+		fmt.Printf("more results: -page-token=%s\n", resp.NextPageToken)
+	}
+	return nil
+}
+
+// search asks the tracker, or every shard of the ring.
+func (sf *SearchFilesCommand) search(ctx context.Context, in *tracker.SearchFilesRequest) (*tracker.SearchFilesResponse, error) {
+	if sf.ring != "" {
+		shards, err := dialRing(sf.ring, sf.namespace)
+		if err != nil {
+			return nil, err
+		}
+		defer shards.Close()
+		return shards.searchFiles(ctx, in)
+	}
+
+	trackerConn, err := grpc.NewClient(sf.tracker, trackerOptions(sf.namespace)...)
+	if err != nil {
+		return nil, fmt.Errorf("new tracker client: %w", err)
+	}
+	defer trackerConn.Close()
+
+	return tracker.NewTrackerServiceClient(trackerConn).SearchFiles(ctx, in)
+}
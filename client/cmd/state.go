@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// ExportStateCommand represents a command and all required info to dump the state of a tracker.
+type ExportStateCommand struct {
+	fs         *flag.FlagSet
+	tracker    string
+	ring       string
+	out        string
+	adminToken string
+}
+
+func NewExportStateCommand() *ExportStateCommand {
+	c := ExportStateCommand{
+		fs: flag.NewFlagSet("export", flag.ContinueOnError),
+	}
+
+	//set all args
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service.")
+	c.fs.StringVar(&c.ring, "ring", "", "ring is the file defining the tracker shards, every shard is exported instead of 'tracker'.")
+	c.fs.StringVar(&c.out, "out", "", "out is the file to write the snapshot to, stdout when empty. With 'ring' it is the directory to write the snapshot of every shard to.")
+	c.fs.StringVar(&c.adminToken, "admin-token-file", "", "admin-token-file is the file keeping the admin token of the tracker, without it only a tracker on this machine exports, leaving the peer tokens out.")
+	return &c
+}
+
+func (es *ExportStateCommand) Name() string {
+	return es.fs.Name()
+}
+
+func (es *ExportStateCommand) Init(args []string) error {
+	return es.fs.Parse(args)
+}
+
+func (es *ExportStateCommand) Run() error {
+	if es.ring != "" {
+		return es.exportRing()
+	}
+
+	if es.tracker == "" {
+		return errors.New("'tracker' or 'ring' is required arg")
+	}
+
+	trackerConn, err := grpc.NewClient(es.tracker, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("new tracker client: %w", err)
+	}
+	defer trackerConn.Close()
+
+	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	ctx, err = withAdminToken(ctx, es.adminToken)
+	if err != nil {
+		return err
+	}
+
+	resp, err := trackerClient.ExportState(ctx, &tracker.ExportStateRequest{})
+	if err != nil {
+		return fmt.Errorf("export state: %w", err)
+	}
+
+	if es.out == "" {
+		_, err := os.Stdout.Write(resp.GetSnapshot())
+		return err
+	}
+
+	if err := os.WriteFile(es.out, resp.GetSnapshot(), 0600); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	fmt.Printf("snapshot written to %s\n", es.out)
+	return nil
+}
+
+// exportRing writes the snapshot of every shard to a file of its own, every shard only
+// holds the files it owns.
+func (es *ExportStateCommand) exportRing() error {
+	if es.out == "" {
+		return errors.New("'out' is required with 'ring'")
+	}
+	if err := os.MkdirAll(es.out, 0700); err != nil {
+		return fmt.Errorf("mkdir all: %w", err)
+	}
+
+	shards, err := dialRing(es.ring, "")
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	ctx, err = withAdminToken(ctx, es.adminToken)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range shards.ring.Shards() {
+		resp, err := shards.clients[addr].ExportState(ctx, &tracker.ExportStateRequest{})
+		if err != nil {
+			return fmt.Errorf("export state of shard %s: %w", addr, err)
+		}
+
+		path := shardFile(es.out, addr)
+		if err := os.WriteFile(path, resp.GetSnapshot(), 0600); err != nil {
+			return fmt.Errorf("write snapshot: %w", err)
+		}
+		fmt.Printf("snapshot of shard[%s] written to %s\n", addr, path)
+	}
+	return nil
+}
+
+// ImportStateCommand represents a command and all required info to load a snapshot into a tracker.
+type ImportStateCommand struct {
+	fs         *flag.FlagSet
+	tracker    string
+	ring       string
+	in         string
+	adminToken string
+}
+
+func NewImportStateCommand() *ImportStateCommand {
+	c := ImportStateCommand{
+		fs: flag.NewFlagSet("import", flag.ContinueOnError),
+	}
+
+	//set all args
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service, it must not have peers yet.")
+	c.fs.StringVar(&c.ring, "ring", "", "ring is the file defining the tracker shards, every shard is imported instead of 'tracker'.")
+	c.fs.StringVar(&c.in, "in", "", "in is the snapshot file made by export. With 'ring' it is the directory export wrote the snapshot of every shard to.")
+	c.fs.StringVar(&c.adminToken, "admin-token-file", "", "admin-token-file is the file keeping the admin token of the tracker, required to import.")
+	return &c
+}
+
+func (is *ImportStateCommand) Name() string {
+	return is.fs.Name()
+}
+
+func (is *ImportStateCommand) Init(args []string) error {
+	return is.fs.Parse(args)
+}
+
+func (is *ImportStateCommand) Run() error {
+	if (is.tracker == "" && is.ring == "") || is.in == "" || is.adminToken == "" {
+		return errors.New("'tracker' or 'ring', 'in' and 'admin-token-file' are required args")
+	}
+
+	if is.ring != "" {
+		return is.importRing()
+	}
+
+	snapshot, err := os.ReadFile(is.in)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	trackerConn, err := grpc.NewClient(is.tracker, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("new tracker client: %w", err)
+	}
+	defer trackerConn.Close()
+
+	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	ctx, err = withAdminToken(ctx, is.adminToken)
+	if err != nil {
+		return err
+	}
+
+	resp, err := trackerClient.ImportState(ctx, &tracker.ImportStateRequest{Snapshot: snapshot})
+	if err != nil {
+		return fmt.Errorf("import state: %w", err)
+	}
+
+	fmt.Printf("imported peers[%d]\n", resp.GetPeerCount())
+	return nil
+}
+
+// importRing loads every shard with the snapshot export wrote for it.
+func (is *ImportStateCommand) importRing() error {
+	shards, err := dialRing(is.ring, "")
+	if err != nil {
+		return err
+	}
+	defer shards.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	ctx, err = withAdminToken(ctx, is.adminToken)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range shards.ring.Shards() {
+		snapshot, err := os.ReadFile(shardFile(is.in, addr))
+		if err != nil {
+			return fmt.Errorf("read snapshot: %w", err)
+		}
+
+		resp, err := shards.clients[addr].ImportState(ctx, &tracker.ImportStateRequest{Snapshot: snapshot})
+		if err != nil {
+			return fmt.Errorf("import state of shard %s: %w", addr, err)
+		}
+		fmt.Printf("imported peers[%d] into shard[%s]\n", resp.GetPeerCount(), addr)
+	}
+	return nil
+}
+
+// DiffSnapshotsCommand represents a command and all required info to compare two snapshots.
+type DiffSnapshotsCommand struct {
+	fs  *flag.FlagSet
+	old string
+	new string
+}
+
+func NewDiffSnapshotsCommand() *DiffSnapshotsCommand {
+	c := DiffSnapshotsCommand{
+		fs: flag.NewFlagSet("diff", flag.ContinueOnError),
+	}
+
+	//set all args
+	c.fs.StringVar(&c.old, "old", "", "old is the earlier snapshot file.")
+	c.fs.StringVar(&c.new, "new", "", "new is the later snapshot file.")
+	return &c
+}
+
+func (ds *DiffSnapshotsCommand) Name() string {
+	return ds.fs.Name()
+}
+
+func (ds *DiffSnapshotsCommand) Init(args []string) error {
+	return ds.fs.Parse(args)
+}
+
+func (ds *DiffSnapshotsCommand) Run() error {
+	if ds.old == "" || ds.new == "" {
+		return errors.New("'old' and 'new' are required args")
+	}
+
+	oldSnap, err := readSnapshot(ds.old)
+	if err != nil {
+		return err
+	}
+	newSnap, err := readSnapshot(ds.new)
+	if err != nil {
+		return err
+	}
+
+	diff := peerstore.DiffSnapshots(oldSnap, newSnap)
+	if diff.Empty() {
+		fmt.Println("snapshots hold the same peers and files")
+		return nil
+	}
+
+	fmt.Println("============================================================")
+	fmt.Printf("peers added[%d]\n", len(diff.AddedPeers))
+	for _, host := range diff.AddedPeers {
+		fmt.Printf("\tpeer[%s]\n", host)
+	}
+	fmt.Printf("peers removed[%d]\n", len(diff.RemovedPeers))
+	for _, host := range diff.RemovedPeers {
+		fmt.Printf("\tpeer[%s]\n", host)
+	}
+
+	fmt.Println("============================================================")
+	fmt.Printf("files added[%d]\n", len(diff.AddedFiles))
+	for _, c := range diff.AddedFiles {
+		fmt.Printf("\tpeer[%s] file[%s]----> %s\n", c.Host, c.File.Name, c.File.Checksum)
+	}
+	fmt.Printf("files removed[%d]\n", len(diff.RemovedFiles))
+	for _, c := range diff.RemovedFiles {
+		fmt.Printf("\tpeer[%s] file[%s]----> %s\n", c.Host, c.File.Name, c.File.Checksum)
+	}
+	fmt.Printf("files changed[%d]\n", len(diff.ChangedFiles))
+	for _, c := range diff.ChangedFiles {
+		fmt.Printf("\tpeer[%s] file[%s]----> %s => %s\n", c.Host, c.File.Name, c.OldChecksum, c.File.Checksum)
+	}
+	fmt.Println("============================================================")
+	return nil
+}
+
+// adminTokenKey is the metadata key trackers read the admin token from.
+const adminTokenKey = "admin-token"
+
+// withAdminToken returns ctx sending the admin token kept in the file at path, ctx as
+// is without a path.
+func withAdminToken(ctx context.Context, path string) (context.Context, error) {
+	if path == "" {
+		return ctx, nil
+	}
+
+	token, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read admin token: %w", err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, adminTokenKey, strings.TrimSpace(string(token))), nil
+}
+
+func readSnapshot(path string) (peerstore.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return peerstore.Snapshot{}, fmt.Errorf("read snapshot: %w", err)
+	}
+
+	var snap peerstore.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return peerstore.Snapshot{}, fmt.Errorf("unmarshal snapshot %s: %w", path, err)
+	}
+
+	if snap.Version != peerstore.SnapshotVersion {
+		return peerstore.Snapshot{}, fmt.Errorf("unsupported version %d of snapshot %s", snap.Version, path)
+	}
+	return snap, nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
+	"google.golang.org/grpc"
+)
+
+// GetStatsCommand represents a command and all required info to show statistics of the network.
+type GetStatsCommand struct {
+	fs        *flag.FlagSet
+	tracker   string
+	namespace string
+	top       int
+}
+
+func NewGetStatsCommand() *GetStatsCommand {
+	c := GetStatsCommand{
+		fs: flag.NewFlagSet("stats", flag.ContinueOnError),
+	}
+
+	//set all args
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service.")
+	c.fs.StringVar(&c.namespace, "namespace", "", "namespace is the swarm to look into, a comma separated list for several, the default one when empty.")
+	c.fs.IntVar(&c.top, "top", 10, "top is the number of most looked up files to show.")
+	return &c
+}
+
+func (gs *GetStatsCommand) Name() string {
+	return gs.fs.Name()
+}
+
+func (gs *GetStatsCommand) Init(args []string) error {
+	return gs.fs.Parse(args)
+}
+
+func (gs *GetStatsCommand) Run() error {
+	if gs.tracker == "" {
+		return errors.New("'tracker' is required arg")
+	}
+
+	trackerConn, err := grpc.NewClient(gs.tracker, trackerOptions(gs.namespace)...)
+	if err != nil {
+		return fmt.Errorf("new tracker client: %w", err)
+	}
+	defer trackerConn.Close()
+
+	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := trackerClient.GetStats(ctx, &tracker.GetStatsRequest{TopLookups: int32(gs.top)})
+	if err != nil {
+		return fmt.Errorf("get stats: %w", err)
+	}
+
+	fmt.Printf("peers[%d] files[%d] total-bytes[%d] unique-bytes[%d]\n", resp.GetPeerCount(), resp.GetFileCount(), resp.GetTotalBytes(), resp.GetUniqueBytes())
+
+	fmt.Println("============================================================")
+	fmt.Println("replicas")
+	for _, bucket := range resp.Replicas {
+		fmt.Printf("\treplicas[%d]----> %d files\n", bucket.GetReplicas(), bucket.GetFileCount())
+	}
+
+	fmt.Println("============================================================")
+	fmt.Printf("single replica files[%d]\n", len(resp.SingleReplicaFiles))
+	for _, file := range resp.SingleReplicaFiles {
+		fmt.Printf("\tfile[%s]----> %s\n", file.GetName(), file.GetChecksum())
+	}
+
+	fmt.Println("============================================================")
+	fmt.Println("most looked up")
+	for _, lookup := range resp.Lookups {
+		fmt.Printf("\tfile[%s]----> %d lookups\n", lookup.GetFileName(), lookup.GetCount())
+	}
+	fmt.Println("============================================================")
+	return nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/client/pb/tracker"
+	"google.golang.org/grpc"
+)
+
+// WatchEventsCommand represents a command and all required info to follow changes of the network.
+type WatchEventsCommand struct {
+	fs        *flag.FlagSet
+	tracker   string
+	namespace string
+	pattern   string
+	match     string
+	host      string
+	cursor    uint64
+}
+
+func NewWatchEventsCommand() *WatchEventsCommand {
+	c := WatchEventsCommand{
+		fs: flag.NewFlagSet("watch", flag.ContinueOnError),
+	}
+
+	//set all args
+	c.fs.StringVar(&c.tracker, "tracker", "", "tracker is the control service.")
+	c.fs.StringVar(&c.namespace, "namespace", "", "namespace is the swarm to look into, a comma separated list for several, the default one when empty.")
+	c.fs.StringVar(&c.pattern, "pattern", "", "pattern to match file names of file events against, empty matches all files.")
+	c.fs.StringVar(&c.match, "match", "substring", "match is how the pattern is matched: substring, prefix or glob.")
+	c.fs.StringVar(&c.host, "host", "", "host only shows the events of this peer.")
+	c.fs.Uint64Var(&c.cursor, "cursor", 0, "cursor resumes after the event with this seq, 0 only shows new events.")
+	return &c
+}
+
+func (we *WatchEventsCommand) Name() string {
+	return we.fs.Name()
+}
+
+func (we *WatchEventsCommand) Init(args []string) error {
+	return we.fs.Parse(args)
+}
+
+func (we *WatchEventsCommand) Run() error {
+	if we.tracker == "" {
+		return errors.New("'tracker' is required arg")
+	}
+
+	modes := map[string]tracker.MatchMode{
+		"substring": tracker.MatchMode_MATCH_MODE_SUBSTRING,
+		"prefix":    tracker.MatchMode_MATCH_MODE_PREFIX,
+		"glob":      tracker.MatchMode_MATCH_MODE_GLOB,
+	}
+	mode, ok := modes[we.match]
+	if !ok {
+		return fmt.Errorf("unknown match mode: %q", we.match)
+	}
+
+	trackerConn, err := grpc.NewClient(we.tracker, trackerOptions(we.namespace)...)
+	if err != nil {
+		return fmt.Errorf("new tracker client: %w", err)
+	}
+	defer trackerConn.Close()
+
+	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
+
+	//watch until interrupted
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	in := tracker.WatchEventsRequest{
+		Pattern:   we.pattern,
+		MatchMode: mode,
+		Host:      we.host,
+		Cursor:    we.cursor,
+	}
+
+	stream, err := trackerClient.WatchEvents(ctx, &in)
+	if err != nil {
+		return fmt.Errorf("watch events: %w", err)
+	}
+
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("recv: %w", err)
+		}
+
+		at := ev.GetTime().AsTime().Local().Format("15:04:05")
+		if file := ev.GetFile(); file != nil {
+			fmt.Printf("[%d] %s %s peer[%s] file[%s]----> %s\n", ev.GetSeq(), at, ev.GetKind(), ev.GetHost(), file.GetName(), file.GetChecksum())
+			continue
+		}
+		fmt.Printf("[%d] %s %s peer[%s]\n", ev.GetSeq(), at, ev.GetKind(), ev.GetHost())
+	}
+}
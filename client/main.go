@@ -24,6 +24,14 @@ func run(args []string) error {
 		cmd.NewDownloadFileCommand(),
 		cmd.NewUploadFileCommand(),
 		cmd.NewGetPeersCommand(),
+		cmd.NewSearchFilesCommand(),
+		cmd.NewWatchEventsCommand(),
+		cmd.NewGetStatsCommand(),
+		cmd.NewListConflictsCommand(),
+		cmd.NewDrainPeerCommand(),
+		cmd.NewExportStateCommand(),
+		cmd.NewImportStateCommand(),
+		cmd.NewDiffSnapshotsCommand(),
 	}
 
 	subcommand := args[0]
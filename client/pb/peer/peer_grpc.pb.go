@@ -19,11 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PeerService_Ping_FullMethodName               = "/proto.PeerService/Ping"
-	PeerService_CheckFileExistence_FullMethodName = "/proto.PeerService/CheckFileExistence"
-	PeerService_GetFileMetadata_FullMethodName    = "/proto.PeerService/GetFileMetadata"
-	PeerService_DownloadFile_FullMethodName       = "/proto.PeerService/DownloadFile"
-	PeerService_UploadFile_FullMethodName         = "/proto.PeerService/UploadFile"
+	PeerService_Ping_FullMethodName                   = "/proto.PeerService/Ping"
+	PeerService_CheckFileExistence_FullMethodName     = "/proto.PeerService/CheckFileExistence"
+	PeerService_GetFileMetadata_FullMethodName        = "/proto.PeerService/GetFileMetadata"
+	PeerService_DownloadFile_FullMethodName           = "/proto.PeerService/DownloadFile"
+	PeerService_DownloadFileByChecksum_FullMethodName = "/proto.PeerService/DownloadFileByChecksum"
+	PeerService_UploadFile_FullMethodName             = "/proto.PeerService/UploadFile"
+	PeerService_ReplicateFile_FullMethodName          = "/proto.PeerService/ReplicateFile"
+	PeerService_ProveStorage_FullMethodName           = "/proto.PeerService/ProveStorage"
 )
 
 // PeerServiceClient is the client API for PeerService service.
@@ -38,8 +41,14 @@ type PeerServiceClient interface {
 	GetFileMetadata(ctx context.Context, in *GetFileMetadataRequest, opts ...grpc.CallOption) (*GetFileMetadataResponse, error)
 	// Download File is used to download a file from another peer chunk by chunk.
 	DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error)
+	// DownloadFileByChecksum is used to download the exact content matching a checksum, whatever its name is.
+	DownloadFileByChecksum(ctx context.Context, in *DownloadFileByChecksumRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error)
 	// UploadFile is used to by client to upload a file into peer.
 	UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse], error)
+	// ReplicateFile is used by the tracker to have the peer pull a copy of a content from other peers, when it has too few replicas.
+	ReplicateFile(ctx context.Context, in *ReplicateFileRequest, opts ...grpc.CallOption) (*ReplicateFileResponse, error)
+	// ProveStorage is used by the tracker to check that the peer really keeps a content it announced, by having it hash a random byte range of it.
+	ProveStorage(ctx context.Context, in *ProveStorageRequest, opts ...grpc.CallOption) (*ProveStorageResponse, error)
 }
 
 type peerServiceClient struct {
@@ -99,9 +108,28 @@ func (c *peerServiceClient) DownloadFile(ctx context.Context, in *DownloadFileRe
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PeerService_DownloadFileClient = grpc.ServerStreamingClient[FileChunk]
 
+func (c *peerServiceClient) DownloadFileByChecksum(ctx context.Context, in *DownloadFileByChecksumRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PeerService_ServiceDesc.Streams[1], PeerService_DownloadFileByChecksum_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadFileByChecksumRequest, FileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_DownloadFileByChecksumClient = grpc.ServerStreamingClient[FileChunk]
+
 func (c *peerServiceClient) UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &PeerService_ServiceDesc.Streams[1], PeerService_UploadFile_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &PeerService_ServiceDesc.Streams[2], PeerService_UploadFile_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +140,26 @@ func (c *peerServiceClient) UploadFile(ctx context.Context, opts ...grpc.CallOpt
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PeerService_UploadFileClient = grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse]
 
+func (c *peerServiceClient) ReplicateFile(ctx context.Context, in *ReplicateFileRequest, opts ...grpc.CallOption) (*ReplicateFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplicateFileResponse)
+	err := c.cc.Invoke(ctx, PeerService_ReplicateFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) ProveStorage(ctx context.Context, in *ProveStorageRequest, opts ...grpc.CallOption) (*ProveStorageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProveStorageResponse)
+	err := c.cc.Invoke(ctx, PeerService_ProveStorage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeerServiceServer is the server API for PeerService service.
 // All implementations must embed UnimplementedPeerServiceServer
 // for forward compatibility.
@@ -124,8 +172,14 @@ type PeerServiceServer interface {
 	GetFileMetadata(context.Context, *GetFileMetadataRequest) (*GetFileMetadataResponse, error)
 	// Download File is used to download a file from another peer chunk by chunk.
 	DownloadFile(*DownloadFileRequest, grpc.ServerStreamingServer[FileChunk]) error
+	// DownloadFileByChecksum is used to download the exact content matching a checksum, whatever its name is.
+	DownloadFileByChecksum(*DownloadFileByChecksumRequest, grpc.ServerStreamingServer[FileChunk]) error
 	// UploadFile is used to by client to upload a file into peer.
 	UploadFile(grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]) error
+	// ReplicateFile is used by the tracker to have the peer pull a copy of a content from other peers, when it has too few replicas.
+	ReplicateFile(context.Context, *ReplicateFileRequest) (*ReplicateFileResponse, error)
+	// ProveStorage is used by the tracker to check that the peer really keeps a content it announced, by having it hash a random byte range of it.
+	ProveStorage(context.Context, *ProveStorageRequest) (*ProveStorageResponse, error)
 	mustEmbedUnimplementedPeerServiceServer()
 }
 
@@ -148,9 +202,18 @@ func (UnimplementedPeerServiceServer) GetFileMetadata(context.Context, *GetFileM
 func (UnimplementedPeerServiceServer) DownloadFile(*DownloadFileRequest, grpc.ServerStreamingServer[FileChunk]) error {
 	return status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
 }
+func (UnimplementedPeerServiceServer) DownloadFileByChecksum(*DownloadFileByChecksumRequest, grpc.ServerStreamingServer[FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadFileByChecksum not implemented")
+}
 func (UnimplementedPeerServiceServer) UploadFile(grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
 }
+func (UnimplementedPeerServiceServer) ReplicateFile(context.Context, *ReplicateFileRequest) (*ReplicateFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplicateFile not implemented")
+}
+func (UnimplementedPeerServiceServer) ProveStorage(context.Context, *ProveStorageRequest) (*ProveStorageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProveStorage not implemented")
+}
 func (UnimplementedPeerServiceServer) mustEmbedUnimplementedPeerServiceServer() {}
 func (UnimplementedPeerServiceServer) testEmbeddedByValue()                     {}
 
@@ -237,6 +300,17 @@ func _PeerService_DownloadFile_Handler(srv interface{}, stream grpc.ServerStream
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PeerService_DownloadFileServer = grpc.ServerStreamingServer[FileChunk]
 
+func _PeerService_DownloadFileByChecksum_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadFileByChecksumRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PeerServiceServer).DownloadFileByChecksum(m, &grpc.GenericServerStream[DownloadFileByChecksumRequest, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_DownloadFileByChecksumServer = grpc.ServerStreamingServer[FileChunk]
+
 func _PeerService_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(PeerServiceServer).UploadFile(&grpc.GenericServerStream[UploadFileChunk, UploadFileResponse]{ServerStream: stream})
 }
@@ -244,6 +318,42 @@ func _PeerService_UploadFile_Handler(srv interface{}, stream grpc.ServerStream)
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PeerService_UploadFileServer = grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]
 
+func _PeerService_ReplicateFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplicateFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).ReplicateFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_ReplicateFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).ReplicateFile(ctx, req.(*ReplicateFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_ProveStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProveStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).ProveStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_ProveStorage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).ProveStorage(ctx, req.(*ProveStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PeerService_ServiceDesc is the grpc.ServiceDesc for PeerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -263,6 +373,14 @@ var PeerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetFileMetadata",
 			Handler:    _PeerService_GetFileMetadata_Handler,
 		},
+		{
+			MethodName: "ReplicateFile",
+			Handler:    _PeerService_ReplicateFile_Handler,
+		},
+		{
+			MethodName: "ProveStorage",
+			Handler:    _PeerService_ProveStorage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -270,6 +388,11 @@ var PeerService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _PeerService_DownloadFile_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "DownloadFileByChecksum",
+			Handler:       _PeerService_DownloadFileByChecksum_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "UploadFile",
 			Handler:       _PeerService_UploadFile_Handler,
@@ -0,0 +1,118 @@
+// Package failover spreads the calls of a gRPC client over several addresses of the
+// same service, like the trackers of a cluster. Calls stick to the address that last
+// answered and move on to the next one while it is unavailable.
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Conn is a client connection failing over between addresses, generated clients are
+// created on top of it like on a *grpc.ClientConn.
+type Conn struct {
+	addrs []string
+	conns []*grpc.ClientConn
+
+	mu sync.Mutex
+	// current is the index of the address that last answered.
+	current int
+}
+
+var _ grpc.ClientConnInterface = (*Conn)(nil)
+
+// New creates a connection to addrs, tried in order. Like grpc.NewClient, it does not
+// connect until the first call.
+func New(addrs []string, opts ...grpc.DialOption) (*Conn, error) {
+	if len(addrs) == 0 {
+		return nil, errors.New("at least one address is required")
+	}
+
+	c := Conn{addrs: addrs}
+	for _, addr := range addrs {
+		conn, err := grpc.NewClient(addr, opts...)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("new client %s: %w", addr, err)
+		}
+		c.conns = append(c.conns, conn)
+	}
+	return &c, nil
+}
+
+// Invoke performs a unary call on the current address, trying the others in order
+// while it is unavailable.
+func (c *Conn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	var err error
+	for _, i := range c.order() {
+		err = c.conns[i].Invoke(ctx, method, args, reply, opts...)
+		if !unavailable(err) || ctx.Err() != nil {
+			c.use(i)
+			return err
+		}
+	}
+	return err
+}
+
+// NewStream opens a stream on the current address, trying the others in order while
+// it is unavailable. Once opened, a stream does not move to another address.
+func (c *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	var (
+		stream grpc.ClientStream
+		err    error
+	)
+	for _, i := range c.order() {
+		stream, err = c.conns[i].NewStream(ctx, desc, method, opts...)
+		if !unavailable(err) || ctx.Err() != nil {
+			c.use(i)
+			return stream, err
+		}
+	}
+	return nil, err
+}
+
+// Addr returns the address that last answered.
+func (c *Conn) Addr() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.addrs[c.current]
+}
+
+// Close closes the connections to every address.
+func (c *Conn) Close() error {
+	var errs []error
+	for _, conn := range c.conns {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// order returns the indexes of the addresses to try, starting with the current one.
+func (c *Conn) order() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	order := make([]int, len(c.conns))
+	for i := range order {
+		order[i] = (c.current + i) % len(c.conns)
+	}
+	return order
+}
+
+func (c *Conn) use(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = i
+}
+
+// unavailable reports whether err means the call should be tried on another address,
+// like when the server is down or is a tracker that does not lead its cluster.
+func unavailable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
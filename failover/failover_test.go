@@ -0,0 +1,120 @@
+package failover_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/failover"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestConn(t *testing.T) {
+	//nothing listens on the first address.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected to listen: %s", err)
+	}
+	down := lis.Addr().String()
+	lis.Close()
+
+	follower, _ := startTracker(t, &trackerMock{follower: true})
+	leaderMock := &trackerMock{}
+	leader, stopLeader := startTracker(t, leaderMock)
+
+	conn, err := failover.New([]string{down, follower, leader}, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("expected to create conn: %s", err)
+	}
+	defer conn.Close()
+	client := tracker.NewTrackerServiceClient(conn)
+
+	//only the leader accepts registrations.
+	if _, err := client.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: "10.0.0.1:50051"}); err != nil {
+		t.Fatalf("expected to register: %s", err)
+	}
+	if conn.Addr() != leader {
+		t.Fatalf("addr=%s, got %s", leader, conn.Addr())
+	}
+
+	//calls stick to the address that answered.
+	if _, err := client.GetPeers(context.Background(), &tracker.GetPeersRequest{}); err != nil {
+		t.Fatalf("expected to get peers: %s", err)
+	}
+	if leaderMock.reads.Load() != 1 {
+		t.Fatalf("expected the leader to serve the read, got %d reads", leaderMock.reads.Load())
+	}
+
+	//once the leader is gone, the follower serves reads and streams.
+	stopLeader()
+	if _, err := client.GetPeers(context.Background(), &tracker.GetPeersRequest{}); err != nil {
+		t.Fatalf("expected to get peers: %s", err)
+	}
+	if conn.Addr() != follower {
+		t.Fatalf("addr=%s, got %s", follower, conn.Addr())
+	}
+
+	stream, err := client.ListPeers(context.Background(), &tracker.ListPeersRequest{})
+	if err != nil {
+		t.Fatalf("expected to list peers: %s", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("expected to receive a peer: %s", err)
+	}
+
+	//no address accepts registrations anymore.
+	_, err = client.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: "10.0.0.1:50051"})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("code=%s, got %s", codes.Unavailable, status.Code(err))
+	}
+}
+
+func TestNewWithoutAddresses(t *testing.T) {
+	if _, err := failover.New(nil); err == nil {
+		t.Fatal("expected an error without addresses")
+	}
+}
+
+// startTracker serves ts on a random port, the returned func stops it.
+func startTracker(t *testing.T, ts *trackerMock) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected to listen: %s", err)
+	}
+
+	server := grpc.NewServer()
+	tracker.RegisterTrackerServiceServer(server, ts)
+	go server.Serve(lis)
+
+	t.Cleanup(server.Stop)
+	return lis.Addr().String(), server.Stop
+}
+
+// ==============================================================================
+// mocks
+type trackerMock struct {
+	tracker.UnimplementedTrackerServiceServer
+	follower bool
+	reads    atomic.Int64
+}
+
+func (tm *trackerMock) RegisterPeer(ctx context.Context, in *tracker.RegisterPeerRequest) (*tracker.RegisterPeerResponse, error) {
+	if tm.follower {
+		return nil, status.Error(codes.Unavailable, "not the leader")
+	}
+	return &tracker.RegisterPeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+}
+
+func (tm *trackerMock) GetPeers(ctx context.Context, in *tracker.GetPeersRequest) (*tracker.GetPeersResponse, error) {
+	tm.reads.Add(1)
+	return &tracker.GetPeersResponse{}, nil
+}
+
+func (tm *trackerMock) ListPeers(in *tracker.ListPeersRequest, stream grpc.ServerStreamingServer[tracker.Peer]) error {
+	return stream.Send(&tracker.Peer{Host: "10.0.0.1:50051"})
+}
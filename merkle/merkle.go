@@ -0,0 +1,96 @@
+// Package merkle builds Merkle trees over the blocks of a content, so a peer can prove
+// it keeps any block of a content against a single root.
+//
+// Leaves are the sha256 of a zero byte followed by a block, inner nodes the sha256 of a
+// one byte followed by both children. A node left without a sibling moves up a level as
+// it is, so the tree of a single block is the leaf of that block.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// Leaf returns the leaf of a block.
+func Leaf(block []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{0})
+	hash.Write(block)
+	return hash.Sum(nil)
+}
+
+// Root returns the root of the tree over the leaves, nil without leaves.
+func Root(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	nodes := leaves
+	for len(nodes) > 1 {
+		nodes = up(nodes)
+	}
+	return nodes[0]
+}
+
+// Proof returns the path from the leaf at index to the root: the siblings met on the way
+// up, from the leaf's own.
+func Proof(leaves [][]byte, index int) [][]byte {
+	var path [][]byte
+
+	nodes := leaves
+	for len(nodes) > 1 {
+		if sibling := index ^ 1; sibling < len(nodes) {
+			path = append(path, nodes[sibling])
+		}
+		nodes = up(nodes)
+		index /= 2
+	}
+	return path
+}
+
+// Verify reports whether leaf is the leaf at index of a tree of count leaves with the
+// given root, along the path returned by Proof.
+func Verify(root []byte, leaf []byte, index int, count int, path [][]byte) bool {
+	if index < 0 || index >= count {
+		return false
+	}
+
+	hash := leaf
+	for ; count > 1; count = (count + 1) / 2 {
+		if sibling := index ^ 1; sibling < count {
+			if len(path) == 0 {
+				return false
+			}
+
+			if index%2 == 0 {
+				hash = node(hash, path[0])
+			} else {
+				hash = node(path[0], hash)
+			}
+			path = path[1:]
+		}
+		index /= 2
+	}
+	return len(path) == 0 && bytes.Equal(hash, root)
+}
+
+// up returns the level of the tree above nodes.
+func up(nodes [][]byte) [][]byte {
+	parents := make([][]byte, 0, (len(nodes)+1)/2)
+	for i := 0; i < len(nodes); i += 2 {
+		if i+1 == len(nodes) {
+			parents = append(parents, nodes[i])
+			continue
+		}
+		parents = append(parents, node(nodes[i], nodes[i+1]))
+	}
+	return parents
+}
+
+func node(left []byte, right []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{1})
+	hash.Write(left)
+	hash.Write(right)
+	return hash.Sum(nil)
+}
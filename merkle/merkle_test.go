@@ -0,0 +1,48 @@
+package merkle_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/merkle"
+)
+
+func TestVerify(t *testing.T) {
+	for count := 1; count <= 9; count++ {
+		t.Run(fmt.Sprintf("%d leaves", count), func(t *testing.T) {
+			leaves := make([][]byte, count)
+			for i := range leaves {
+				leaves[i] = merkle.Leaf([]byte(fmt.Sprintf("block %d", i)))
+			}
+			root := merkle.Root(leaves)
+
+			for i, leaf := range leaves {
+				path := merkle.Proof(leaves, i)
+				if !merkle.Verify(root, leaf, i, count, path) {
+					t.Fatalf("expected leaf %d to verify", i)
+				}
+
+				//the leaf is only valid at its own place.
+				if count > 1 && merkle.Verify(root, leaf, (i+1)%count, count, path) {
+					t.Fatalf("expected leaf %d not to verify at %d", i, (i+1)%count)
+				}
+			}
+
+			forged := merkle.Leaf([]byte("forged"))
+			if merkle.Verify(root, forged, 0, count, merkle.Proof(leaves, 0)) {
+				t.Fatal("expected a forged leaf not to verify")
+			}
+		})
+	}
+
+	//a single block is its own root.
+	leaf := merkle.Leaf([]byte("block"))
+	if root := merkle.Root([][]byte{leaf}); !bytes.Equal(root, leaf) {
+		t.Fatalf("root=%x, got %x", leaf, root)
+	}
+
+	if merkle.Root(nil) != nil {
+		t.Fatal("expected no root without leaves")
+	}
+}
@@ -0,0 +1,90 @@
+// Package namespace separates the swarms sharing a tracker. Callers send the namespaces
+// they belong to in the gRPC metadata of every tracker call, peers are only handed out
+// to callers sharing one of their namespaces. Callers sending none are in the default
+// namespace. Nothing checks the namespaces a caller sends, they scope lookups and do not
+// keep anything secret.
+package namespace
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Key is the metadata key holding the namespaces of a call, one per value.
+	Key = "namespace"
+	// Default is the namespace of callers that do not send any.
+	Default = "default"
+)
+
+// Normalize returns the namespaces sorted and without blanks or duplicates, or the
+// default namespace when none are left.
+func Normalize(namespaces []string) []string {
+	var normalized []string
+	for _, ns := range namespaces {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			normalized = append(normalized, ns)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return []string{Default}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// Parse returns the namespaces of a comma separated list, normalized.
+func Parse(def string) []string {
+	return Normalize(strings.Split(def, ","))
+}
+
+// FromIncomingContext returns the namespaces the caller of a call sent, normalized.
+func FromIncomingContext(ctx context.Context) []string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return Normalize(md.Get(Key))
+}
+
+// AppendToOutgoingContext returns a context sending the namespaces with the calls
+// made with it.
+func AppendToOutgoingContext(ctx context.Context, namespaces ...string) context.Context {
+	kv := make([]string, 0, 2*len(namespaces))
+	for _, ns := range namespaces {
+		kv = append(kv, Key, ns)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// DialOptions returns the options of a client connection sending the namespaces with
+// every call, no options at all when there are none to send.
+func DialOptions(namespaces ...string) []grpc.DialOption {
+	if len(namespaces) == 0 {
+		return nil
+	}
+
+	unary := func(ctx context.Context, method string, req any, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(AppendToOutgoingContext(ctx, namespaces...), method, req, reply, cc, opts...)
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(AppendToOutgoingContext(ctx, namespaces...), desc, cc, method, opts...)
+	}
+
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unary),
+		grpc.WithChainStreamInterceptor(stream),
+	}
+}
+
+// Shares reports whether both lists have a namespace in common.
+func Shares(a []string, b []string) bool {
+	return slices.ContainsFunc(a, func(ns string) bool { return slices.Contains(b, ns) })
+}
+
+// Within reports whether every namespace of a is one of b.
+func Within(a []string, b []string) bool {
+	return !slices.ContainsFunc(a, func(ns string) bool { return !slices.Contains(b, ns) })
+}
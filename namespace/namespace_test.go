@@ -0,0 +1,98 @@
+package namespace_test
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string][]string{
+		"":                      {namespace.Default},
+		" , ":                   {namespace.Default},
+		"team-b,team-a":         {"team-a", "team-b"},
+		" team-a , team-a,,x ":  {"team-a", "x"},
+		"default,team-a":        {"default", "team-a"},
+		"team-a":                {"team-a"},
+		"team-c,team-b,team-a ": {"team-a", "team-b", "team-c"},
+	}
+
+	for def, expected := range tests {
+		if got := namespace.Parse(def); !slices.Equal(got, expected) {
+			t.Errorf("namespaces of %q=%v, got %v", def, expected, got)
+		}
+	}
+
+	if !namespace.Shares([]string{"team-a", "team-b"}, []string{"team-b"}) || namespace.Shares([]string{"team-a"}, []string{"team-b"}) {
+		t.Error("expected lists to share team-b only")
+	}
+
+	if !namespace.Within([]string{"team-a"}, []string{"team-a", "team-b"}) || namespace.Within([]string{"team-a", "team-c"}, []string{"team-a", "team-b"}) {
+		t.Error("expected team-a only to be within team-a and team-b")
+	}
+}
+
+func TestDialOptions(t *testing.T) {
+	tests := map[string]struct {
+		namespaces []string
+		expected   []string
+	}{
+		"none":    {namespaces: nil, expected: []string{namespace.Default}},
+		"single":  {namespaces: []string{"team-a"}, expected: []string{"team-a"}},
+		"several": {namespaces: []string{"team-b", "team-a"}, expected: []string{"team-a", "team-b"}},
+	}
+
+	mock := trackerMock{}
+	addr := startTracker(t, &mock)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, namespace.DialOptions(test.namespaces...)...)
+			conn, err := grpc.NewClient(addr, opts...)
+			if err != nil {
+				t.Fatalf("expected to create client: %s", err)
+			}
+			defer conn.Close()
+
+			if _, err := tracker.NewTrackerServiceClient(conn).GetStats(context.Background(), &tracker.GetStatsRequest{}); err != nil {
+				t.Fatalf("expected to get stats: %s", err)
+			}
+			if !slices.Equal(mock.namespaces, test.expected) {
+				t.Errorf("namespaces=%v, got %v", test.expected, mock.namespaces)
+			}
+		})
+	}
+}
+
+// trackerMock records the namespaces of the last call.
+type trackerMock struct {
+	tracker.UnimplementedTrackerServiceServer
+	namespaces []string
+}
+
+func (m *trackerMock) GetStats(ctx context.Context, in *tracker.GetStatsRequest) (*tracker.GetStatsResponse, error) {
+	m.namespaces = namespace.FromIncomingContext(ctx)
+	return &tracker.GetStatsResponse{}, nil
+}
+
+func startTracker(t *testing.T, mock *trackerMock) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected to listen: %s", err)
+	}
+
+	server := grpc.NewServer()
+	tracker.RegisterTrackerServiceServer(server, mock)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
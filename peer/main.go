@@ -8,16 +8,25 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/hamidoujand/P2P-file-sharing-network/failover"
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/peer"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/service"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/shards"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"github.com/hamidoujand/P2P-file-sharing-network/ring"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// ringWatchInterval is how often the ring definition is checked for changes.
+const ringWatchInterval = 10 * time.Second
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -31,9 +40,13 @@ func run() error {
 		return errors.New("environment variable 'PEER_HOST' is required")
 	}
 
-	trackerHost := os.Getenv("TRACKER_ADDR")
-	if trackerHost == "" {
-		return errors.New("environment variable 'TRACKER_ADDR' is required")
+	//comma separated, calls fail over between the trackers
+	trackerAddrs := os.Getenv("TRACKER_ADDR")
+
+	//ring of tracker shards, replaces TRACKER_ADDR when set
+	ringFile := os.Getenv("TRACKER_RING_FILE")
+	if trackerAddrs == "" && ringFile == "" {
+		return errors.New("environment variable 'TRACKER_ADDR' or 'TRACKER_RING_FILE' is required")
 	}
 
 	listener, err := net.Listen("tcp", host)
@@ -52,28 +65,73 @@ func run() error {
 		}
 	}
 
-	trackerConn, err := grpc.NewClient(trackerHost, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return fmt.Errorf("new tracker client: %w", err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//swarms this peer joins, comma separated, sent with every tracker call
+	trackerOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if namespaces := os.Getenv("PEER_NAMESPACES"); namespaces != "" {
+		trackerOpts = append(trackerOpts, namespace.DialOptions(namespace.Parse(namespaces)...)...)
+	}
+
+	var trackerClient tracker.TrackerServiceClient
+	if ringFile != "" {
+		r, err := ring.Load(ringFile)
+		if err != nil {
+			return fmt.Errorf("environment variable 'TRACKER_RING_FILE': %w", err)
+		}
+
+		shardsClient, err := shards.New(r, trackerOpts...)
+		if err != nil {
+			return fmt.Errorf("new tracker client: %w", err)
+		}
+		defer shardsClient.Close()
+
+		//shards added or removed from the ring get the files they own
+		go shardsClient.WatchRing(ctx, ringFile, ringWatchInterval)
+		trackerClient = shardsClient
+	} else {
+		trackerConn, err := failover.New(strings.Split(trackerAddrs, ","), trackerOpts...)
+		if err != nil {
+			return fmt.Errorf("new tracker client: %w", err)
+		}
+		defer trackerConn.Close()
+		trackerClient = tracker.NewTrackerServiceClient(trackerConn)
 	}
-	trackerClient := tracker.NewTrackerServiceClient(trackerConn)
 
 	fsys := os.DirFS("static")
 
+	//token the tracker handed out, kept outside of the shared files
+	tokenFile := os.Getenv("PEER_TOKEN_FILE")
+	if tokenFile == "" {
+		tokenFile = "peer.token"
+	}
+
+	//topology of this peer, like "region=eu,zone=eu-1,rack=r1"
+	labels, err := parseLabels(os.Getenv("PEER_LABELS"))
+	if err != nil {
+		return fmt.Errorf("environment variable 'PEER_LABELS': %w", err)
+	}
+
 	conf := service.Config{
 		Host:             host,
 		Store:            store,
 		TrackerClient:    trackerClient,
 		DefaultChunkSize: defaultChunk,
 		Fs:               fsys,
+		TokenFile:        tokenFile,
+		Labels:           labels,
 	}
 
-	service, err := service.New(context.Background(), &conf)
+	service, err := service.New(ctx, &conf)
 	if err != nil {
 		return fmt.Errorf("new service: %w", err)
 	}
 	peer.RegisterPeerServiceServer(server, service)
 
+	//keep the lease on tracker alive for the lifetime of the peer
+	go service.RunHeartbeat(ctx)
+
 	shutdownCh := make(chan os.Signal, 1)
 	signal.Notify(shutdownCh, syscall.SIGTERM, syscall.SIGINT)
 
@@ -92,3 +150,20 @@ func run() error {
 		return nil
 	}
 }
+
+// parseLabels parses comma separated key=value pairs.
+func parseLabels(value string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if value == "" {
+		return labels, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", pair)
+		}
+		labels[key] = val
+	}
+	return labels, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// addFile saves the metadata of a new file and announces only that change to the tracker.
+func (s *Service) addFile(ctx context.Context, fm store.FileMetadata) {
+	s.catalogMu.Lock()
+	defer s.catalogMu.Unlock()
+
+	s.store.AddFileMetadata(fm)
+	s.announceLocked(ctx, []store.FileMetadata{fm}, nil)
+}
+
+// removeFile drops the metadata of a file and announces only that change to the tracker.
+func (s *Service) removeFile(ctx context.Context, name string) {
+	s.catalogMu.Lock()
+	defer s.catalogMu.Unlock()
+
+	if err := s.store.RemoveFileMetadata(name); err != nil {
+		return
+	}
+	s.announceLocked(ctx, nil, []string{name})
+}
+
+// announceLocked sends a catalog change to the tracker, falling back to the whole catalog
+// when the tracker missed a change. Callers must hold catalogMu and have already
+// applied the change to the store.
+func (s *Service) announceLocked(ctx context.Context, added []store.FileMetadata, removed []string) {
+	seq, digest := s.store.Catalog()
+	if wait := s.backoff(); wait > 0 {
+		//the digest of the next heartbeat tells the tracker it missed the change.
+		fmt.Printf("peer[%s] rate limited by tracker for %s, the change goes with the next heartbeat\n", s.host, wait.Round(time.Millisecond))
+		return
+	}
+
+	in := tracker.AnnounceChangesRequest{
+		Host:          s.host,
+		Added:         toTrackerFiles(added),
+		Removed:       removed,
+		CatalogSeq:    seq,
+		CatalogDigest: digest,
+		Token:         s.token,
+	}
+
+	resp, err := s.trackerClient.AnnounceChanges(ctx, &in)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			//tracker forgot about this peer, register again with the whole catalog
+			if err := s.registerLocked(ctx); err != nil {
+				fmt.Printf("peer[%s] failed to register again: %s\n", s.host, err)
+			}
+			return
+		}
+		if s.throttled(err) {
+			fmt.Printf("peer[%s] rate limited by tracker, the change goes with the next heartbeat\n", s.host)
+			return
+		}
+		//log the error since that does not concerns the end user
+		fmt.Printf("peer[%s] failed to announce changes to tracker: %s\n", s.host, err)
+		return
+	}
+
+	if resp.ResyncRequired {
+		fmt.Printf("peer[%s] tracker is at seq %d, sending the whole catalog\n", s.host, resp.CatalogSeq)
+		s.syncCatalogLocked(ctx)
+	}
+}
+
+// syncCatalogLocked sends the whole catalog of this peer to the tracker, callers must
+// hold catalogMu.
+func (s *Service) syncCatalogLocked(ctx context.Context) {
+	seq, _ := s.store.Catalog()
+
+	updatePeerReq := &tracker.UpdatePeerRequest{
+		Host:       s.host, //the current peer
+		Files:      toTrackerFiles(s.store.ListFileMetadatas()),
+		CatalogSeq: seq,
+		Token:      s.token,
+	}
+	_, err := s.trackerClient.UpdatePeer(ctx, updatePeerReq)
+	if err != nil {
+		s.throttled(err)
+		//log the error since that does not concerns the end user
+		fmt.Printf("peer[%s] failed to update it's state in tracker: %s\n", s.host, err.Error())
+	}
+}
+
+func toTrackerFiles(fileMetas []store.FileMetadata) []*tracker.File {
+	files := make([]*tracker.File, len(fileMetas))
+	for i, fm := range fileMetas {
+		files[i] = &tracker.File{
+			Name:     fm.Name,
+			Size:     fm.Size,
+			Checksum: fm.Checksum,
+		}
+	}
+	return files
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
+)
+
+// loadMeter keeps track of how busy this peer is serving files to others.
+type loadMeter struct {
+	streams atomic.Int64
+	bytes   atomic.Int64
+
+	// mu guards the bytes sent and the time of the previous report, the rate is
+	// computed between two reports.
+	mu        sync.Mutex
+	lastBytes int64
+	lastAt    time.Time
+}
+
+func newLoadMeter() *loadMeter {
+	return &loadMeter{lastAt: time.Now()}
+}
+
+// begin counts a new download being served, the returned func must be called once it is done.
+func (m *loadMeter) begin() func() {
+	m.streams.Add(1)
+	return func() { m.streams.Add(-1) }
+}
+
+// sent counts bytes sent to another peer or client.
+func (m *loadMeter) sent(n int) {
+	m.bytes.Add(int64(n))
+}
+
+// report returns the current load, the rate covers the time since the previous report.
+func (m *loadMeter) report() *tracker.Load {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	bytes := m.bytes.Load()
+
+	var rate int64
+	if elapsed := now.Sub(m.lastAt).Seconds(); elapsed > 0 {
+		rate = int64(float64(bytes-m.lastBytes) / elapsed)
+	}
+	m.lastBytes = bytes
+	m.lastAt = now
+
+	return &tracker.Load{
+		ActiveStreams:  m.streams.Load(),
+		BytesPerSecond: rate,
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/merkle"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxProofLength caps the byte range a single challenge can have this peer read.
+const maxProofLength = 1 << 20
+
+// ProveStorage hashes the nonce of a challenge followed by a byte range of the content,
+// proving this peer keeps it. Given a block size, it also hands out a block of the content
+// along with its path to the root of the Merkle tree of the content. A content announced
+// but missing from the disk is dropped from the catalog.
+func (s *Service) ProveStorage(_ context.Context, in *peer.ProveStorageRequest) (*peer.ProveStorageResponse, error) {
+	if in.GetChecksum() == "" {
+		return nil, status.Error(codes.InvalidArgument, "checksum is required")
+	}
+	if in.GetOffset() < 0 || in.GetLength() <= 0 || in.GetLength() > maxProofLength {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid range of %d bytes at %d", in.GetLength(), in.GetOffset())
+	}
+	if in.GetBlockSize() < 0 || in.GetBlockSize() > maxProofLength {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid block size of %d bytes", in.GetBlockSize())
+	}
+
+	fm, err := s.store.GetFileByChecksum(in.GetChecksum())
+	if err != nil {
+		if errors.Is(err, store.ErrFileNotFound) {
+			return nil, status.Errorf(codes.NotFound, "content [%s] not found", in.GetChecksum())
+		}
+		return nil, status.Error(codes.Internal, codes.Internal.String())
+	}
+
+	if in.GetOffset()+in.GetLength() > fm.Size {
+		return nil, status.Errorf(codes.OutOfRange, "range of %d bytes at %d is past the end of content [%s]", in.GetLength(), in.GetOffset(), in.GetChecksum())
+	}
+	if size := in.GetBlockSize(); size > 0 && (in.GetBlockIndex() < 0 || in.GetBlockIndex()*size >= fm.Size) {
+		return nil, status.Errorf(codes.OutOfRange, "block %d is past the end of content [%s]", in.GetBlockIndex(), in.GetChecksum())
+	}
+
+	file, err := s.fs.Open(fm.Name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("file [%s] is gone from disk, dropping it from the catalog\n", fm.Name)
+			s.removeFile(context.Background(), fm.Name)
+			return nil, status.Errorf(codes.NotFound, "content [%s] not found", in.GetChecksum())
+		}
+		return nil, status.Error(codes.Internal, codes.Internal.String())
+	}
+	defer file.Close()
+
+	//skip to the range, files of the os can seek right to it.
+	if seeker, ok := file.(io.Seeker); ok {
+		_, err = seeker.Seek(in.GetOffset(), io.SeekStart)
+	} else {
+		_, err = io.CopyN(io.Discard, file, in.GetOffset())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "skip to offset %d: %s", in.GetOffset(), err)
+	}
+
+	hash := sha256.New()
+	hash.Write(in.GetNonce())
+	if _, err := io.CopyN(hash, file, in.GetLength()); err != nil {
+		//a file shorter than announced can not prove anything.
+		return nil, status.Errorf(codes.DataLoss, "read range of content [%s]: %s", in.GetChecksum(), err)
+	}
+
+	resp := peer.ProveStorageResponse{Proof: hex.EncodeToString(hash.Sum(nil))}
+	if in.GetBlockSize() > 0 {
+		if err := s.proveBlock(fm, in, &resp); err != nil {
+			return nil, err
+		}
+	}
+	return &resp, nil
+}
+
+// proveBlock reads the whole content block by block to build its Merkle tree, and hands
+// out the block challenged with its path to the root.
+func (s *Service) proveBlock(fm store.FileMetadata, in *peer.ProveStorageRequest, resp *peer.ProveStorageResponse) error {
+	file, err := s.fs.Open(fm.Name)
+	if err != nil {
+		return status.Error(codes.Internal, codes.Internal.String())
+	}
+	defer file.Close()
+
+	var leaves [][]byte
+	buf := make([]byte, in.GetBlockSize())
+	for remaining := fm.Size; remaining > 0; {
+		block := buf[:min(remaining, in.GetBlockSize())]
+		if _, err := io.ReadFull(file, block); err != nil {
+			return status.Errorf(codes.DataLoss, "read block %d of content [%s]: %s", len(leaves), in.GetChecksum(), err)
+		}
+
+		if int64(len(leaves)) == in.GetBlockIndex() {
+			resp.Block = slices.Clone(block)
+		}
+		leaves = append(leaves, merkle.Leaf(block))
+		remaining -= int64(len(block))
+	}
+
+	resp.Path = merkle.Proof(leaves, int(in.GetBlockIndex()))
+	resp.Root = merkle.Root(leaves)
+	return nil
+}
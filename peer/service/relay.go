@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// fetchFunc starts a download on another peer and returns the metadata of the file
+// it is going to stream.
+type fetchFunc func(ctx context.Context, client peer.PeerServiceClient, p *tracker.Peer) (*peer.FileMetadata, grpc.ServerStreamingClient[peer.FileChunk], error)
+
+// sendFunc forwards a chunk received from another peer, a nil sendFunc only keeps
+// the copy on this peer.
+type sendFunc func(chunk *peer.FileChunk) error
+
+// forward returns the sendFunc streaming chunks to the client of stream.
+func (s *Service) forward(stream grpc.ServerStreamingServer[peer.FileChunk]) sendFunc {
+	return func(chunk *peer.FileChunk) error {
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+		s.load.sent(len(chunk.Data))
+		return nil
+	}
+}
+
+// relay downloads a file from the first peer that can serve it, keeps a copy on this
+// peer and hands every chunk to send at the same time.
+func (s *Service) relay(ctx context.Context, file string, peers []*tracker.Peer, fetch fetchFunc, send sendFunc) error {
+	if len(peers) == 0 {
+		//not found in entire network
+		fmt.Printf("file [%s] not found in entire network\n", file)
+		return status.Errorf(codes.NotFound, "file [%s] not found on entire network", file)
+	}
+
+	fmt.Printf("total number of peers that have file[%s]: %d\n", file, len(peers))
+
+	for _, p := range peers {
+		if p.Host == s.host {
+			continue
+		}
+
+		started, err := s.relayFrom(ctx, p, fetch, send)
+		if err == nil {
+			return nil
+		}
+
+		//a download given up on by our own caller says nothing about the peer.
+		if ctx.Err() == nil {
+			s.report(p.Host, file, err)
+		}
+
+		//once chunks reached the client, switching peers would corrupt its file.
+		if started {
+			return err
+		}
+		fmt.Printf("peer [%s] could not serve file [%s]: %s\n", p.Host, file, err)
+	}
+
+	return status.Errorf(codes.Unavailable, "no peer could serve file [%s]", file)
+}
+
+// relayFrom downloads the file from a single peer, it reports whether any chunk was
+// already sent to the client when it fails.
+func (s *Service) relayFrom(ctx context.Context, p *tracker.Peer, fetch fetchFunc, send sendFunc) (bool, error) {
+	fmt.Printf("trying to ping peer [%s]\n", p.Host)
+	//need a peer client
+	peerConn, err := grpc.NewClient(p.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return false, fmt.Errorf("dial: %w", err)
+	}
+	defer peerConn.Close()
+	peerClient := peer.NewPeerServiceClient(peerConn)
+
+	resp, err := peerClient.Ping(ctx, &peer.PingRequest{Message: "Hi"})
+	if err != nil {
+		return false, fmt.Errorf("ping: %w", err)
+	}
+	if resp.Status != codes.OK.String() {
+		return false, fmt.Errorf("status not ok: %s", resp.Status)
+	}
+
+	meta, remote, err := fetch(ctx, peerClient, p)
+	if err != nil {
+		return false, err
+	}
+
+	name := s.localName(meta)
+	if !validName(name) {
+		return false, status.Errorf(codes.InvalidArgument, "invalid file name [%s]", name)
+	}
+	path := filepath.Join("static", name)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "create: %s", err)
+	}
+
+	//removes the partial copy whenever the relay fails.
+	discard := func() {
+		file.Close()
+		os.Remove(path)
+	}
+
+	bufWriter := bufio.NewWriter(file)
+	hash := sha256.New()
+	started := false
+
+	for {
+		otherPeerChunk, err := remote.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			discard()
+			code := codes.Unavailable
+			if status.Code(err) == codes.DeadlineExceeded {
+				code = codes.DeadlineExceeded
+			}
+			return started, status.Errorf(code, "receive chunk from peer [%s]: %s", p.Host, err)
+		}
+
+		hash.Write(otherPeerChunk.Data)
+		if _, err := bufWriter.Write(otherPeerChunk.Data); err != nil {
+			discard()
+			return started, status.Errorf(codes.Internal, "write: %s", err)
+		}
+
+		//nobody is waiting for the chunks when only copying.
+		if send == nil {
+			continue
+		}
+
+		//also we need to send this chunk to the cli that asked for it
+		chunk := &peer.FileChunk{
+			ChunkNumber: otherPeerChunk.ChunkNumber,
+			Data:        otherPeerChunk.Data,
+			TotalChunks: otherPeerChunk.TotalChunks,
+		}
+
+		started = true
+		if err := send(chunk); err != nil {
+			discard()
+			return started, status.Errorf(codes.Internal, "failed to send chunk[%d]: %s", chunk.ChunkNumber, err)
+		}
+	}
+
+	//flush
+	if err := bufWriter.Flush(); err != nil {
+		discard()
+		return started, status.Errorf(codes.Internal, "flush: %s", err)
+	}
+	//close
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return started, status.Errorf(codes.Internal, "close: %s", err)
+	}
+
+	checksum := fmt.Sprintf("%X", hash.Sum(nil))
+	if checksum != meta.GetChecksum() {
+		os.Remove(path)
+		return started, status.Errorf(codes.DataLoss, "peer [%s] sent content with checksum [%s], expected [%s]", p.Host, checksum, meta.GetChecksum())
+	}
+
+	sfm := store.FileMetadata{
+		Name:     name,
+		Size:     meta.GetSize(),
+		Checksum: checksum,
+	}
+	//save metadata for this file and announce it to tracker
+	s.addFile(context.Background(), sfm)
+	return started, nil
+}
+
+// localName returns the name a downloaded file is stored under, the remote name is
+// kept unless a different content already lives under it on this peer.
+func (s *Service) localName(meta *peer.FileMetadata) string {
+	existing, err := s.store.GetFileMetadata(meta.GetName())
+	if errors.Is(err, store.ErrFileNotFound) || existing.Checksum == meta.GetChecksum() {
+		return meta.GetName()
+	}
+
+	checksum := meta.GetChecksum()
+	return meta.GetName() + "." + checksum[:min(len(checksum), 12)]
+}
+
+// validName reports whether a file name stays right inside the static dir, names come
+// from other peers and the tracker.
+func validName(name string) bool {
+	return filepath.IsLocal(name) && filepath.Base(name) == name
+}
+
+// report tells the tracker that a peer failed to serve a file, when the failure is the
+// fault of the peer.
+func (s *Service) report(host string, file string, err error) {
+	var reason tracker.ReportReason
+	switch status.Code(err) {
+	case codes.DataLoss:
+		reason = tracker.ReportReason_REPORT_REASON_CHECKSUM_MISMATCH
+	case codes.DeadlineExceeded:
+		reason = tracker.ReportReason_REPORT_REASON_TIMEOUT
+	case codes.Unavailable:
+		reason = tracker.ReportReason_REPORT_REASON_REFUSED
+	default:
+		return
+	}
+
+	s.catalogMu.Lock()
+	token := s.token
+	s.catalogMu.Unlock()
+
+	in := tracker.ReportPeerRequest{
+		Host:     host,
+		Reason:   reason,
+		FileName: file,
+	}
+	//until registered, this peer has no token and can only report anonymously.
+	if token != "" {
+		in.Reporter = s.host
+		in.Token = token
+	}
+	if _, err := s.trackerClient.ReportPeer(context.Background(), &in); err != nil {
+		fmt.Printf("peer[%s] failed to report peer [%s]: %s\n", s.host, host, err)
+	}
+}
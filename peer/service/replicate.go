@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/shards"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// trackerTokenKey is the gRPC metadata key the tracker sends the hash of the token of this
+// peer in.
+const trackerTokenKey = "tracker-token"
+
+// ReplicateFile pulls a copy of a content from the given peers and announces it to the
+// tracker, nothing is streamed back to the caller. Only the tracker can ask for copies.
+func (s *Service) ReplicateFile(ctx context.Context, in *peer.ReplicateFileRequest) (*peer.ReplicateFileResponse, error) {
+	if err := s.fromTracker(ctx); err != nil {
+		return nil, err
+	}
+
+	meta := in.GetFile()
+	if meta.GetChecksum() == "" {
+		return nil, status.Error(codes.InvalidArgument, "file checksum is required")
+	}
+	if !validName(meta.GetName()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid file name [%s]", meta.GetName())
+	}
+	if len(in.GetSources()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "sources are required")
+	}
+
+	//a copy may have arrived in the meantime, from a client or a previous request.
+	fm, err := s.store.GetFileByChecksum(meta.GetChecksum())
+	if err == nil {
+		return &peer.ReplicateFileResponse{
+			Success: true,
+			Message: fmt.Sprintf("content already on this peer as [%s]", fm.Name),
+		}, nil
+	}
+	if !errors.Is(err, store.ErrFileNotFound) {
+		return nil, status.Error(codes.Internal, codes.Internal.String())
+	}
+
+	fmt.Printf("replicating content [%s] as [%s]\n", meta.GetChecksum(), meta.GetName())
+
+	fetch := func(ctx context.Context, client peer.PeerServiceClient, _ *tracker.Peer) (*peer.FileMetadata, grpc.ServerStreamingClient[peer.FileChunk], error) {
+		remote, err := client.DownloadFileByChecksum(ctx, &peer.DownloadFileByChecksumRequest{Checksum: meta.GetChecksum()})
+		if err != nil {
+			return nil, nil, fmt.Errorf("init download: %w", err)
+		}
+		return meta, remote, nil
+	}
+
+	sources := make([]*tracker.Peer, len(in.GetSources()))
+	for i, host := range in.GetSources() {
+		sources[i] = &tracker.Peer{Host: host}
+	}
+
+	if err := s.relay(ctx, meta.GetChecksum(), sources, fetch, nil); err != nil {
+		return nil, err
+	}
+
+	return &peer.ReplicateFileResponse{
+		Success: true,
+		Message: codes.OK.String(),
+	}, nil
+}
+
+// fromTracker checks that the caller presents the hash of the token the tracker handed
+// this peer, besides this peer only the tracker knows it.
+func (s *Service) fromTracker(ctx context.Context) error {
+	s.catalogMu.Lock()
+	token := s.token
+	s.catalogMu.Unlock()
+
+	presented := metadata.ValueFromIncomingContext(ctx, trackerTokenKey)
+	if token != "" && len(presented) == 1 {
+		for _, t := range shards.Tokens(token) {
+			sum := sha256.Sum256([]byte(t))
+			if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(presented[0])) == 1 {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.PermissionDenied, "only the tracker can ask for copies")
+}
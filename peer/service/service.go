@@ -2,6 +2,7 @@ package service
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"crypto/sha256"
 	"errors"
@@ -10,17 +11,28 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/peer"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	// DefaultHeartbeatInterval is used until the tracker hands out its own interval.
+	DefaultHeartbeatInterval = 10 * time.Second
+	// RegisterRetryInterval is how often registering is tried again while no tracker
+	// could be reached.
+	RegisterRetryInterval = time.Second
+)
+
 // Service represents all of the rpc service calls.
 type Service struct {
 	peer.UnimplementedPeerServiceServer
@@ -30,6 +42,23 @@ type Service struct {
 	fs               fs.FS
 	dialer           grpc.DialOption
 	host             string
+	labels           map[string]string
+	load             *loadMeter
+
+	mu                sync.RWMutex
+	heartbeatInterval time.Duration
+	// retryAt is when the rate limiter of the tracker lets this peer call again.
+	retryAt time.Time
+
+	// catalogMu serializes changes of the catalog with their announcement, so the
+	// tracker receives them in the order of their sequence numbers.
+	catalogMu sync.Mutex
+	// token proves to the tracker that this peer owns its host, guarded by catalogMu
+	// since every call carrying it is made under that lock.
+	token     string
+	tokenFile string
+	// registered is set once a tracker accepted this peer, guarded by catalogMu.
+	registered bool
 }
 
 type Config struct {
@@ -39,6 +68,12 @@ type Config struct {
 	DefaultChunkSize int64
 	Fs               fs.FS
 	Dialer           grpc.DialOption
+	// TokenFile keeps the tracker token across restarts, so a restarted peer can
+	// register its host again before the old lease expires. Optional.
+	TokenFile string
+	// Labels describe where this peer runs, like its region, zone and rack, so
+	// downloads prefer peers close by. Optional.
+	Labels map[string]string
 }
 
 // New creates a new rpc service.
@@ -48,8 +83,6 @@ func New(ctx context.Context, conf *Config) (*Service, error) {
 		return nil, errors.New("FS can not be nil")
 	}
 
-	var fileMetadata []store.FileMetadata
-
 	//walk the fs
 	walk := func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -98,8 +131,6 @@ func New(ctx context.Context, conf *Config) (*Service, error) {
 		}
 		//add it into store
 		conf.Store.AddFileMetadata(fm)
-
-		fileMetadata = append(fileMetadata, fm)
 		return nil
 	}
 
@@ -107,38 +138,210 @@ func New(ctx context.Context, conf *Config) (*Service, error) {
 		return nil, fmt.Errorf("walk fs: %w", err)
 	}
 
-	files := make([]*tracker.File, len(fileMetadata))
-	for i, fm := range fileMetadata {
-		files[i] = &tracker.File{
-			Name:     fm.Name,
-			Size:     fm.Size,
-			Checksum: fm.Checksum,
+	s := Service{
+		store:             conf.Store,
+		defaultChunkSize:  conf.DefaultChunkSize,
+		trackerClient:     conf.TrackerClient,
+		fs:                conf.Fs,
+		dialer:            conf.Dialer,
+		host:              conf.Host,
+		labels:            conf.Labels,
+		heartbeatInterval: DefaultHeartbeatInterval,
+		tokenFile:         conf.TokenFile,
+		load:              newLoadMeter(),
+	}
+
+	if s.tokenFile != "" {
+		token, err := os.ReadFile(s.tokenFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read token: %w", err)
 		}
+		s.token = strings.TrimSpace(string(token))
 	}
 
+	//an unreachable tracker is no reason not to serve, RunHeartbeat keeps trying. A
+	//rejected registration is, another peer owns the host.
+	if err := s.register(ctx); err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			return nil, err
+		}
+		fmt.Printf("peer[%s] failed to register, retrying in the background: %s\n", s.host, err)
+	}
+
+	return &s, nil
+}
+
+// register announces this peer with all of its files to the tracker.
+func (s *Service) register(ctx context.Context) error {
+	s.catalogMu.Lock()
+	defer s.catalogMu.Unlock()
+	return s.registerLocked(ctx)
+}
+
+// registerLocked registers this peer, callers must hold catalogMu.
+func (s *Service) registerLocked(ctx context.Context) error {
+	seq, _ := s.store.Catalog()
+
 	//register peer with tracker
 	in := &tracker.RegisterPeerRequest{
-		Host:  conf.Host,
-		Files: files,
+		Host:       s.host,
+		Files:      toTrackerFiles(s.store.ListFileMetadatas()),
+		CatalogSeq: seq,
+		Token:      s.token,
+		Labels:     s.labels,
 	}
-	resp, err := conf.TrackerClient.RegisterPeer(ctx, in)
+	resp, err := s.trackerClient.RegisterPeer(ctx, in)
 
 	if err != nil {
-		return nil, fmt.Errorf("rpc:tracker: %w", err)
+		s.throttled(err)
+		return fmt.Errorf("rpc:tracker: %w", err)
 	}
 
 	if resp.StatusCode != int64(codes.OK) {
-		return nil, fmt.Errorf("status: %d", resp.StatusCode)
+		return fmt.Errorf("status: %d", resp.StatusCode)
+	}
+
+	if resp.Token != s.token {
+		s.token = resp.Token
+		if s.tokenFile != "" {
+			if err := os.WriteFile(s.tokenFile, []byte(s.token), 0600); err != nil {
+				return fmt.Errorf("write token: %w", err)
+			}
+		}
+	}
+
+	if resp.HeartbeatIntervalSeconds > 0 {
+		s.mu.Lock()
+		s.heartbeatInterval = time.Duration(resp.HeartbeatIntervalSeconds) * time.Second
+		s.mu.Unlock()
+	}
+	s.registered = true
+	return nil
+}
+
+// isRegistered reports whether a tracker accepted this peer.
+func (s *Service) isRegistered() bool {
+	s.catalogMu.Lock()
+	defer s.catalogMu.Unlock()
+	return s.registered
+}
+
+// RunHeartbeat keeps the lease of this peer alive on the tracker until ctx is done,
+// registering the peer again whenever the tracker no longer knows about it. Until a
+// tracker accepted the peer, registering is tried every RegisterRetryInterval. While
+// the tracker rate limits this peer, it waits as long as the tracker asked.
+func (s *Service) RunHeartbeat(ctx context.Context) {
+	for {
+		s.mu.RLock()
+		interval := s.heartbeatInterval
+		s.mu.RUnlock()
+
+		if !s.isRegistered() {
+			interval = RegisterRetryInterval
+		}
+		interval = max(interval, s.backoff())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		s.heartbeat(ctx)
+	}
+}
+
+// heartbeat sends a single heartbeat along with the catalog digest, so drift between
+// this peer and the tracker is repaired.
+func (s *Service) heartbeat(ctx context.Context) {
+	s.catalogMu.Lock()
+	defer s.catalogMu.Unlock()
+
+	if !s.registered {
+		if err := s.registerLocked(ctx); err != nil {
+			fmt.Printf("peer[%s] failed to register: %s\n", s.host, err)
+		}
+		return
+	}
+
+	seq, digest := s.store.Catalog()
+	in := tracker.HeartbeatRequest{
+		Host:          s.host,
+		CatalogSeq:    seq,
+		CatalogDigest: digest,
+		Token:         s.token,
+		Load:          s.load.report(),
+	}
+
+	resp, err := s.trackerClient.Heartbeat(ctx, &in)
+	if err == nil {
+		if resp.ResyncRequired {
+			fmt.Printf("peer[%s] catalog drifted on tracker, sending it whole\n", s.host)
+			s.syncCatalogLocked(ctx)
+		}
+		return
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		//lease expired on tracker, register again
+		fmt.Printf("peer[%s] not found on tracker, registering again\n", s.host)
+	case codes.PermissionDenied:
+		//the tracker does not know our token, registering again claims the host if it is free
+		fmt.Printf("peer[%s] token rejected by tracker, registering again\n", s.host)
+	case codes.FailedPrecondition:
+		//an operator drained this peer, it stays out of the network until restarted
+		fmt.Printf("peer[%s] was drained from tracker: %s\n", s.host, err)
+		return
+	case codes.ResourceExhausted:
+		s.throttled(err)
+		fmt.Printf("peer[%s] heartbeat rate limited by tracker, retrying in %s\n", s.host, s.backoff().Round(time.Millisecond))
+		return
+	default:
+		fmt.Printf("peer[%s] heartbeat failed: %s\n", s.host, err)
+		return
+	}
+
+	if err := s.registerLocked(ctx); err != nil {
+		fmt.Printf("peer[%s] failed to register again: %s\n", s.host, err)
+	}
+}
+
+// throttled records how long the tracker asked this peer to wait when err is a
+// rejection of its rate limiter, and reports whether it was one.
+func (s *Service) throttled(err error) bool {
+	if status.Code(err) != codes.ResourceExhausted {
+		return false
 	}
 
-	return &Service{
-		store:            conf.Store,
-		defaultChunkSize: conf.DefaultChunkSize,
-		trackerClient:    conf.TrackerClient,
-		fs:               conf.Fs,
-		dialer:           conf.Dialer,
-		host:             conf.Host,
-	}, nil
+	wait, ok := retryAfter(err)
+	if !ok {
+		wait = RegisterRetryInterval
+	}
+
+	s.mu.Lock()
+	s.retryAt = time.Now().Add(wait)
+	s.mu.Unlock()
+	return true
+}
+
+// retryAfter returns how long the tracker asked to wait before retrying a call, or false
+// when err does not tell.
+func retryAfter(err error) (time.Duration, bool) {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+// backoff returns how long this peer still has to wait before calling the tracker
+// again, zero when it is not rate limited.
+func (s *Service) backoff() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return max(time.Until(s.retryAt), 0)
 }
 
 // Ping is used to check the health of the server.
@@ -192,162 +395,127 @@ func (s *Service) GetFileMetadata(ctx context.Context, in *peer.GetFileMetadataR
 
 // DownloadFile handles downloading files chunk by chunk to the client.
 func (s *Service) DownloadFile(in *peer.DownloadFileRequest, stream grpc.ServerStreamingServer[peer.FileChunk]) error {
+	done := s.load.begin()
+	defer done()
+
 	filename := in.GetFileName()
 
 	//check the store for metadata
 	_, err := s.store.GetFileMetadata(filename)
 	if err != nil {
-		if errors.Is(err, store.ErrFileNotFound) {
-			//when the file is not on this peer.
-			fmt.Printf("file [%s] not found on peer[%s]\n", filename, s.host)
-			//try tracker service
-			in := tracker.GetPeersForFileRequest{
-				FileName: filename,
+		if !errors.Is(err, store.ErrFileNotFound) {
+			return status.Error(codes.Internal, codes.Internal.String())
+		}
+
+		//when the file is not on this peer.
+		fmt.Printf("file [%s] not found on peer[%s]\n", filename, s.host)
+		//try tracker service
+		in := tracker.GetPeersForFileRequest{
+			FileName:  filename,
+			Requester: s.host,
+			Labels:    s.labels,
+		}
+
+		peers, err := s.trackerClient.GetPeersForFile(context.Background(), &in)
+		if err != nil {
+			return status.Errorf(codes.Internal, "get peers for file: %s", err)
+		}
+
+		//peers disagree on the content, only relay the most replicated one so chunks of
+		//different contents are never mixed. The peers handed out are capped and may all
+		//keep other versions, the holders of that content are asked for on their own.
+		if versions := peers.GetVersions(); len(versions) > 1 {
+			checksum := versions[0].GetFile().GetChecksum()
+			fmt.Printf("file [%s] has %d versions on the network, relaying checksum [%s]\n", filename, len(versions), checksum)
+
+			holders, err := s.trackerClient.GetPeersForChecksum(context.Background(), &tracker.GetPeersForChecksumRequest{Checksum: checksum, Requester: s.host, Labels: s.labels})
+			if err != nil {
+				return status.Errorf(codes.Internal, "get peers for checksum: %s", err)
 			}
+			return s.relay(stream.Context(), filename, holders.Peers, fetchContent(checksum, filename), s.forward(stream))
+		}
 
-			peers, err := s.trackerClient.GetPeersForFile(context.Background(), &in)
+		fetch := func(ctx context.Context, client peer.PeerServiceClient, _ *tracker.Peer) (*peer.FileMetadata, grpc.ServerStreamingClient[peer.FileChunk], error) {
+			//get file metadata
+			fm, err := client.GetFileMetadata(ctx, &peer.GetFileMetadataRequest{Name: filename})
 			if err != nil {
-				return status.Errorf(codes.Internal, "get peers for file: %s", err)
+				return nil, nil, fmt.Errorf("fetch metadata: %w", err)
 			}
-			if len(peers.Peers) == 0 {
-				//not found in entire network
-				fmt.Printf("file [%s] not found in entire network\n", filename)
-				return status.Errorf(codes.NotFound, "file [%s] not found on entire network", filename)
+
+			downloadIn := peer.DownloadFileRequest{
+				FileName: fm.Metadata.GetName(),
 			}
+			remote, err := client.DownloadFile(ctx, &downloadIn)
+			if err != nil {
+				return nil, nil, fmt.Errorf("init download: %w", err)
+			}
+			return fm.Metadata, remote, nil
+		}
 
-			fmt.Printf("total number of peers that have file[%s]: %d\n", filename, len(peers.Peers))
+		return s.relay(stream.Context(), filename, peers.Peers, fetch, s.forward(stream))
+	}
 
-			for _, p := range peers.Peers {
-				fmt.Printf("trying to ping peer [%s]\n", p.Host)
-				//need a peer client
-				peerConn, err := grpc.NewClient(p.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
-				if err != nil {
-					fmt.Printf("failed to dial peer [%s]: %s", p.Host, err)
-					continue
-				}
-				defer peerConn.Close()
-				peerClient := peer.NewPeerServiceClient(peerConn)
+	//when the file is on this peer
+	fmt.Printf("file [%s] is on this peer\n", filename)
+	return s.sendFile(filename, stream)
+}
 
-				//ping peer
-				in := peer.PingRequest{
-					Message: "Hi",
-				}
+// DownloadFileByChecksum handles downloading the exact content matching a checksum,
+// whatever name this peer or the others keep it under.
+func (s *Service) DownloadFileByChecksum(in *peer.DownloadFileByChecksumRequest, stream grpc.ServerStreamingServer[peer.FileChunk]) error {
+	checksum := in.GetChecksum()
+	if checksum == "" {
+		return status.Error(codes.InvalidArgument, "checksum is required")
+	}
 
-				resp, err := peerClient.Ping(context.Background(), &in)
-				if err != nil {
-					fmt.Printf("ping [%s] failed: %s\n", p.Host, err.Error())
-					continue //continue to next peer that has the file
-				}
-				if resp.Status != codes.OK.String() {
-					fmt.Printf("status [%s] not ok: %s\n", p.Host, resp.Status)
-					continue
-				}
-				//get file metadata
-				getIn := peer.GetFileMetadataRequest{
-					Name: filename,
-				}
-				fm, err := peerClient.GetFileMetadata(context.Background(), &getIn)
-				if err != nil {
-					fmt.Printf("failed to fetch metadata from peer [%s]: %s\n", p.Host, err)
-					continue
-				}
-				//handle download from another peer
-				downloadIn := peer.DownloadFileRequest{
-					FileName: fm.Metadata.GetName(),
-				}
+	done := s.load.begin()
+	defer done()
 
-				anotherPeerStream, err := peerClient.DownloadFile(context.Background(), &downloadIn)
-				if err != nil {
-					fmt.Printf("failed to init download from peer [%s]: %s\n", p.Host, err)
-					continue
-				}
-				path := "static" + "/" + fm.Metadata.GetName()
+	fm, err := s.store.GetFileByChecksum(checksum)
+	if err == nil {
+		fmt.Printf("content [%s] is on this peer as [%s]\n", checksum, fm.Name)
+		return s.sendFile(fm.Name, stream)
+	}
 
-				file, err := os.Create(path)
-				if err != nil {
-					if !os.IsExist(err) {
-						return status.Errorf(codes.Internal, "create: %s", err)
-					}
-					//reset the seeker, since we redownloadin from another peer
-					file.Seek(0, 0)
-				}
+	if !errors.Is(err, store.ErrFileNotFound) {
+		return status.Error(codes.Internal, codes.Internal.String())
+	}
 
-				bufWriter := bufio.NewWriter(file)
-
-				for {
-					otherPeerChunk, err := anotherPeerStream.Recv()
-					if err == io.EOF {
-						//flush
-						if err := bufWriter.Flush(); err != nil {
-							return status.Errorf(codes.Internal, "flush: %s", err)
-						}
-						//close
-						if err := file.Close(); err != nil {
-							return status.Errorf(codes.Internal, "close: %s", err)
-						}
-
-						sfm := store.FileMetadata{
-							Name:     fm.Metadata.GetName(),
-							Size:     fm.Metadata.GetSize(),
-							Checksum: fm.Metadata.GetChecksum(),
-						}
-						//save metadata for this file into the store.
-						s.store.AddFileMetadata(sfm)
-
-						//update this peer on tracker
-						fileMetas := s.store.ListFileMetadatas()
-						trackerFiles := make([]*tracker.File, len(fileMetas))
-						for i, fm := range fileMetas {
-							trackerFiles[i] = &tracker.File{
-								Name:     fm.Name,
-								Size:     fm.Size,
-								Checksum: fm.Checksum,
-							}
-						}
-
-						updatePeerReq := &tracker.UpdatePeerRequest{
-							Host:  s.host, //the current peer
-							Files: trackerFiles,
-						}
-						_, err := s.trackerClient.UpdatePeer(context.Background(), updatePeerReq)
-						if err != nil {
-							//log the error since that does not concerns the end user
-							fmt.Printf("peer[%s] failed to update it's state in tracker: %s", s.host, err.Error())
-						}
-
-						//close the client stream as well
-						return nil
-					}
-
-					if err != nil {
-						fmt.Printf("failed to receive chunk [%d]: %s", otherPeerChunk.ChunkNumber, err)
-						continue
-					}
-
-					if _, err := bufWriter.Write(otherPeerChunk.Data); err != nil {
-						return status.Errorf(codes.Internal, "write: %s", err)
-					}
-
-					//also we need to send this chunk to the cli that asked for it
-					chunk := &peer.FileChunk{
-						ChunkNumber: otherPeerChunk.ChunkNumber,
-						Data:        otherPeerChunk.Data,
-						TotalChunks: otherPeerChunk.TotalChunks,
-					}
-
-					if err := stream.Send(chunk); err != nil {
-						return status.Errorf(codes.Internal, "failed to send chunk[%d]: %s", chunk.ChunkNumber, err)
-					}
-				}
-			}
-		} else {
-			return status.Error(codes.Internal, codes.Internal.String())
+	fmt.Printf("content [%s] not found on peer[%s]\n", checksum, s.host)
+	peers, err := s.trackerClient.GetPeersForChecksum(context.Background(), &tracker.GetPeersForChecksumRequest{Checksum: checksum, Requester: s.host, Labels: s.labels})
+	if err != nil {
+		return status.Errorf(codes.Internal, "get peers for checksum: %s", err)
+	}
+
+	return s.relay(stream.Context(), checksum, peers.Peers, fetchContent(checksum, ""), s.forward(stream))
+}
+
+// fetchContent returns the fetchFunc downloading the exact content from the peers holding
+// it. The copy is kept under name, or under the name the peer keeps it under when empty.
+func fetchContent(checksum string, name string) fetchFunc {
+	return func(ctx context.Context, client peer.PeerServiceClient, p *tracker.Peer) (*peer.FileMetadata, grpc.ServerStreamingClient[peer.FileChunk], error) {
+		if len(p.Files) == 0 {
+			return nil, nil, errors.New("tracker returned no files for peer")
+		}
+
+		//the tracker already knows how this peer named the content
+		meta := &peer.FileMetadata{
+			Name:     cmp.Or(name, p.Files[0].GetName()),
+			Size:     p.Files[0].GetSize(),
+			Checksum: checksum,
+		}
+
+		remote, err := client.DownloadFileByChecksum(ctx, &peer.DownloadFileByChecksumRequest{Checksum: checksum})
+		if err != nil {
+			return nil, nil, fmt.Errorf("init download: %w", err)
 		}
+		return meta, remote, nil
 	}
+}
 
-	//when the file is on this peer
-	fmt.Printf("file [%s] is on this peer\n", filename)
-	file, err := s.fs.Open(in.GetFileName())
+// sendFile streams a file of this peer chunk by chunk.
+func (s *Service) sendFile(filename string, stream grpc.ServerStreamingServer[peer.FileChunk]) error {
+	file, err := s.fs.Open(filename)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return status.Errorf(codes.NotFound, "file %s, not found", filename)
@@ -366,8 +534,8 @@ func (s *Service) DownloadFile(in *peer.DownloadFileRequest, stream grpc.ServerS
 	buffReader := bufio.NewReader(file)
 
 	for chunkNumber := int32(1); chunkNumber <= totalChunks; chunkNumber++ {
-		readBytes, err := buffReader.Read(buffer)
-		if err != nil {
+		readBytes, err := io.ReadFull(buffReader, buffer)
+		if err != nil && err != io.ErrUnexpectedEOF {
 			if err == io.EOF {
 				break //end of the file.
 			}
@@ -384,6 +552,7 @@ func (s *Service) DownloadFile(in *peer.DownloadFileRequest, stream grpc.ServerS
 		if err := stream.Send(chunk); err != nil {
 			return status.Errorf(codes.Internal, "failed to send chunk[%d]: %s", chunkNumber, err)
 		}
+		s.load.sent(readBytes)
 	}
 	return nil
 }
@@ -417,29 +586,8 @@ func (s *Service) UploadFile(stream grpc.ClientStreamingServer[peer.UploadFileCh
 					Checksum: fmt.Sprintf("%X", hash.Sum(nil)),
 				}
 
-				//save metadata for this file
-				s.store.AddFileMetadata(fm)
-
-				//update this peer on tracker
-				fileMetas := s.store.ListFileMetadatas()
-				trackerFiles := make([]*tracker.File, len(fileMetas))
-				for i, fm := range fileMetas {
-					trackerFiles[i] = &tracker.File{
-						Name:     fm.Name,
-						Size:     fm.Size,
-						Checksum: fm.Checksum,
-					}
-				}
-
-				updatePeerReq := &tracker.UpdatePeerRequest{
-					Host:  s.host, //the current peer
-					Files: trackerFiles,
-				}
-				_, err = s.trackerClient.UpdatePeer(context.Background(), updatePeerReq)
-				if err != nil {
-					//log the error since that does not concerns the end user
-					fmt.Printf("peer[%s] failed to update it's state in tracker service: %s", s.host, err.Error())
-				}
+				//save metadata for this file and announce it to tracker
+				s.addFile(context.Background(), fm)
 
 				//close it
 				file.Close()
@@ -459,6 +607,9 @@ func (s *Service) UploadFile(stream grpc.ClientStreamingServer[peer.UploadFileCh
 		if filename == "" {
 			var err error
 			filename = chunk.GetFileName()
+			if !validName(filename) {
+				return status.Errorf(codes.InvalidArgument, "invalid file name [%s]", filename)
+			}
 			filepath := filepath.Join("static", filename)
 			file, err = os.Create(filepath)
 			if err != nil {
@@ -11,18 +11,26 @@ import (
 	"io/fs"
 	"net"
 	"os"
+	"path/filepath"
+	"slices"
 	"sync"
 	"testing"
 	"testing/fstest"
+	"time"
 
+	"github.com/hamidoujand/P2P-file-sharing-network/merkle"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/peer"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/service"
 	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestNewService(t *testing.T) {
@@ -235,9 +243,597 @@ func TestUploadFile(t *testing.T) {
 
 }
 
+func TestRunHeartbeatRegistersAgain(t *testing.T) {
+	trackerClient := setupTrackerClient(t)
+	host := "0.0.0.0:50051"
+
+	fs := fstest.MapFS{
+		"file.txt": &fstest.MapFile{Data: []byte("this is a test file.")},
+	}
+
+	conf := service.Config{
+		Host:             host,
+		Store:            store.New(),
+		TrackerClient:    trackerClient,
+		DefaultChunkSize: 1024,
+		Fs:               fs,
+	}
+	s, err := service.New(context.Background(), &conf)
+	if err != nil {
+		t.Fatalf("failed to create a new peer service: %s", err)
+	}
+
+	//tracker forgets about the peer, like after a lease expiry
+	_, err = trackerClient.UnRegisterPeer(context.Background(), &tracker.UnRegisterPeerRequest{Host: host})
+	if err != nil {
+		t.Fatalf("failed to unregister peer: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	go s.RunHeartbeat(ctx)
+
+	for {
+		resp, err := trackerClient.GetPeers(context.Background(), &tracker.GetPeersRequest{})
+		if err != nil {
+			t.Fatalf("failed to get peers: %s", err)
+		}
+		if len(resp.Peers) == 1 && resp.Peers[0].Host == host {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected peer to register again after a failed heartbeat")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func TestRegisterRetriedInBackground(t *testing.T) {
+	trackerClient := &unavailableTracker{TrackerServiceClient: setupTrackerClient(t), failures: 2}
+	host := "0.0.0.0:50051"
+
+	conf := service.Config{
+		Host:             host,
+		Store:            store.New(),
+		TrackerClient:    trackerClient,
+		DefaultChunkSize: 1024,
+		Fs:               fstest.MapFS{},
+	}
+	s, err := service.New(context.Background(), &conf)
+	if err != nil {
+		t.Fatalf("expected the peer to start without a tracker: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go s.RunHeartbeat(ctx)
+
+	for {
+		resp, err := trackerClient.GetPeers(context.Background(), &tracker.GetPeersRequest{})
+		if err != nil {
+			t.Fatalf("failed to get peers: %s", err)
+		}
+		if len(resp.Peers) == 1 && resp.Peers[0].Host == host {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected peer to register once the tracker is back")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func TestRegisterBacksOffWhenRateLimited(t *testing.T) {
+	const retryAfter = 1500 * time.Millisecond
+	trackerClient := &limitedTracker{TrackerServiceClient: setupTrackerClient(t), retryAfter: retryAfter}
+	host := "0.0.0.0:50051"
+
+	conf := service.Config{
+		Host:             host,
+		Store:            store.New(),
+		TrackerClient:    trackerClient,
+		DefaultChunkSize: 1024,
+		Fs:               fstest.MapFS{},
+	}
+	s, err := service.New(context.Background(), &conf)
+	if err != nil {
+		t.Fatalf("expected the peer to start while rate limited: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go s.RunHeartbeat(ctx)
+
+	for {
+		resp, err := trackerClient.GetPeers(context.Background(), &tracker.GetPeersRequest{})
+		if err != nil {
+			t.Fatalf("failed to get peers: %s", err)
+		}
+		if len(resp.Peers) == 1 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("expected peer to register once the tracker lets it")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	//the peer waited as long as the tracker asked, not RegisterRetryInterval.
+	calls := trackerClient.registrations()
+	if len(calls) != 2 {
+		t.Fatalf("registrations=2, got %d", len(calls))
+	}
+	if waited := calls[1].Sub(calls[0]); waited < retryAfter-100*time.Millisecond {
+		t.Fatalf("expected the peer to wait %s before registering again, waited %s", retryAfter, waited)
+	}
+}
+
+func TestTokenFile(t *testing.T) {
+	trackerClient := setupTrackerClient(t)
+	host := "0.0.0.0:50051"
+	tokenFile := filepath.Join(t.TempDir(), "peer.token")
+
+	conf := service.Config{
+		Host:             host,
+		Store:            store.New(),
+		TrackerClient:    trackerClient,
+		DefaultChunkSize: 1024,
+		Fs:               fstest.MapFS{},
+		TokenFile:        tokenFile,
+	}
+	if _, err := service.New(context.Background(), &conf); err != nil {
+		t.Fatalf("failed to create a new peer service: %s", err)
+	}
+
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		t.Fatalf("expected token to be saved: %s", err)
+	}
+	if string(token) != "token-1" {
+		t.Fatalf("token=%s, got %s", "token-1", token)
+	}
+
+	//a restarted peer claims its host back with the saved token
+	conf.Store = store.New()
+	if _, err := service.New(context.Background(), &conf); err != nil {
+		t.Fatalf("expected restarted peer to register again: %s", err)
+	}
+
+	//without it the host is still owned by the first registration
+	conf.Store = store.New()
+	conf.TokenFile = ""
+	_, err = service.New(context.Background(), &conf)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("status=%s, got %v", codes.PermissionDenied, err)
+	}
+}
+
+func TestDownloadFileByChecksum(t *testing.T) {
+	data := []byte("this is a test file.")
+	fs := fstest.MapFS{
+		"local-name.txt": &fstest.MapFile{Data: data},
+	}
+
+	peerClient := setupPeerClient(t, fs)
+
+	stream, err := peerClient.DownloadFileByChecksum(context.Background(), &peer.DownloadFileByChecksumRequest{Checksum: checksumOf(data)})
+	if err != nil {
+		t.Fatalf("failed to download file: %s", err)
+	}
+
+	received, err := receiveAll(stream)
+	if err != nil {
+		t.Fatalf("failed to receive file: %s", err)
+	}
+
+	if !bytes.Equal(received, data) {
+		t.Fatal("expected the downloaded file to have same content as original file")
+	}
+
+	//unknown content
+	stream, err = peerClient.DownloadFileByChecksum(context.Background(), &peer.DownloadFileByChecksumRequest{Checksum: "unknown"})
+	if err != nil {
+		t.Fatalf("failed to download file: %s", err)
+	}
+
+	_, err = receiveAll(stream)
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("status=%s, got %s", codes.NotFound, status.Code(err))
+	}
+}
+
+// when another peer has the content under a different name.
+func TestRelayFileByChecksum(t *testing.T) {
+	staticDir := "static"
+	if err := os.MkdirAll(staticDir, 0755); err != nil {
+		t.Fatalf("failed to create static dir: %s", err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(staticDir)
+	})
+
+	data := make([]byte, 12*1024)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("failed to randomly generate bytes: %s", err)
+	}
+	checksum := checksumOf(data)
+
+	trackerClient := setupTrackerClient(t)
+	startPeer(t, trackerClient, fstest.MapFS{"remote-name.bin": &fstest.MapFile{Data: data}})
+	peerClient := startPeer(t, trackerClient, fstest.MapFS{})
+
+	stream, err := peerClient.DownloadFileByChecksum(context.Background(), &peer.DownloadFileByChecksumRequest{Checksum: checksum})
+	if err != nil {
+		t.Fatalf("failed to download file: %s", err)
+	}
+
+	received, err := receiveAll(stream)
+	if err != nil {
+		t.Fatalf("failed to receive file: %s", err)
+	}
+
+	if !bytes.Equal(received, data) {
+		t.Fatal("expected the relayed file to have same content as original file")
+	}
+
+	//the relaying peer keeps a copy under the remote name
+	meta, err := peerClient.GetFileMetadata(context.Background(), &peer.GetFileMetadataRequest{Name: "remote-name.bin"})
+	if err != nil {
+		t.Fatalf("expected relayed file to be stored: %s", err)
+	}
+
+	if meta.Metadata.Checksum != checksum {
+		t.Fatalf("checksum=[%s], got [%s]", checksum, meta.Metadata.Checksum)
+	}
+}
+
+func TestRelayMostReplicatedVersion(t *testing.T) {
+	staticDir := "static"
+	if err := os.MkdirAll(staticDir, 0755); err != nil {
+		t.Fatalf("failed to create static dir: %s", err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(staticDir)
+	})
+
+	common := []byte("the version most peers keep.")
+	rare := []byte("the version a single peer keeps.")
+
+	mock := newTrackerServiceMock()
+	mock.maxPeers = 1
+	trackerClient := serveTracker(t, mock)
+
+	//the single peer handed out keeps the other version.
+	startPeer(t, trackerClient, fstest.MapFS{"file.txt": &fstest.MapFile{Data: common}})
+	startPeer(t, trackerClient, fstest.MapFS{"file.txt": &fstest.MapFile{Data: common}})
+	startPeer(t, trackerClient, fstest.MapFS{"file.txt": &fstest.MapFile{Data: rare}})
+	peerClient := startPeer(t, trackerClient, fstest.MapFS{})
+
+	stream, err := peerClient.DownloadFile(context.Background(), &peer.DownloadFileRequest{FileName: "file.txt"})
+	if err != nil {
+		t.Fatalf("failed to download file: %s", err)
+	}
+
+	received, err := receiveAll(stream)
+	if err != nil {
+		t.Fatalf("failed to receive file: %s", err)
+	}
+	if !bytes.Equal(received, common) {
+		t.Fatalf("expected the most replicated version, got %q", received)
+	}
+}
+
+// when the tracker asks a peer for one more replica.
+func TestReplicateFile(t *testing.T) {
+	staticDir := "static"
+	if err := os.MkdirAll(staticDir, 0755); err != nil {
+		t.Fatalf("failed to create static dir: %s", err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(staticDir)
+	})
+
+	data := make([]byte, 12*1024)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("failed to randomly generate bytes: %s", err)
+	}
+	checksum := checksumOf(data)
+
+	trackerClient := setupTrackerClient(t)
+	startPeer(t, trackerClient, fstest.MapFS{"file.bin": &fstest.MapFile{Data: data}})
+	peerClient := startPeer(t, trackerClient, fstest.MapFS{})
+
+	holders, err := trackerClient.GetPeersForChecksum(context.Background(), &tracker.GetPeersForChecksumRequest{Checksum: checksum})
+	if err != nil || len(holders.Peers) != 1 {
+		t.Fatalf("expected a single peer to hold the file: %v", err)
+	}
+
+	in := peer.ReplicateFileRequest{
+		File: &peer.FileMetadata{Name: "file.bin", Size: int64(len(data)), Checksum: checksum},
+		//the first source is gone, the next one is tried
+		Sources: []string{"127.0.0.1:1", holders.Peers[0].Host},
+	}
+	//only the tracker knows the hash of the token of the peer
+	_, err = peerClient.ReplicateFile(context.Background(), &in)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected replication without a token to fail with %s, got %v", codes.PermissionDenied, err)
+	}
+	_, err = peerClient.ReplicateFile(fromTracker("token-1"), &in)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected replication with the token of another peer to fail with %s, got %v", codes.PermissionDenied, err)
+	}
+
+	ctx := fromTracker("token-2")
+	resp, err := peerClient.ReplicateFile(ctx, &in)
+	if err != nil {
+		t.Fatalf("failed to replicate file: %s", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected replication to succeed: %s", resp.Message)
+	}
+
+	meta, err := peerClient.GetFileMetadata(context.Background(), &peer.GetFileMetadataRequest{Name: "file.bin"})
+	if err != nil {
+		t.Fatalf("expected replicated file to be stored: %s", err)
+	}
+	if meta.Metadata.Checksum != checksum {
+		t.Fatalf("checksum=[%s], got [%s]", checksum, meta.Metadata.Checksum)
+	}
+
+	//the copy is announced to the tracker
+	holders, err = trackerClient.GetPeersForChecksum(context.Background(), &tracker.GetPeersForChecksumRequest{Checksum: checksum})
+	if err != nil {
+		t.Fatalf("failed to get peers for checksum: %s", err)
+	}
+	if len(holders.Peers) != 2 {
+		t.Fatalf("replicas=2, got %d", len(holders.Peers))
+	}
+
+	_, err = peerClient.ReplicateFile(ctx, &peer.ReplicateFileRequest{File: in.File})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected replication without sources to fail with %s, got %v", codes.InvalidArgument, err)
+	}
+
+	//names out of the static dir are rejected
+	escape := peer.ReplicateFileRequest{
+		File:    &peer.FileMetadata{Name: "../../file.bin", Size: int64(len(data)), Checksum: checksum},
+		Sources: in.Sources,
+	}
+	_, err = peerClient.ReplicateFile(ctx, &escape)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected replication of [%s] to fail with %s, got %v", escape.File.Name, codes.InvalidArgument, err)
+	}
+}
+
+// fromTracker returns a context carrying the hash of a peer token, like the tracker sends
+// when asking for copies.
+func fromTracker(token string) context.Context {
+	sum := sha256.Sum256([]byte(token))
+	return metadata.AppendToOutgoingContext(context.Background(), "tracker-token", hex.EncodeToString(sum[:]))
+}
+
+func TestUploadAnnouncesChanges(t *testing.T) {
+	staticDir := "static"
+	if err := os.MkdirAll(staticDir, 0755); err != nil {
+		t.Fatalf("failed to create static dir: %s", err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(staticDir)
+	})
+
+	trackerClient := setupTrackerClient(t)
+	peerClient := startPeer(t, trackerClient, fstest.MapFS{
+		"file.txt": &fstest.MapFile{Data: []byte("this is a test file.")},
+	})
+
+	upload(t, peerClient, "file2.txt", []byte("second file"))
+	expectFiles(t, trackerClient, "file.txt", "file2.txt")
+
+	//tracker loses track of the catalog, next announcement falls back to a full update
+	peers, err := trackerClient.GetPeers(context.Background(), &tracker.GetPeersRequest{})
+	if err != nil {
+		t.Fatalf("failed to get peers: %s", err)
+	}
+	host := peers.Peers[0].Host
+	_, err = trackerClient.UpdatePeer(context.Background(), &tracker.UpdatePeerRequest{Host: host, CatalogSeq: 1000})
+	if err != nil {
+		t.Fatalf("failed to update peer: %s", err)
+	}
+
+	upload(t, peerClient, "file3.txt", []byte("third file"))
+	expectFiles(t, trackerClient, "file.txt", "file2.txt", "file3.txt")
+}
+
+func TestProveStorage(t *testing.T) {
+	data := []byte("this is a test file.")
+	gone := []byte("this file is gone.")
+	fsys := fstest.MapFS{
+		"file.txt": &fstest.MapFile{Data: data},
+		"gone.txt": &fstest.MapFile{Data: gone},
+	}
+
+	trackerClient := setupTrackerClient(t)
+	peerClient := startPeer(t, trackerClient, fsys)
+
+	nonce := []byte("nonce")
+	in := peer.ProveStorageRequest{Checksum: checksumOf(data), Offset: 5, Length: 7, Nonce: nonce}
+	resp, err := peerClient.ProveStorage(context.Background(), &in)
+	if err != nil {
+		t.Fatalf("expected to prove storage: %s", err)
+	}
+
+	sum := sha256.Sum256(append(slices.Clone(nonce), data[5:12]...))
+	if resp.Proof != hex.EncodeToString(sum[:]) {
+		t.Fatalf("proof=%s, got %s", hex.EncodeToString(sum[:]), resp.Proof)
+	}
+	if resp.Root != nil {
+		t.Fatalf("expected no root without a block size, got %x", resp.Root)
+	}
+
+	//the block comes along with its path to the root of the tree of the content.
+	in.BlockSize, in.BlockIndex = 8, 2
+	resp, err = peerClient.ProveStorage(context.Background(), &in)
+	if err != nil {
+		t.Fatalf("expected to prove storage: %s", err)
+	}
+
+	leaves := [][]byte{merkle.Leaf(data[:8]), merkle.Leaf(data[8:16]), merkle.Leaf(data[16:])}
+	if !bytes.Equal(resp.Block, data[16:]) {
+		t.Fatalf("block=%q, got %q", data[16:], resp.Block)
+	}
+	if !bytes.Equal(resp.Root, merkle.Root(leaves)) {
+		t.Fatalf("root=%x, got %x", merkle.Root(leaves), resp.Root)
+	}
+	if !merkle.Verify(resp.Root, merkle.Leaf(resp.Block), 2, len(leaves), resp.Path) {
+		t.Fatal("expected the block to verify against the root")
+	}
+
+	tests := map[string]struct {
+		in   *peer.ProveStorageRequest
+		code codes.Code
+	}{
+		"unknown content": {
+			in:   &peer.ProveStorageRequest{Checksum: "unknown", Length: 1},
+			code: codes.NotFound,
+		},
+		"empty range": {
+			in:   &peer.ProveStorageRequest{Checksum: checksumOf(data)},
+			code: codes.InvalidArgument,
+		},
+		"past the end": {
+			in:   &peer.ProveStorageRequest{Checksum: checksumOf(data), Offset: 15, Length: 10},
+			code: codes.OutOfRange,
+		},
+		"block past the end": {
+			in:   &peer.ProveStorageRequest{Checksum: checksumOf(data), Length: 1, BlockSize: 8, BlockIndex: 3},
+			code: codes.OutOfRange,
+		},
+		"block too large": {
+			in:   &peer.ProveStorageRequest{Checksum: checksumOf(data), Length: 1, BlockSize: 1 << 30},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := peerClient.ProveStorage(context.Background(), test.in)
+			if status.Code(err) != test.code {
+				t.Fatalf("code=%s, got %s", test.code, status.Code(err))
+			}
+		})
+	}
+
+	//a file lost from the disk is dropped from the catalog.
+	delete(fsys, "gone.txt")
+	_, err = peerClient.ProveStorage(context.Background(), &peer.ProveStorageRequest{Checksum: checksumOf(gone), Length: 1})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("code=%s, got %s", codes.NotFound, status.Code(err))
+	}
+	expectFiles(t, trackerClient, "file.txt")
+}
+
 // =============================================================================
 // utils
+func upload(t *testing.T, peerClient peer.PeerServiceClient, filename string, data []byte) {
+	stream, err := peerClient.UploadFile(context.Background())
+	if err != nil {
+		t.Fatalf("failed to create stream: %s", err)
+	}
+
+	chunk := peer.UploadFileChunk{ChunkNumber: 1, TotalChunks: 1, FileName: filename, Data: data}
+	if err := stream.Send(&chunk); err != nil {
+		t.Fatalf("failed to send chunk: %s", err)
+	}
+
+	if _, err := stream.CloseAndRecv(); err != nil {
+		t.Fatalf("failed to close stream: %s", err)
+	}
+}
+
+func expectFiles(t *testing.T, trackerClient tracker.TrackerServiceClient, names ...string) {
+	t.Helper()
+	resp, err := trackerClient.GetPeers(context.Background(), &tracker.GetPeersRequest{})
+	if err != nil {
+		t.Fatalf("failed to get peers: %s", err)
+	}
+
+	if len(resp.Peers) != 1 {
+		t.Fatalf("length=%d, got %d", 1, len(resp.Peers))
+	}
+
+	var got []string
+	for _, f := range resp.Peers[0].Files {
+		got = append(got, f.Name)
+	}
+	slices.Sort(got)
+
+	if !slices.Equal(got, names) {
+		t.Fatalf("files=%v, got %v", names, got)
+	}
+}
+
+func checksumOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%X", sum[:])
+}
+
+func receiveAll(stream grpc.ServerStreamingClient[peer.FileChunk]) ([]byte, error) {
+	var received []byte
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return received, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		received = append(received, chunk.Data...)
+	}
+}
+
+// startPeer serves a peer on a real tcp listener, so other peers can dial it by its host.
+func startPeer(t *testing.T, trackerClient tracker.TrackerServiceClient, fsys fs.FS) peer.PeerServiceClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	conf := service.Config{
+		Host:             lis.Addr().String(),
+		Store:            store.New(),
+		TrackerClient:    trackerClient,
+		DefaultChunkSize: 5 * 1024,
+		Fs:               fsys,
+	}
+	svc, err := service.New(context.Background(), &conf)
+	if err != nil {
+		t.Fatalf("failed to create a new peer service: %s", err)
+	}
+
+	server := grpc.NewServer()
+	peer.RegisterPeerServiceServer(server, svc)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create peer conn: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return peer.NewPeerServiceClient(conn)
+}
 func setupTrackerClient(t *testing.T) tracker.TrackerServiceClient {
+	return serveTracker(t, newTrackerServiceMock())
+}
+
+func serveTracker(t *testing.T, ts *trackerServiceMock) tracker.TrackerServiceClient {
 	lis := bufconn.Listen(1024 * 1024)
 	server := grpc.NewServer()
 	bufDialer := func(context.Context, string) (net.Conn, error) {
@@ -252,7 +848,7 @@ func setupTrackerClient(t *testing.T) tracker.TrackerServiceClient {
 		t.Fatalf("failed to create tracker conn: %s", err)
 	}
 
-	tracker.RegisterTrackerServiceServer(server, newTrackerServiceMock())
+	tracker.RegisterTrackerServiceServer(server, ts)
 
 	go func() {
 		if err := server.Serve(lis); err != nil {
@@ -315,27 +911,105 @@ func setupPeerService(t *testing.T, fsys fs.FS) peer.PeerServiceServer {
 
 // ==============================================================================
 // mocks
+
+// unavailableTracker fails the first registrations, like a tracker that is not up yet.
+type unavailableTracker struct {
+	tracker.TrackerServiceClient
+	mu       sync.Mutex
+	failures int
+}
+
+func (ut *unavailableTracker) RegisterPeer(ctx context.Context, in *tracker.RegisterPeerRequest, opts ...grpc.CallOption) (*tracker.RegisterPeerResponse, error) {
+	ut.mu.Lock()
+	if ut.failures > 0 {
+		ut.failures--
+		ut.mu.Unlock()
+		return nil, status.Error(codes.Unavailable, "connection refused")
+	}
+	ut.mu.Unlock()
+	return ut.TrackerServiceClient.RegisterPeer(ctx, in, opts...)
+}
+
+// limitedTracker rate limits the first registration, asking to retry after retryAfter.
+type limitedTracker struct {
+	tracker.TrackerServiceClient
+	retryAfter time.Duration
+	mu         sync.Mutex
+	calls      []time.Time
+}
+
+func (lt *limitedTracker) RegisterPeer(ctx context.Context, in *tracker.RegisterPeerRequest, opts ...grpc.CallOption) (*tracker.RegisterPeerResponse, error) {
+	lt.mu.Lock()
+	lt.calls = append(lt.calls, time.Now())
+	first := len(lt.calls) == 1
+	lt.mu.Unlock()
+
+	if first {
+		st, err := status.New(codes.ResourceExhausted, "rate limit exceeded").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(lt.retryAfter)})
+		if err != nil {
+			return nil, err
+		}
+		return nil, st.Err()
+	}
+	return lt.TrackerServiceClient.RegisterPeer(ctx, in, opts...)
+}
+
+func (lt *limitedTracker) registrations() []time.Time {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	return slices.Clone(lt.calls)
+}
+
 type trackerServiceMock struct {
 	tracker.UnimplementedTrackerServiceServer
-	peers map[string]*tracker.Peer
-	mu    sync.RWMutex
+	peers  map[string]*tracker.Peer
+	seqs   map[string]uint64
+	tokens map[string]string
+	mu     sync.RWMutex
+	// maxPeers caps GetPeersForFile like the tracker does, handing out the holders of
+	// the least replicated versions first.
+	maxPeers int
 }
 
 func newTrackerServiceMock() *trackerServiceMock {
 	return &trackerServiceMock{
-		peers: make(map[string]*tracker.Peer),
+		peers:  make(map[string]*tracker.Peer),
+		seqs:   make(map[string]uint64),
+		tokens: make(map[string]string),
 	}
 }
 
 func (ts *trackerServiceMock) RegisterPeer(ctx context.Context, in *tracker.RegisterPeerRequest) (*tracker.RegisterPeerResponse, error) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
+
+	token := in.Token
+	if _, ok := ts.peers[in.Host]; !ok {
+		token = fmt.Sprintf("token-%d", len(ts.tokens)+1)
+	} else if in.Token != ts.tokens[in.Host] {
+		return nil, status.Errorf(codes.PermissionDenied, "invalid token for peer %s", in.Host)
+	}
+
 	ts.peers[in.Host] = &tracker.Peer{
 		Host:  in.Host,
 		Files: in.Files,
 	}
+	ts.seqs[in.Host] = in.CatalogSeq
+	ts.tokens[in.Host] = token
 
-	return &tracker.RegisterPeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+	return &tracker.RegisterPeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String(), HeartbeatIntervalSeconds: 1, Token: token}, nil
+}
+
+func (ts *trackerServiceMock) Heartbeat(ctx context.Context, in *tracker.HeartbeatRequest) (*tracker.HeartbeatResponse, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	if _, ok := ts.peers[in.Host]; !ok {
+		return nil, status.Errorf(codes.NotFound, "peer %s, not found", in.Host)
+	}
+	if in.Token != ts.tokens[in.Host] {
+		return nil, status.Errorf(codes.PermissionDenied, "invalid token for peer %s", in.Host)
+	}
+	return &tracker.HeartbeatResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
 }
 
 func (ts *trackerServiceMock) UnRegisterPeer(ctx context.Context, in *tracker.UnRegisterPeerRequest) (*tracker.UnRegisterPeerResponse, error) {
@@ -360,10 +1034,46 @@ func (ts *trackerServiceMock) GetPeersForFile(ctx context.Context, in *tracker.G
 	defer ts.mu.RUnlock()
 
 	peerList := []*tracker.Peer{}
+	counts := make(map[string]int)
+	var versions []*tracker.FileSummary
 	for _, peer := range ts.peers {
 		for _, file := range peer.Files {
 			if file.Name == in.FileName {
 				peerList = append(peerList, peer)
+				if counts[file.Checksum] == 0 {
+					versions = append(versions, &tracker.FileSummary{File: file})
+				}
+				counts[file.Checksum]++
+				break
+			}
+		}
+	}
+
+	for _, v := range versions {
+		v.PeerCount = int64(counts[v.File.Checksum])
+	}
+	slices.SortFunc(versions, func(a, b *tracker.FileSummary) int { return int(b.PeerCount - a.PeerCount) })
+	if len(versions) < 2 {
+		versions = nil
+	}
+
+	if ts.maxPeers > 0 {
+		slices.SortFunc(peerList, func(a, b *tracker.Peer) int {
+			return counts[a.Files[0].Checksum] - counts[b.Files[0].Checksum]
+		})
+		peerList = peerList[:min(ts.maxPeers, len(peerList))]
+	}
+	return &tracker.GetPeersResponse{Peers: peerList, Versions: versions}, nil
+}
+func (ts *trackerServiceMock) GetPeersForChecksum(ctx context.Context, in *tracker.GetPeersForChecksumRequest) (*tracker.GetPeersResponse, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	peerList := []*tracker.Peer{}
+	for _, peer := range ts.peers {
+		for _, file := range peer.Files {
+			if file.Checksum == in.Checksum {
+				peerList = append(peerList, &tracker.Peer{Host: peer.Host, Files: []*tracker.File{file}})
 				break
 			}
 		}
@@ -371,6 +1081,7 @@ func (ts *trackerServiceMock) GetPeersForFile(ctx context.Context, in *tracker.G
 
 	return &tracker.GetPeersResponse{Peers: peerList}, nil
 }
+
 func (ts *trackerServiceMock) UpdatePeer(ctx context.Context, in *tracker.UpdatePeerRequest) (*tracker.UpdatePeerResponse, error) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
@@ -378,5 +1089,26 @@ func (ts *trackerServiceMock) UpdatePeer(ctx context.Context, in *tracker.Update
 		Host:  in.Host,
 		Files: in.Files,
 	}
+	ts.seqs[in.Host] = in.CatalogSeq
 	return &tracker.UpdatePeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
 }
+
+func (ts *trackerServiceMock) AnnounceChanges(ctx context.Context, in *tracker.AnnounceChangesRequest) (*tracker.AnnounceChangesResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	p, ok := ts.peers[in.Host]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "peer %s, not found", in.Host)
+	}
+
+	if in.CatalogSeq != ts.seqs[in.Host]+1 {
+		return &tracker.AnnounceChangesResponse{ResyncRequired: true, CatalogSeq: ts.seqs[in.Host]}, nil
+	}
+
+	files := slices.DeleteFunc(slices.Clone(p.Files), func(f *tracker.File) bool {
+		return slices.Contains(in.Removed, f.Name) || slices.ContainsFunc(in.Added, func(a *tracker.File) bool { return a.Name == f.Name })
+	})
+	ts.peers[in.Host] = &tracker.Peer{Host: in.Host, Files: append(files, in.Added...)}
+	ts.seqs[in.Host] = in.CatalogSeq
+	return &tracker.AnnounceChangesResponse{StatusCode: int64(codes.OK), CatalogSeq: in.CatalogSeq}, nil
+}
@@ -0,0 +1,846 @@
+// Package shards routes the tracker calls of a peer to the tracker shards of a ring.
+// Every shard indexes the files whose names it owns: the peer registers with every
+// shard, but each file is only announced to its owner, and lookups by name only ask
+// the owner of the name.
+//
+// Every shard keeps its own catalog sequence, digest and token for the peer. The
+// client tracks them, so the peer keeps talking to it like to a single tracker.
+package shards
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"github.com/hamidoujand/P2P-file-sharing-network/ring"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultPageSize and maxPageSize page merged results like a single tracker does.
+	defaultPageSize = 100
+	maxPageSize     = 1000
+	// defaultTopLookups is the number of most looked up files reported by default.
+	defaultTopLookups = 10
+)
+
+// shard is a tracker of the ring and the part of the catalog of the peer it indexes.
+type shard struct {
+	conn   *grpc.ClientConn
+	client tracker.TrackerServiceClient
+	// token is empty until the shard accepted the peer.
+	token  string
+	seq    uint64
+	files  map[string]store.FileMetadata
+	digest store.Digest
+}
+
+// Client is a tracker client spreading the catalog of a peer over the shards of a ring.
+// Lookups without a file to route by, like SearchFiles, ask every shard and merge the
+// answers.
+type Client struct {
+	opts []grpc.DialOption
+
+	// mu serializes the calls changing the catalog on the shards with ring changes.
+	mu sync.Mutex
+	// register is the last registration of the peer, replayed to shards joining the ring.
+	register *tracker.RegisterPeerRequest
+	// resync is set when the ring changed, the next heartbeat asks the peer for its
+	// whole catalog so every file reaches its new owner.
+	resync bool
+
+	// routeMu guards the ring and the shards, lookups only take it.
+	routeMu sync.RWMutex
+	ring    *ring.Ring
+	shards  map[string]*shard
+}
+
+var _ tracker.TrackerServiceClient = (*Client)(nil)
+
+// New creates a client for the shards of r, like grpc.NewClient it does not connect
+// until the first call.
+func New(r *ring.Ring, opts ...grpc.DialOption) (*Client, error) {
+	c := Client{
+		opts:   opts,
+		ring:   r,
+		shards: make(map[string]*shard),
+	}
+
+	for _, addr := range r.Shards() {
+		sh, err := c.dial(addr)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.shards[addr] = sh
+	}
+	return &c, nil
+}
+
+// Ring returns the current ring.
+func (c *Client) Ring() *ring.Ring {
+	c.routeMu.RLock()
+	defer c.routeMu.RUnlock()
+	return c.ring
+}
+
+// SetRing moves the peer to the shards of r. The peer leaves the shards no longer on
+// the ring right away, its files reach their new owners on the next heartbeat.
+func (c *Client) SetRing(ctx context.Context, r *ring.Ring) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.routeMu.Lock()
+	shards := make(map[string]*shard)
+	var dialed []*shard
+	for _, addr := range r.Shards() {
+		if sh, ok := c.shards[addr]; ok {
+			shards[addr] = sh
+			continue
+		}
+
+		sh, err := c.dial(addr)
+		if err != nil {
+			c.routeMu.Unlock()
+			for _, sh := range dialed {
+				sh.conn.Close()
+			}
+			return err
+		}
+		shards[addr] = sh
+		dialed = append(dialed, sh)
+	}
+
+	var removed []*shard
+	for addr, sh := range c.shards {
+		if _, ok := shards[addr]; !ok {
+			removed = append(removed, sh)
+		}
+	}
+
+	c.ring = r
+	c.shards = shards
+	c.resync = true
+	c.routeMu.Unlock()
+
+	for _, sh := range removed {
+		if sh.token != "" && c.register != nil {
+			in := tracker.UnRegisterPeerRequest{Host: c.register.GetHost(), Token: sh.token}
+			if _, err := sh.client.UnRegisterPeer(ctx, &in); err != nil {
+				log.Printf("unregister from removed shard: %s\n", err)
+			}
+		}
+		sh.conn.Close()
+	}
+	return nil
+}
+
+// WatchRing loads the ring definition at path every interval until ctx is done, and
+// moves the peer to the new shards whenever it changed.
+func (c *Client) WatchRing(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r, err := ring.Load(path)
+		if err != nil {
+			log.Printf("load ring: %s\n", err)
+			continue
+		}
+		if r.Equal(c.Ring()) {
+			continue
+		}
+
+		log.Printf("ring changed to %v, rebalancing\n", r.Shards())
+		if err := c.SetRing(ctx, r); err != nil {
+			log.Printf("set ring: %s\n", err)
+		}
+	}
+}
+
+// Close closes the connections to every shard.
+func (c *Client) Close() error {
+	c.routeMu.Lock()
+	defer c.routeMu.Unlock()
+
+	for _, sh := range c.shards {
+		sh.conn.Close()
+	}
+	return nil
+}
+
+// =============================================================================
+// calls changing the catalog, spread over the shards
+
+// RegisterPeer registers the peer with every shard, along with the files each owns.
+// The token handed back holds the tokens of every shard.
+func (c *Client) RegisterPeer(ctx context.Context, in *tracker.RegisterPeerRequest, opts ...grpc.CallOption) (*tracker.RegisterPeerResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.register = proto.Clone(in).(*tracker.RegisterPeerRequest)
+	c.restoreTokens(in.GetToken())
+
+	resp := tracker.RegisterPeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}
+	parts := c.split(in.GetFiles())
+	for _, addr := range c.Ring().Shards() {
+		r, err := c.registerShard(ctx, addr, parts[addr], opts...)
+		if err != nil {
+			return nil, err
+		}
+		resp.LeaseTtlSeconds = minPositive(resp.LeaseTtlSeconds, r.GetLeaseTtlSeconds())
+		resp.HeartbeatIntervalSeconds = minPositive(resp.HeartbeatIntervalSeconds, r.GetHeartbeatIntervalSeconds())
+	}
+
+	//every shard got the files it owns.
+	c.resync = false
+	resp.Token = c.tokens()
+	return &resp, nil
+}
+
+// Heartbeat renews the lease of the peer on every shard, with the sequence and digest
+// of the part of the catalog each indexes. Shards that lost the peer, or joined the ring
+// since it registered, get the registration again with the files they own. The errors of
+// the other shards are returned together once every shard got its heartbeat.
+func (c *Client) Heartbeat(ctx context.Context, in *tracker.HeartbeatRequest, opts ...grpc.CallOption) (*tracker.HeartbeatResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	resp := tracker.HeartbeatResponse{StatusCode: int64(codes.OK), Message: codes.OK.String(), ResyncRequired: c.resync}
+	var errs []error
+	for _, addr := range c.Ring().Shards() {
+		sh := c.shard(addr)
+
+		req := tracker.HeartbeatRequest{
+			Host:  in.GetHost(),
+			Token: sh.token,
+			Load:  in.GetLoad(),
+		}
+		if in.GetCatalogDigest() != "" {
+			req.CatalogSeq = sh.seq
+			req.CatalogDigest = sh.digest.String()
+		}
+
+		r, err := sh.client.Heartbeat(ctx, &req, opts...)
+		if status.Code(err) == codes.NotFound && c.register != nil {
+			//the lease expired on this shard only, the others keep the peer.
+			reg, err := c.registerShard(ctx, addr, sh.list(), opts...)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			resp.LeaseTtlSeconds = minPositive(resp.LeaseTtlSeconds, reg.GetLeaseTtlSeconds())
+			continue
+		}
+		if err != nil {
+			errs = append(errs, shardError(addr, err))
+			continue
+		}
+		resp.LeaseTtlSeconds = minPositive(resp.LeaseTtlSeconds, r.GetLeaseTtlSeconds())
+		resp.ResyncRequired = resp.ResyncRequired || r.GetResyncRequired()
+	}
+
+	if len(errs) > 0 {
+		//keep the resync asked for by the shards that answered for the next heartbeat.
+		c.resync = resp.ResyncRequired
+		return nil, errors.Join(errs...)
+	}
+
+	//the peer sends its whole catalog next, every file reaches its owner.
+	c.resync = false
+	return &resp, nil
+}
+
+// AnnounceChanges announces every change to the shard owning the file, shards without
+// changes are left alone.
+func (c *Client) AnnounceChanges(ctx context.Context, in *tracker.AnnounceChangesRequest, opts ...grpc.CallOption) (*tracker.AnnounceChangesResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := c.Ring()
+	added := c.split(in.GetAdded())
+	removed := make(map[string][]string)
+	for _, name := range in.GetRemoved() {
+		owner := r.Owner(name)
+		removed[owner] = append(removed[owner], name)
+	}
+
+	resp := tracker.AnnounceChangesResponse{StatusCode: int64(codes.OK), Message: codes.OK.String(), CatalogSeq: in.GetCatalogSeq()}
+	for _, addr := range r.Shards() {
+		if len(added[addr]) == 0 && len(removed[addr]) == 0 {
+			continue
+		}
+
+		sh := c.shard(addr)
+		for _, name := range removed[addr] {
+			sh.remove(name)
+		}
+		for _, file := range added[addr] {
+			sh.remove(file.Name)
+			sh.add(file)
+		}
+		sh.seq++
+
+		req := tracker.AnnounceChangesRequest{
+			Host:       in.GetHost(),
+			Added:      toTrackerFiles(added[addr]),
+			Removed:    removed[addr],
+			CatalogSeq: sh.seq,
+			Token:      sh.token,
+		}
+		if in.GetCatalogDigest() != "" {
+			req.CatalogDigest = sh.digest.String()
+		}
+
+		r, err := sh.client.AnnounceChanges(ctx, &req, opts...)
+		if err != nil {
+			return nil, shardError(addr, err)
+		}
+		if r.GetResyncRequired() {
+			resp.StatusCode = r.GetStatusCode()
+			resp.Message = fmt.Sprintf("shard %s: %s", addr, r.GetMessage())
+			resp.ResyncRequired = true
+		}
+	}
+	return &resp, nil
+}
+
+// UpdatePeer replaces the files of the peer on every shard with the ones it owns.
+// Shards that joined the ring since the peer registered get the registration instead.
+func (c *Client) UpdatePeer(ctx context.Context, in *tracker.UpdatePeerRequest, opts ...grpc.CallOption) (*tracker.UpdatePeerResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	parts := c.split(in.GetFiles())
+	for _, addr := range c.Ring().Shards() {
+		sh := c.shard(addr)
+
+		if sh.token == "" && c.register != nil {
+			if _, err := c.registerShard(ctx, addr, parts[addr], opts...); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sh.set(parts[addr])
+		sh.seq++
+
+		req := tracker.UpdatePeerRequest{
+			Host:       in.GetHost(),
+			Files:      toTrackerFiles(parts[addr]),
+			CatalogSeq: sh.seq,
+			Token:      sh.token,
+		}
+		if _, err := sh.client.UpdatePeer(ctx, &req, opts...); err != nil {
+			return nil, shardError(addr, err)
+		}
+	}
+	return &tracker.UpdatePeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+}
+
+// UnRegisterPeer removes the peer from every shard.
+func (c *Client) UnRegisterPeer(ctx context.Context, in *tracker.UnRegisterPeerRequest, opts ...grpc.CallOption) (*tracker.UnRegisterPeerResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.restoreTokens(in.GetToken())
+
+	var firstErr error
+	for _, addr := range c.Ring().Shards() {
+		sh := c.shard(addr)
+		_, err := sh.client.UnRegisterPeer(ctx, &tracker.UnRegisterPeerRequest{Host: in.GetHost(), Token: sh.token}, opts...)
+		if err != nil && firstErr == nil {
+			firstErr = shardError(addr, err)
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return &tracker.UnRegisterPeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+}
+
+// =============================================================================
+// lookups
+
+// GetPeersForFile asks the shard owning the file name.
+func (c *Client) GetPeersForFile(ctx context.Context, in *tracker.GetPeersForFileRequest, opts ...grpc.CallOption) (*tracker.GetPeersResponse, error) {
+	c.routeMu.RLock()
+	client := c.shards[c.ring.Owner(in.GetFileName())].client
+	c.routeMu.RUnlock()
+
+	return client.GetPeersForFile(ctx, in, opts...)
+}
+
+// GetFileVersions asks the shard owning the file name.
+func (c *Client) GetFileVersions(ctx context.Context, in *tracker.GetFileVersionsRequest, opts ...grpc.CallOption) (*tracker.GetFileVersionsResponse, error) {
+	c.routeMu.RLock()
+	client := c.shards[c.ring.Owner(in.GetFileName())].client
+	c.routeMu.RUnlock()
+
+	return client.GetFileVersions(ctx, in, opts...)
+}
+
+// GetPeersForChecksum asks every shard, the same content may be kept under names
+// owned by different shards. Peers found on several shards are returned once.
+func (c *Client) GetPeersForChecksum(ctx context.Context, in *tracker.GetPeersForChecksumRequest, opts ...grpc.CallOption) (*tracker.GetPeersResponse, error) {
+	var resp tracker.GetPeersResponse
+	for _, addr := range c.Ring().Shards() {
+		r, err := c.shard(addr).client.GetPeersForChecksum(ctx, in, opts...)
+		if err != nil {
+			return nil, shardError(addr, err)
+		}
+
+		for _, p := range r.GetPeers() {
+			if !slices.ContainsFunc(resp.Peers, func(found *tracker.Peer) bool { return found.GetHost() == p.GetHost() }) {
+				resp.Peers = append(resp.Peers, p)
+			}
+		}
+	}
+
+	if in.GetLimit() > 0 && len(resp.Peers) > int(in.GetLimit()) {
+		resp.Peers = resp.Peers[:in.GetLimit()]
+	}
+	return &resp, nil
+}
+
+// =============================================================================
+// calls without a file to route by, the first shard answers them. Every shard knows
+// every peer, but only the files it owns.
+
+func (c *Client) GetPeers(ctx context.Context, in *tracker.GetPeersRequest, opts ...grpc.CallOption) (*tracker.GetPeersResponse, error) {
+	return c.first().GetPeers(ctx, in, opts...)
+}
+
+func (c *Client) ListPeers(ctx context.Context, in *tracker.ListPeersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[tracker.Peer], error) {
+	return c.first().ListPeers(ctx, in, opts...)
+}
+
+func (c *Client) WatchEvents(ctx context.Context, in *tracker.WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[tracker.Event], error) {
+	return c.first().WatchEvents(ctx, in, opts...)
+}
+
+// =============================================================================
+// calls asking every shard, the answers are merged
+
+// SearchFiles searches every shard and pages the merged results like a single tracker.
+// A file found on two shards while it moves between them is returned once.
+func (c *Client) SearchFiles(ctx context.Context, in *tracker.SearchFilesRequest, opts ...grpc.CallOption) (*tracker.SearchFilesResponse, error) {
+	var results []*tracker.FileSummary
+	for _, addr := range c.Ring().Shards() {
+		req := proto.Clone(in).(*tracker.SearchFilesRequest)
+		req.PageSize = maxPageSize
+		req.PageToken = ""
+		for {
+			r, err := c.shard(addr).client.SearchFiles(ctx, req, opts...)
+			if err != nil {
+				return nil, shardError(addr, err)
+			}
+			results = append(results, r.GetFiles()...)
+			if r.GetNextPageToken() == "" {
+				break
+			}
+			req.PageToken = r.GetNextPageToken()
+		}
+	}
+
+	//the copy on the shard knowing the most peers is kept, then results are ordered
+	//like on a single tracker.
+	slices.SortFunc(results, func(a, b *tracker.FileSummary) int {
+		return cmp.Or(
+			cmp.Compare(a.GetFile().GetName(), b.GetFile().GetName()),
+			cmp.Compare(a.GetFile().GetChecksum(), b.GetFile().GetChecksum()),
+			cmp.Compare(b.GetPeerCount(), a.GetPeerCount()),
+		)
+	})
+	results = dedupe(results, func(f *tracker.FileSummary) string {
+		return f.GetFile().GetName() + "\x00" + f.GetFile().GetChecksum()
+	})
+	if in.GetOrder() == tracker.SearchOrder_SEARCH_ORDER_AVAILABILITY {
+		slices.SortStableFunc(results, func(a, b *tracker.FileSummary) int {
+			return cmp.Compare(b.GetPeerCount(), a.GetPeerCount())
+		})
+	}
+
+	files, next, err := page(results, in.GetPageToken(), in.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	return &tracker.SearchFilesResponse{Files: files, NextPageToken: next}, nil
+}
+
+// ListConflicts lists the conflicts of every shard by name, paged like a single tracker.
+func (c *Client) ListConflicts(ctx context.Context, in *tracker.ListConflictsRequest, opts ...grpc.CallOption) (*tracker.ListConflictsResponse, error) {
+	var conflicts []*tracker.Conflict
+	for _, addr := range c.Ring().Shards() {
+		req := tracker.ListConflictsRequest{PageSize: maxPageSize}
+		for {
+			r, err := c.shard(addr).client.ListConflicts(ctx, &req, opts...)
+			if err != nil {
+				return nil, shardError(addr, err)
+			}
+			conflicts = append(conflicts, r.GetConflicts()...)
+			if r.GetNextPageToken() == "" {
+				break
+			}
+			req.PageToken = r.GetNextPageToken()
+		}
+	}
+
+	slices.SortStableFunc(conflicts, func(a, b *tracker.Conflict) int {
+		return cmp.Compare(a.GetFileName(), b.GetFileName())
+	})
+	conflicts = dedupe(conflicts, (*tracker.Conflict).GetFileName)
+
+	conflicts, next, err := page(conflicts, in.GetPageToken(), in.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+	return &tracker.ListConflictsResponse{Conflicts: conflicts, NextPageToken: next}, nil
+}
+
+// GetStats sums the stats of every shard. Every shard knows every peer, so the peer
+// count is the largest one. Contents kept under names owned by different shards are
+// counted by each of them.
+func (c *Client) GetStats(ctx context.Context, in *tracker.GetStatsRequest, opts ...grpc.CallOption) (*tracker.GetStatsResponse, error) {
+	var resp tracker.GetStatsResponse
+	replicas := make(map[int64]int64)
+	lookups := make(map[string]uint64)
+	for _, addr := range c.Ring().Shards() {
+		r, err := c.shard(addr).client.GetStats(ctx, in, opts...)
+		if err != nil {
+			return nil, shardError(addr, err)
+		}
+
+		resp.PeerCount = max(resp.PeerCount, r.GetPeerCount())
+		resp.FileCount += r.GetFileCount()
+		resp.TotalBytes += r.GetTotalBytes()
+		resp.UniqueBytes += r.GetUniqueBytes()
+		resp.SingleReplicaFiles = append(resp.SingleReplicaFiles, r.GetSingleReplicaFiles()...)
+		for _, b := range r.GetReplicas() {
+			replicas[b.GetReplicas()] += b.GetFileCount()
+		}
+		//lookups are counted by the shard owning the name.
+		for _, l := range r.GetLookups() {
+			lookups[l.GetFileName()] += l.GetCount()
+		}
+	}
+
+	for n, files := range replicas {
+		resp.Replicas = append(resp.Replicas, &tracker.ReplicaBucket{Replicas: n, FileCount: files})
+	}
+	slices.SortFunc(resp.Replicas, func(a, b *tracker.ReplicaBucket) int {
+		return cmp.Compare(a.GetReplicas(), b.GetReplicas())
+	})
+
+	for name, count := range lookups {
+		resp.Lookups = append(resp.Lookups, &tracker.FileLookups{FileName: name, Count: count})
+	}
+	slices.SortFunc(resp.Lookups, func(a, b *tracker.FileLookups) int {
+		return cmp.Or(cmp.Compare(b.GetCount(), a.GetCount()), cmp.Compare(a.GetFileName(), b.GetFileName()))
+	})
+	top := int(in.GetTopLookups())
+	if top <= 0 {
+		top = defaultTopLookups
+	}
+	if len(resp.Lookups) > top {
+		resp.Lookups = resp.Lookups[:top]
+	}
+	return &resp, nil
+}
+
+// GetCatalogSummary merges the catalogs of every shard.
+func (c *Client) GetCatalogSummary(ctx context.Context, in *tracker.GetCatalogSummaryRequest, opts ...grpc.CallOption) (*tracker.GetCatalogSummaryResponse, error) {
+	var resp tracker.GetCatalogSummaryResponse
+	for _, addr := range c.Ring().Shards() {
+		r, err := c.shard(addr).client.GetCatalogSummary(ctx, in, opts...)
+		if err != nil {
+			return nil, shardError(addr, err)
+		}
+		resp.Entries = append(resp.Entries, r.GetEntries()...)
+	}
+	return &resp, nil
+}
+
+// =============================================================================
+// calls operators make on every shard on their own, like the client does with a ring
+
+// DrainPeer is not supported, every shard only copies the files it owns and needs its
+// own token of the peer.
+func (c *Client) DrainPeer(ctx context.Context, in *tracker.DrainPeerRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[tracker.DrainProgress], error) {
+	return nil, status.Error(codes.Unimplemented, "drain the peer on every shard")
+}
+
+// ExportState is not supported, every shard has a snapshot of its own.
+func (c *Client) ExportState(ctx context.Context, in *tracker.ExportStateRequest, opts ...grpc.CallOption) (*tracker.ExportStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "export the state of every shard")
+}
+
+// ImportState is not supported, every shard has a snapshot of its own.
+func (c *Client) ImportState(ctx context.Context, in *tracker.ImportStateRequest, opts ...grpc.CallOption) (*tracker.ImportStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "import the state of every shard")
+}
+
+// ReportPeer reports to the shard owning the file, the one handing out its peers, with
+// the token the peer has there. Reports without a file go to the first shard, shards
+// the peer is not registered with get an anonymous report.
+func (c *Client) ReportPeer(ctx context.Context, in *tracker.ReportPeerRequest, opts ...grpc.CallOption) (*tracker.ReportPeerResponse, error) {
+	c.mu.Lock()
+	c.routeMu.RLock()
+	addr := c.ring.Shards()[0]
+	if in.GetFileName() != "" {
+		addr = c.ring.Owner(in.GetFileName())
+	}
+	sh := c.shards[addr]
+	c.routeMu.RUnlock()
+
+	req := proto.Clone(in).(*tracker.ReportPeerRequest)
+	req.Token = sh.token
+	if sh.token == "" {
+		req.Reporter = ""
+	}
+	c.mu.Unlock()
+
+	return sh.client.ReportPeer(ctx, req, opts...)
+}
+
+// =============================================================================
+
+// registerShard registers the peer with a single shard along with files, callers must
+// hold c.mu.
+func (c *Client) registerShard(ctx context.Context, addr string, files []store.FileMetadata, opts ...grpc.CallOption) (*tracker.RegisterPeerResponse, error) {
+	sh := c.shard(addr)
+
+	req := proto.Clone(c.register).(*tracker.RegisterPeerRequest)
+	req.Files = toTrackerFiles(files)
+	req.CatalogSeq = sh.seq
+	req.Token = sh.token
+
+	resp, err := sh.client.RegisterPeer(ctx, req, opts...)
+	if err != nil {
+		return nil, shardError(addr, err)
+	}
+	if resp.GetStatusCode() != int64(codes.OK) {
+		return nil, status.Errorf(codes.Code(resp.GetStatusCode()), "shard %s: %s", addr, resp.GetMessage())
+	}
+
+	sh.token = resp.GetToken()
+	sh.set(files)
+	return resp, nil
+}
+
+// split groups files by the shard owning their name.
+func (c *Client) split(files []*tracker.File) map[string][]store.FileMetadata {
+	r := c.Ring()
+
+	parts := make(map[string][]store.FileMetadata)
+	for _, f := range files {
+		owner := r.Owner(f.GetName())
+		parts[owner] = append(parts[owner], store.FileMetadata{
+			Name:     f.GetName(),
+			Size:     f.GetSize(),
+			Checksum: f.GetChecksum(),
+		})
+	}
+	return parts
+}
+
+// tokens encodes the tokens of every shard into a single one.
+func (c *Client) tokens() string {
+	c.routeMu.RLock()
+	defer c.routeMu.RUnlock()
+
+	values := make(url.Values)
+	for addr, sh := range c.shards {
+		if sh.token != "" {
+			values.Set(addr, sh.token)
+		}
+	}
+	return values.Encode()
+}
+
+// Tokens returns the token of every shard held in a token made by the client, or the
+// token itself when a single tracker handed it out.
+func Tokens(token string) []string {
+	values, err := url.ParseQuery(token)
+	if err != nil {
+		return []string{token}
+	}
+
+	var tokens []string
+	for _, shardTokens := range values {
+		for _, t := range shardTokens {
+			if t != "" {
+				tokens = append(tokens, t)
+			}
+		}
+	}
+	if len(tokens) == 0 {
+		return []string{token}
+	}
+	return tokens
+}
+
+// restoreTokens hands the shards their token from a token made by tokens, like the
+// one a restarted peer saved. Shards that already have a token keep it.
+func (c *Client) restoreTokens(token string) {
+	values, err := url.ParseQuery(token)
+	if err != nil {
+		return
+	}
+
+	c.routeMu.RLock()
+	defer c.routeMu.RUnlock()
+
+	for addr, sh := range c.shards {
+		if sh.token == "" {
+			sh.token = values.Get(addr)
+		}
+	}
+}
+
+func (c *Client) shard(addr string) *shard {
+	c.routeMu.RLock()
+	defer c.routeMu.RUnlock()
+	return c.shards[addr]
+}
+
+func (c *Client) first() tracker.TrackerServiceClient {
+	c.routeMu.RLock()
+	defer c.routeMu.RUnlock()
+	return c.shards[c.ring.Shards()[0]].client
+}
+
+func (c *Client) dial(addr string) (*shard, error) {
+	conn, err := grpc.NewClient(addr, c.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("new client %s: %w", addr, err)
+	}
+
+	sh := shard{
+		conn:   conn,
+		client: tracker.NewTrackerServiceClient(conn),
+		files:  make(map[string]store.FileMetadata),
+	}
+	return &sh, nil
+}
+
+// set replaces the files the shard indexes.
+func (sh *shard) set(files []store.FileMetadata) {
+	sh.files = make(map[string]store.FileMetadata)
+	sh.digest = store.Digest{}
+	for _, file := range files {
+		sh.add(file)
+	}
+}
+
+// list returns the files the shard indexes.
+func (sh *shard) list() []store.FileMetadata {
+	files := make([]store.FileMetadata, 0, len(sh.files))
+	for _, file := range sh.files {
+		files = append(files, file)
+	}
+	return files
+}
+
+func (sh *shard) add(file store.FileMetadata) {
+	sh.files[file.Name] = file
+	sh.digest.Toggle(file)
+}
+
+func (sh *shard) remove(name string) {
+	if file, ok := sh.files[name]; ok {
+		delete(sh.files, name)
+		sh.digest.Toggle(file)
+	}
+}
+
+// shardError tells which shard failed, keeping the status code and details callers act
+// on, like when to retry.
+func shardError(addr string, err error) error {
+	s := status.Convert(err).Proto()
+	s.Message = fmt.Sprintf("shard %s: %s", addr, s.GetMessage())
+	return status.FromProto(s).Err()
+}
+
+// dedupe removes the items with the same key as the one before them, items must be
+// sorted by key.
+func dedupe[T any](items []T, key func(T) string) []T {
+	return slices.CompactFunc(items, func(a, b T) bool { return key(a) == key(b) })
+}
+
+// page returns the items of the page a token points at and the token of the next
+// page, the same way a single tracker pages results.
+func page[T any](items []T, token string, requested int32) ([]T, string, error) {
+	offset, err := decodePageToken(token)
+	if err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, "invalid page token")
+	}
+
+	size := defaultPageSize
+	if requested > 0 {
+		size = min(int(requested), maxPageSize)
+	}
+	start := min(offset, len(items))
+	end := min(start+size, len(items))
+
+	var next string
+	if end < len(items) {
+		next = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+	}
+	return items[start:end], next, nil
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return offset, nil
+}
+
+func minPositive(a, b int64) int64 {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func toTrackerFiles(files []store.FileMetadata) []*tracker.File {
+	buffFiles := make([]*tracker.File, len(files))
+	for i, f := range files {
+		buffFiles[i] = &tracker.File{
+			Name:     f.Name,
+			Size:     f.Size,
+			Checksum: f.Checksum,
+		}
+	}
+	return buffFiles
+}
@@ -0,0 +1,436 @@
+package shards_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/shards"
+	"github.com/hamidoujand/P2P-file-sharing-network/peer/store"
+	"github.com/hamidoujand/P2P-file-sharing-network/ring"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const host = "10.0.0.1:50051"
+
+func TestClient(t *testing.T) {
+	trackers := make(map[string]*trackerServiceMock)
+	var addrs []string
+	for range 3 {
+		addr, ts := startTracker(t)
+		trackers[addr] = ts
+		addrs = append(addrs, addr)
+	}
+
+	r := newRing(t, addrs[0], addrs[1])
+	client, err := shards.New(r, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("expected to create client: %s", err)
+	}
+	defer client.Close()
+
+	var files []*tracker.File
+	for i := range 20 {
+		files = append(files, &tracker.File{Name: fmt.Sprintf("file-%d.txt", i), Size: int64(i), Checksum: fmt.Sprintf("hash-%d", i)})
+	}
+
+	resp, err := client.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Files: files})
+	if err != nil {
+		t.Fatalf("expected to register: %s", err)
+	}
+	token := resp.Token
+	checkOwners(t, r, trackers)
+
+	//the token of every shard is held in the one handed to the peer.
+	var shardTokens []string
+	for _, addr := range addrs[:2] {
+		shardTokens = append(shardTokens, trackers[addr].tokenOf(host))
+	}
+	got := shards.Tokens(token)
+	slices.Sort(got)
+	slices.Sort(shardTokens)
+	if !slices.Equal(got, shardTokens) {
+		t.Fatalf("tokens=%v, got %v", shardTokens, got)
+	}
+	if got := shards.Tokens("single"); !slices.Equal(got, []string{"single"}) {
+		t.Fatalf("tokens=[single], got %v", got)
+	}
+
+	//digests of every shard match what the client announced.
+	heartbeat(t, client, false)
+
+	added := &tracker.File{Name: "added.txt", Size: 1, Checksum: "added"}
+	files = append(files, added)
+	_, err = client.AnnounceChanges(context.Background(), &tracker.AnnounceChangesRequest{Host: host, Added: []*tracker.File{added}, CatalogDigest: "-"})
+	if err != nil {
+		t.Fatalf("expected to announce changes: %s", err)
+	}
+	checkOwners(t, r, trackers)
+	heartbeat(t, client, false)
+
+	//lookups by name go to the owner only.
+	peers, err := client.GetPeersForFile(context.Background(), &tracker.GetPeersForFileRequest{FileName: "added.txt"})
+	if err != nil {
+		t.Fatalf("expected to get peers for file: %s", err)
+	}
+	if len(peers.Peers) != 1 || peers.Peers[0].Host != host {
+		t.Fatalf("expected the peer to serve added.txt, got %v", peers.Peers)
+	}
+
+	//a shard joins, it does not know the peer until it registers again.
+	r = newRing(t, addrs...)
+	if err := client.SetRing(context.Background(), r); err != nil {
+		t.Fatalf("expected to set ring: %s", err)
+	}
+	heartbeat(t, client, true)
+	if !trackers[addrs[2]].has(host) {
+		t.Fatal("expected the heartbeat to register the peer with the new shard")
+	}
+	if _, err := client.UpdatePeer(context.Background(), &tracker.UpdatePeerRequest{Host: host, Files: files}); err != nil {
+		t.Fatalf("expected to update peer: %s", err)
+	}
+	checkOwners(t, r, trackers)
+	heartbeat(t, client, false)
+
+	//a shard lost the peer while another is down, the first gets the peer back anyway.
+	trackers[addrs[0]].forget(host)
+	trackers[addrs[1]].setDown(true)
+	_, err = client.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: host, CatalogDigest: "-"})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("code=%s, got %s", codes.Unavailable, status.Code(err))
+	}
+	trackers[addrs[1]].setDown(false)
+	checkOwners(t, r, trackers)
+	heartbeat(t, client, false)
+
+	if _, err := client.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Files: files, Token: token}); err != nil {
+		t.Fatalf("expected to register again: %s", err)
+	}
+	checkOwners(t, r, trackers)
+
+	//a shard leaves, the others get its files once the peer sends its catalog.
+	r = newRing(t, addrs[0], addrs[2])
+	if err := client.SetRing(context.Background(), r); err != nil {
+		t.Fatalf("expected to set ring: %s", err)
+	}
+	if trackers[addrs[1]].has(host) {
+		t.Fatal("expected the peer to leave the removed shard")
+	}
+
+	heartbeat(t, client, true)
+	if _, err := client.UpdatePeer(context.Background(), &tracker.UpdatePeerRequest{Host: host, Files: files}); err != nil {
+		t.Fatalf("expected to update peer: %s", err)
+	}
+	checkOwners(t, r, trackers)
+	heartbeat(t, client, false)
+}
+
+func TestClientMerges(t *testing.T) {
+	var addrs []string
+	for range 2 {
+		addr, _ := startTracker(t)
+		addrs = append(addrs, addr)
+	}
+
+	client, err := shards.New(newRing(t, addrs...), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("expected to create client: %s", err)
+	}
+	defer client.Close()
+
+	var files []*tracker.File
+	var total int64
+	for i := range 20 {
+		files = append(files, &tracker.File{Name: fmt.Sprintf("file-%02d.txt", i), Size: int64(i), Checksum: fmt.Sprintf("hash-%d", i)})
+		total += int64(i)
+	}
+	if _, err := client.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Files: files}); err != nil {
+		t.Fatalf("expected to register: %s", err)
+	}
+
+	//search results of both shards are paged in order.
+	var names []string
+	in := tracker.SearchFilesRequest{PageSize: 6}
+	for {
+		resp, err := client.SearchFiles(context.Background(), &in)
+		if err != nil {
+			t.Fatalf("expected to search files: %s", err)
+		}
+		for _, f := range resp.Files {
+			names = append(names, f.File.Name)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		in.PageToken = resp.NextPageToken
+	}
+	if len(names) != len(files) || !slices.IsSorted(names) {
+		t.Fatalf("expected every file once and in order, got %v", names)
+	}
+
+	stats, err := client.GetStats(context.Background(), &tracker.GetStatsRequest{})
+	if err != nil {
+		t.Fatalf("expected to get stats: %s", err)
+	}
+	if stats.PeerCount != 1 || stats.TotalBytes != total {
+		t.Fatalf("peers=1 bytes=%d, got peers=%d bytes=%d", total, stats.PeerCount, stats.TotalBytes)
+	}
+
+	if _, err := client.ExportState(context.Background(), &tracker.ExportStateRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("code=%s, got %s", codes.Unimplemented, status.Code(err))
+	}
+}
+
+func newRing(t *testing.T, shards ...string) *ring.Ring {
+	r, err := ring.New(shards)
+	if err != nil {
+		t.Fatalf("expected to create ring: %s", err)
+	}
+	return r
+}
+
+func heartbeat(t *testing.T, client *shards.Client, resync bool) {
+	resp, err := client.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: host, CatalogDigest: "-"})
+	if err != nil {
+		t.Fatalf("expected to send heartbeat: %s", err)
+	}
+	if resp.ResyncRequired != resync {
+		t.Fatalf("resync=%t, got %t", resync, resp.ResyncRequired)
+	}
+}
+
+// checkOwners checks that every shard of r indexes exactly the files it owns.
+func checkOwners(t *testing.T, r *ring.Ring, trackers map[string]*trackerServiceMock) {
+	t.Helper()
+
+	var total int
+	for _, addr := range r.Shards() {
+		names := trackers[addr].names(host)
+		for _, name := range names {
+			if owner := r.Owner(name); owner != addr {
+				t.Fatalf("expected %s on shard %s, found on %s", name, owner, addr)
+			}
+		}
+		total += len(names)
+	}
+	if total == 0 {
+		t.Fatal("expected the shards to index the files")
+	}
+}
+
+// startTracker serves a tracker mock on a random port.
+func startTracker(t *testing.T) (string, *trackerServiceMock) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected to listen: %s", err)
+	}
+
+	ts := &trackerServiceMock{
+		files:  make(map[string][]*tracker.File),
+		seqs:   make(map[string]uint64),
+		tokens: make(map[string]string),
+	}
+	server := grpc.NewServer()
+	tracker.RegisterTrackerServiceServer(server, ts)
+	go server.Serve(lis)
+
+	t.Cleanup(server.Stop)
+	return lis.Addr().String(), ts
+}
+
+// ==============================================================================
+// mocks
+type trackerServiceMock struct {
+	tracker.UnimplementedTrackerServiceServer
+	mu     sync.Mutex
+	files  map[string][]*tracker.File
+	seqs   map[string]uint64
+	tokens map[string]string
+	// down makes the heartbeats fail like on a tracker that is down.
+	down bool
+}
+
+func (ts *trackerServiceMock) has(host string) bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	_, ok := ts.tokens[host]
+	return ok
+}
+
+// forget drops the peer like when its lease expired.
+func (ts *trackerServiceMock) forget(host string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	delete(ts.files, host)
+	delete(ts.seqs, host)
+	delete(ts.tokens, host)
+}
+
+func (ts *trackerServiceMock) setDown(down bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.down = down
+}
+
+func (ts *trackerServiceMock) names(host string) []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var names []string
+	for _, f := range ts.files[host] {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// authorize must be called with ts.mu held.
+func (ts *trackerServiceMock) authorize(host string, token string) error {
+	expected, ok := ts.tokens[host]
+	if !ok {
+		return status.Errorf(codes.NotFound, "peer %s, not found", host)
+	}
+	if token != expected {
+		return status.Errorf(codes.PermissionDenied, "invalid token for peer %s", host)
+	}
+	return nil
+}
+
+// digest must be called with ts.mu held.
+func (ts *trackerServiceMock) digest(host string) string {
+	var d store.Digest
+	for _, f := range ts.files[host] {
+		d.Toggle(store.FileMetadata{Name: f.Name, Size: f.Size, Checksum: f.Checksum})
+	}
+	return d.String()
+}
+
+func (ts *trackerServiceMock) tokenOf(host string) string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.tokens[host]
+}
+
+func (ts *trackerServiceMock) RegisterPeer(ctx context.Context, in *tracker.RegisterPeerRequest) (*tracker.RegisterPeerResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	token := in.Token
+	if _, ok := ts.tokens[in.Host]; !ok {
+		token = fmt.Sprintf("token-%p", in)
+	} else if err := ts.authorize(in.Host, in.Token); err != nil {
+		return nil, err
+	}
+
+	ts.files[in.Host] = in.Files
+	ts.seqs[in.Host] = in.CatalogSeq
+	ts.tokens[in.Host] = token
+	return &tracker.RegisterPeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String(), Token: token}, nil
+}
+
+func (ts *trackerServiceMock) UnRegisterPeer(ctx context.Context, in *tracker.UnRegisterPeerRequest) (*tracker.UnRegisterPeerResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if err := ts.authorize(in.Host, in.Token); err != nil {
+		return nil, err
+	}
+
+	delete(ts.files, in.Host)
+	delete(ts.seqs, in.Host)
+	delete(ts.tokens, in.Host)
+	return &tracker.UnRegisterPeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+}
+
+func (ts *trackerServiceMock) Heartbeat(ctx context.Context, in *tracker.HeartbeatRequest) (*tracker.HeartbeatResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.down {
+		return nil, status.Error(codes.Unavailable, "tracker is down")
+	}
+	if err := ts.authorize(in.Host, in.Token); err != nil {
+		return nil, err
+	}
+
+	resync := in.CatalogSeq != ts.seqs[in.Host] || in.CatalogDigest != ts.digest(in.Host)
+	return &tracker.HeartbeatResponse{StatusCode: int64(codes.OK), Message: codes.OK.String(), ResyncRequired: resync}, nil
+}
+
+func (ts *trackerServiceMock) AnnounceChanges(ctx context.Context, in *tracker.AnnounceChangesRequest) (*tracker.AnnounceChangesResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if err := ts.authorize(in.Host, in.Token); err != nil {
+		return nil, err
+	}
+
+	if in.CatalogSeq != ts.seqs[in.Host]+1 {
+		return &tracker.AnnounceChangesResponse{ResyncRequired: true, CatalogSeq: ts.seqs[in.Host]}, nil
+	}
+
+	files := slices.DeleteFunc(slices.Clone(ts.files[in.Host]), func(f *tracker.File) bool {
+		return slices.Contains(in.Removed, f.Name) || slices.ContainsFunc(in.Added, func(a *tracker.File) bool { return a.Name == f.Name })
+	})
+	ts.files[in.Host] = append(files, in.Added...)
+	ts.seqs[in.Host] = in.CatalogSeq
+
+	resync := in.CatalogDigest != ts.digest(in.Host)
+	return &tracker.AnnounceChangesResponse{StatusCode: int64(codes.OK), CatalogSeq: in.CatalogSeq, ResyncRequired: resync}, nil
+}
+
+func (ts *trackerServiceMock) UpdatePeer(ctx context.Context, in *tracker.UpdatePeerRequest) (*tracker.UpdatePeerResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if err := ts.authorize(in.Host, in.Token); err != nil {
+		return nil, err
+	}
+
+	ts.files[in.Host] = in.Files
+	ts.seqs[in.Host] = in.CatalogSeq
+	return &tracker.UpdatePeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+}
+
+func (ts *trackerServiceMock) GetPeersForFile(ctx context.Context, in *tracker.GetPeersForFileRequest) (*tracker.GetPeersResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var peers []*tracker.Peer
+	for host, files := range ts.files {
+		for _, f := range files {
+			if f.Name == in.FileName {
+				peers = append(peers, &tracker.Peer{Host: host, Files: []*tracker.File{f}})
+			}
+		}
+	}
+	return &tracker.GetPeersResponse{Peers: peers}, nil
+}
+
+func (ts *trackerServiceMock) SearchFiles(ctx context.Context, in *tracker.SearchFilesRequest) (*tracker.SearchFilesResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var resp tracker.SearchFilesResponse
+	for _, files := range ts.files {
+		for _, f := range files {
+			resp.Files = append(resp.Files, &tracker.FileSummary{File: f, PeerCount: 1})
+		}
+	}
+	return &resp, nil
+}
+
+func (ts *trackerServiceMock) GetStats(ctx context.Context, in *tracker.GetStatsRequest) (*tracker.GetStatsResponse, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	resp := tracker.GetStatsResponse{PeerCount: int64(len(ts.tokens))}
+	for _, files := range ts.files {
+		for _, f := range files {
+			resp.TotalBytes += f.Size
+		}
+	}
+	return &resp, nil
+}
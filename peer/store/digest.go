@@ -0,0 +1,33 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Digest is an order independent fingerprint of the catalog: the xor of the hashes of
+// every file. It must be computed exactly like the tracker does, so both sides can tell
+// whether they agree on the catalog of this peer.
+type Digest [sha256.Size]byte
+
+// Toggle adds the file to the digest, or removes it when it was already part of it.
+func (d *Digest) Toggle(file FileMetadata) {
+	h := sha256.New()
+	h.Write([]byte(file.Name))
+	h.Write([]byte{0})
+	h.Write([]byte(file.Checksum))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(file.Size, 10)))
+
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	for i := range d {
+		d[i] ^= sum[i]
+	}
+}
+
+// String returns the hex representation of the digest.
+func (d Digest) String() string {
+	return hex.EncodeToString(d[:])
+}
@@ -11,12 +11,18 @@ var ErrFileNotFound = errors.New("file not found")
 type Store struct {
 	mu    sync.RWMutex
 	store map[string]FileMetadata
+	// checksums maps a checksum to the names of the files with that content.
+	checksums map[string]map[string]struct{}
+	// seq is bumped on every change of the catalog, digest fingerprints its content.
+	seq    uint64
+	digest Digest
 }
 
 // New returns a new store.
 func New() *Store {
 	return &Store{
-		store: map[string]FileMetadata{},
+		store:     map[string]FileMetadata{},
+		checksums: map[string]map[string]struct{}{},
 	}
 }
 
@@ -32,11 +38,22 @@ func (s *Store) GetFileMetadata(name string) (FileMetadata, error) {
 	return file, nil
 }
 
+// GetFileByChecksum returns a file with the content matching the checksum or ErrFileNotFound.
+func (s *Store) GetFileByChecksum(checksum string) (FileMetadata, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for name := range s.checksums[checksum] {
+		return s.store[name], nil
+	}
+	return FileMetadata{}, ErrFileNotFound
+}
+
 // AddFileMetadata adds the new file metadata.
 func (s *Store) AddFileMetadata(fm FileMetadata) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.store[fm.Name] = fm
+	s.put(fm)
 }
 
 // RemoveFileMetadata removes the file matched the given name or return ErrFileNotFound.
@@ -47,7 +64,10 @@ func (s *Store) RemoveFileMetadata(filename string) error {
 	if !ok {
 		return ErrFileNotFound
 	}
+	s.unindex(file)
 	delete(s.store, file.Name)
+	s.digest.Toggle(file)
+	s.seq++
 	return nil
 }
 
@@ -55,14 +75,20 @@ func (s *Store) RemoveFileMetadata(filename string) error {
 func (s *Store) UpdateFileMetadata(updates FileMetadata) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	file, ok := s.store[updates.Name]
-	if !ok {
+	if _, ok := s.store[updates.Name]; !ok {
 		return ErrFileNotFound
 	}
-	s.store[file.Name] = updates
+	s.put(updates)
 	return nil
 }
 
+// Catalog returns the sequence number and digest of the current catalog.
+func (s *Store) Catalog() (uint64, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.seq, s.digest.String()
+}
+
 // ListFileMetadats returns a slice of all file metadatas that it has.
 func (s *Store) ListFileMetadatas() []FileMetadata {
 	s.mu.RLock()
@@ -74,3 +100,30 @@ func (s *Store) ListFileMetadatas() []FileMetadata {
 	}
 	return files
 }
+
+// put stores fm and keeps the checksum index and the catalog version in sync, callers
+// must hold the write lock.
+func (s *Store) put(fm FileMetadata) {
+	if old, ok := s.store[fm.Name]; ok {
+		s.unindex(old)
+		s.digest.Toggle(old)
+	}
+	s.store[fm.Name] = fm
+	s.digest.Toggle(fm)
+	s.seq++
+
+	names, ok := s.checksums[fm.Checksum]
+	if !ok {
+		names = map[string]struct{}{}
+		s.checksums[fm.Checksum] = names
+	}
+	names[fm.Name] = struct{}{}
+}
+
+func (s *Store) unindex(fm FileMetadata) {
+	names := s.checksums[fm.Checksum]
+	delete(names, fm.Name)
+	if len(names) == 0 {
+		delete(s.checksums, fm.Checksum)
+	}
+}
@@ -0,0 +1,112 @@
+// Package ring partitions the file index between tracker shards by consistent hashing
+// of file names. Every shard owns the names hashing between its points and the points
+// before them, so adding or removing a shard only moves the names next to its points.
+//
+// Peers and trackers share the ring by its definition: the addresses of the shards,
+// one per line or comma separated. The order of the addresses does not matter.
+package ring
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Vnodes is the number of points of every shard on the ring, more points spread the
+// names more evenly.
+const Vnodes = 128
+
+// point is a position of a shard on the ring.
+type point struct {
+	hash  uint64
+	shard string
+}
+
+// Ring maps file names to the shards owning them.
+type Ring struct {
+	shards []string
+	points []point
+}
+
+// New creates a ring of shards, duplicates and empty addresses are ignored.
+func New(shards []string) (*Ring, error) {
+	var r Ring
+	for _, shard := range shards {
+		shard = strings.TrimSpace(shard)
+		if shard == "" || slices.Contains(r.shards, shard) {
+			continue
+		}
+		r.shards = append(r.shards, shard)
+	}
+
+	if len(r.shards) == 0 {
+		return nil, errors.New("ring has no shards")
+	}
+	slices.Sort(r.shards)
+
+	for _, shard := range r.shards {
+		for i := range Vnodes {
+			r.points = append(r.points, point{hash: hash(shard + "#" + strconv.Itoa(i)), shard: shard})
+		}
+	}
+	slices.SortFunc(r.points, func(a, b point) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), strings.Compare(a.shard, b.shard))
+	})
+	return &r, nil
+}
+
+// Parse creates a ring from its definition, lines starting with # are comments.
+func Parse(def string) (*Ring, error) {
+	var shards []string
+	for _, line := range strings.Split(def, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		shards = append(shards, strings.Split(line, ",")...)
+	}
+	return New(shards)
+}
+
+// Load creates a ring from the definition kept in a file.
+func Load(path string) (*Ring, error) {
+	def, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read ring: %w", err)
+	}
+	return Parse(string(def))
+}
+
+// Owner returns the shard owning the file name: the shard of the first point at or
+// after the hash of the name.
+func (r *Ring) Owner(name string) string {
+	h := hash(name)
+	i, _ := slices.BinarySearchFunc(r.points, h, func(p point, h uint64) int {
+		return cmp.Compare(p.hash, h)
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].shard
+}
+
+// Shards returns the addresses of the shards, sorted.
+func (r *Ring) Shards() []string {
+	return slices.Clone(r.shards)
+}
+
+// Equal reports whether both rings have the same shards, and so the same owners.
+func (r *Ring) Equal(other *Ring) bool {
+	return slices.Equal(r.shards, other.shards)
+}
+
+func hash(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
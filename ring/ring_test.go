@@ -0,0 +1,86 @@
+package ring_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/ring"
+)
+
+func TestOwner(t *testing.T) {
+	r, err := ring.Parse("# shards\ntracker1:50051\ntracker2:50051,tracker3:50051\n")
+	if err != nil {
+		t.Fatalf("expected to parse ring: %s", err)
+	}
+
+	//the order of the definition does not matter.
+	same, err := ring.New([]string{"tracker3:50051", "tracker1:50051", "tracker2:50051", "tracker1:50051"})
+	if err != nil {
+		t.Fatalf("expected to create ring: %s", err)
+	}
+	if !r.Equal(same) {
+		t.Fatalf("expected rings to be equal, got %v and %v", r.Shards(), same.Shards())
+	}
+
+	owned := make(map[string]int)
+	for i := range 3000 {
+		name := fmt.Sprintf("file-%d.txt", i)
+		owner := r.Owner(name)
+		if owner != same.Owner(name) {
+			t.Fatalf("expected both rings to agree on the owner of %s", name)
+		}
+		owned[owner]++
+	}
+
+	//names are spread over every shard.
+	for _, shard := range r.Shards() {
+		if owned[shard] < 500 {
+			t.Fatalf("expected shard %s to own a fair share, got %d of 3000", shard, owned[shard])
+		}
+	}
+}
+
+func TestOwnerRebalance(t *testing.T) {
+	before, err := ring.New([]string{"tracker1:50051", "tracker2:50051", "tracker3:50051"})
+	if err != nil {
+		t.Fatalf("expected to create ring: %s", err)
+	}
+	after, err := ring.New([]string{"tracker1:50051", "tracker2:50051", "tracker3:50051", "tracker4:50051"})
+	if err != nil {
+		t.Fatalf("expected to create ring: %s", err)
+	}
+
+	//only names moving to the new shard change owner.
+	var moved int
+	for i := range 3000 {
+		name := fmt.Sprintf("file-%d.txt", i)
+		if before.Owner(name) == after.Owner(name) {
+			continue
+		}
+		if after.Owner(name) != "tracker4:50051" {
+			t.Fatalf("expected %s to only move to the new shard, moved to %s", name, after.Owner(name))
+		}
+		moved++
+	}
+
+	if moved == 0 || moved > 1200 {
+		t.Fatalf("expected about a quarter of the names to move, got %d of 3000", moved)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring")
+	if err := os.WriteFile(path, []byte("\n# no shards\n"), 0644); err != nil {
+		t.Fatalf("expected to write ring: %s", err)
+	}
+
+	if _, err := ring.Load(path); err == nil {
+		t.Fatal("expected an error for a ring without shards")
+	}
+
+	if _, err := ring.Load(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected an error for a missing ring")
+	}
+}
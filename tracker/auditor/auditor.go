@@ -0,0 +1,467 @@
+// Package auditor checks that peers really keep the contents they announced. Peers are
+// challenged to hash a random byte range of a content along with a nonce, and to hand out
+// a random block of it along with its path to the root of the Merkle tree of the content.
+// The proof is checked against the ones of other peers serving the same content, and the
+// root against the one the peer proved before. Peers failing a challenge are left out of
+// the lookups of the content until they pass one, and are reported.
+//
+// Peers failing are marked rather than dropped from the catalog: the catalog stays the one
+// the peer announced, so its digest keeps matching and the next sync does not bring the
+// content back, and a peer that lost a content for a while can win it back by proving it.
+package auditor
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	mrand "math/rand/v2"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/merkle"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultInterval is the time between two audit rounds.
+	DefaultInterval = 5 * time.Minute
+	// DefaultTimeout is how long a peer can take to answer a single challenge.
+	DefaultTimeout = 10 * time.Second
+	// DefaultPeers is how many peers are challenged every round.
+	DefaultPeers = 5
+	// DefaultRangeSize is the most bytes of a content a single challenge covers, it is
+	// the size of the blocks of the Merkle trees too.
+	DefaultRangeSize = 64 * 1024
+	// DefaultPenalty is added to the record of a peer failing a challenge, as much as
+	// a report of corrupted content.
+	DefaultPenalty = 5
+)
+
+// nonceSize is the number of random bytes hashed ahead of the range.
+const nonceSize = 16
+
+// reporter is who the auditor reports the peers failing a challenge as.
+const reporter = "tracker/auditor"
+
+// maxVerifiers is how many other holders of a content are asked for the proof at most.
+const maxVerifiers = 3
+
+// Verdict is the outcome of a challenge.
+type Verdict int
+
+const (
+	// Inconclusive challenges can not tell, like when no other holder answered or a single
+	// one disagreed.
+	Inconclusive Verdict = iota
+	// Passed challenges got the proof another holder of the content gave, or a block of
+	// the tree the peer proved before.
+	Passed
+	// Failed challenges got no proof, a block out of place in its tree, a tree other than
+	// the one the peer proved before, or a proof two other holders agree against.
+	Failed
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Passed:
+		return "passed"
+	case Failed:
+		return "failed"
+	default:
+		return "inconclusive"
+	}
+}
+
+// Auditor challenges a few peers every round.
+type Auditor struct {
+	store      peerstore.Storer
+	interval   time.Duration
+	timeout    time.Duration
+	peers      int
+	rangeSize  int64
+	penalty    float64
+	reputation peerstore.Reputation
+	active     func() bool
+
+	// roots are the roots of the trees the peers proved, kept in memory. A root is taken
+	// once two other holders back the peer, or one whose root was taken that way, or as
+	// the peer hands it out when nobody else keeps the content.
+	mu    sync.Mutex
+	roots map[rootKey]root
+}
+
+// rootKey identifies a content of a peer, roots are kept by host so a peer can only ever
+// be held to a tree it proved itself.
+type rootKey struct {
+	host     string
+	checksum string
+}
+
+// root is the root of the tree of a content, along with the size of its blocks.
+type root struct {
+	hash      []byte
+	blockSize int64
+	// backed is set when other holders backed the peer, roots taken from a sole holder
+	// vouch for nobody else.
+	backed bool
+}
+
+// Config represents all the settings of an auditor, zero values fall back to defaults.
+type Config struct {
+	Store    peerstore.Storer
+	Interval time.Duration
+	Timeout  time.Duration
+	// Peers is how many peers are challenged every round.
+	Peers     int
+	RangeSize int64
+	// Penalty and Reputation are used to report the peers failing a challenge, see
+	// peerstore.Store.ReportPeer.
+	Penalty    float64
+	Reputation peerstore.Reputation
+	// Active reports whether audits should run, like only on the leader of a cluster
+	// of trackers. Optional, audits always run without it.
+	Active func() bool
+}
+
+// challenge is a content a peer is asked to prove it keeps.
+type challenge struct {
+	host string
+	file peerstore.FileMetadata
+	// unproven is set when the peer failed to prove it keeps the content before.
+	unproven bool
+}
+
+// New creates a new auditor.
+func New(conf *Config) *Auditor {
+	a := Auditor{
+		store:      conf.Store,
+		interval:   conf.Interval,
+		timeout:    conf.Timeout,
+		peers:      conf.Peers,
+		rangeSize:  conf.RangeSize,
+		penalty:    conf.Penalty,
+		reputation: conf.Reputation,
+		active:     conf.Active,
+		roots:      make(map[rootKey]root),
+	}
+
+	if a.interval <= 0 {
+		a.interval = DefaultInterval
+	}
+
+	if a.timeout <= 0 {
+		a.timeout = DefaultTimeout
+	}
+
+	if a.peers <= 0 {
+		a.peers = DefaultPeers
+	}
+
+	if a.rangeSize <= 0 {
+		a.rangeSize = DefaultRangeSize
+	}
+
+	if a.penalty <= 0 {
+		a.penalty = DefaultPenalty
+	}
+
+	if a.reputation == (peerstore.Reputation{}) {
+		a.reputation = peerstore.DefaultReputation
+	}
+	return &a
+}
+
+// Run audits a few peers every interval until ctx is done.
+func (a *Auditor) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.AuditAll(ctx)
+		}
+	}
+}
+
+// AuditAll challenges random peers on a random content each and waits for every verdict.
+// Peers failing are left out of the lookups of the content and are reported.
+func (a *Auditor) AuditAll(ctx context.Context) {
+	challenges := a.plan()
+
+	var wg sync.WaitGroup
+	wg.Add(len(challenges))
+	for _, c := range challenges {
+		go func() {
+			defer wg.Done()
+			a.audit(ctx, c)
+		}()
+	}
+	wg.Wait()
+}
+
+// plan picks the healthy peers to challenge and a content of each, empty contents have
+// nothing to prove. Contents a peer failed to prove come first, so it can win them back.
+func (a *Auditor) plan() []challenge {
+	if a.active != nil && !a.active() {
+		return nil
+	}
+
+	peers := a.store.GetAllPeers()
+	a.forget(peers)
+
+	var challenges []challenge
+	for _, p := range peers {
+		if p.Health != peerstore.Healthy || p.Draining {
+			continue
+		}
+
+		files := slices.DeleteFunc(slices.Clone(p.Files), func(f peerstore.FileMetadata) bool {
+			return f.Size <= 0
+		})
+		unproven := slices.DeleteFunc(slices.Clone(files), func(f peerstore.FileMetadata) bool {
+			return !slices.Contains(p.Unproven, f.Checksum)
+		})
+		if len(unproven) > 0 {
+			files = unproven
+		}
+		if len(files) == 0 {
+			continue
+		}
+		challenges = append(challenges, challenge{host: p.Host, file: files[mrand.IntN(len(files))], unproven: len(unproven) > 0})
+	}
+
+	mrand.Shuffle(len(challenges), func(i, j int) {
+		challenges[i], challenges[j] = challenges[j], challenges[i]
+	})
+	return challenges[:min(a.peers, len(challenges))]
+}
+
+func (a *Auditor) audit(ctx context.Context, c challenge) {
+	verdict, err := a.Challenge(ctx, c.host, c.file)
+	if err != nil {
+		log.Printf("audit peer[%s] on file[%s]: %s\n", c.host, c.file.Name, err)
+	}
+
+	switch {
+	case verdict == Failed:
+		log.Printf("peer[%s] failed to prove it keeps file[%s], leaving it out of lookups\n", c.host, c.file.Name)
+		if err := a.store.SetUnproven(c.host, c.file.Checksum, true); err != nil {
+			log.Printf("mark file[%s] of peer[%s] unproven: %s\n", c.file.Name, c.host, err)
+		}
+		report := peerstore.Report{Reporter: reporter, Penalty: a.penalty}
+		if _, err := a.store.ReportPeer(c.host, report, a.reputation); err != nil {
+			log.Printf("report peer[%s]: %s\n", c.host, err)
+		}
+	case verdict == Passed && c.unproven:
+		log.Printf("peer[%s] proved it keeps file[%s] again\n", c.host, c.file.Name)
+		if err := a.store.SetUnproven(c.host, c.file.Checksum, false); err != nil {
+			log.Printf("mark file[%s] of peer[%s] proven: %s\n", c.file.Name, c.host, err)
+		}
+	}
+}
+
+// forget drops the roots of the contents their peers no longer announce.
+func (a *Auditor) forget(peers []peerstore.Peer) {
+	kept := make(map[rootKey]bool)
+	for _, p := range peers {
+		for _, file := range p.Files {
+			kept[rootKey{host: p.Host, checksum: file.Checksum}] = true
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key := range a.roots {
+		if !kept[key] {
+			delete(a.roots, key)
+		}
+	}
+}
+
+// Challenge asks a peer for the proof of a random range of the content and for a random
+// block of it, then asks other healthy holders of the content for their proof of the range.
+// The peer fails when it can not prove it keeps the content, when the block is not in the
+// tree it hands out or the tree is not the one it proved before, or when two other holders
+// agree with each other against it and none agrees with it. A single holder disagreeing is
+// not enough, it may be the one lying. Contents kept by a single peer are held to the tree
+// the peer handed out the first time.
+func (a *Auditor) Challenge(ctx context.Context, host string, file peerstore.FileMetadata) (Verdict, error) {
+	if file.Size <= 0 {
+		return Inconclusive, fmt.Errorf("file %s is empty", file.Name)
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return Inconclusive, fmt.Errorf("nonce: %w", err)
+	}
+
+	key := rootKey{host: host, checksum: file.Checksum}
+	a.mu.Lock()
+	known, rooted := a.roots[key]
+	a.mu.Unlock()
+
+	//a root is only ever checked against blocks of the size it was built with.
+	blockSize := a.rangeSize
+	if rooted {
+		blockSize = known.blockSize
+	}
+
+	length := min(a.rangeSize, file.Size)
+	offset := mrand.Int64N(file.Size - length + 1)
+	in := peer.ProveStorageRequest{
+		Checksum:   file.Checksum,
+		Offset:     offset,
+		Length:     length,
+		Nonce:      nonce,
+		BlockSize:  blockSize,
+		BlockIndex: mrand.Int64N((file.Size + blockSize - 1) / blockSize),
+	}
+
+	resp, err := a.prove(ctx, host, &in)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.OutOfRange, codes.DataLoss:
+			//the peer admits not keeping the content whole.
+			return Failed, nil
+		default:
+			return Inconclusive, err
+		}
+	}
+
+	if !inTree(file, &in, resp) {
+		return Failed, nil
+	}
+	if rooted && !bytes.Equal(resp.GetRoot(), known.hash) {
+		return Failed, nil
+	}
+
+	//other holders only prove the range, they have no tree to hand out.
+	check := peer.ProveStorageRequest{Checksum: file.Checksum, Offset: offset, Length: length, Nonce: nonce}
+	verifiers := a.verifiers(host, file.Checksum)
+
+	var (
+		agreed         int
+		backed, failed bool
+	)
+	against := make(map[string]int)
+	for _, verifier := range verifiers {
+		expected, err := a.prove(ctx, verifier, &check)
+		if err != nil {
+			continue
+		}
+		if expected.GetProof() == resp.GetProof() {
+			agreed++
+			backed = backed || a.backed(rootKey{host: verifier, checksum: file.Checksum})
+			continue
+		}
+
+		//holders disagreeing with each other tell nothing about the peer.
+		against[expected.GetProof()]++
+		failed = failed || against[expected.GetProof()] >= 2
+	}
+
+	switch {
+	case agreed >= 2 || backed:
+		a.remember(key, resp.GetRoot(), blockSize, true)
+		return Passed, nil
+	case agreed == 1:
+		//a single holder agreeing may be lying along, the root is only taken once
+		//another one backs the peer.
+		return Passed, nil
+	case failed:
+		return Failed, nil
+	case rooted:
+		return Passed, nil
+	case len(verifiers) == 0:
+		//nobody else keeps the content, the peer is held to this tree from now on.
+		a.remember(key, resp.GetRoot(), blockSize, false)
+	}
+	return Inconclusive, nil
+}
+
+// remember keeps the root of the tree of a content of a peer, unless one is known already.
+// A root taken from a sole holder is replaced once other holders back it.
+func (a *Auditor) remember(key rootKey, hash []byte, blockSize int64, backed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if known, ok := a.roots[key]; !ok || (backed && !known.backed) {
+		a.roots[key] = root{hash: hash, blockSize: blockSize, backed: backed}
+	}
+}
+
+// backed reports whether other holders backed the root of a content of a peer.
+func (a *Auditor) backed(key rootKey) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.roots[key].backed
+}
+
+// inTree reports whether the block a peer handed out has the size of the block challenged,
+// and is at its place in the tree of the root the peer handed out.
+func inTree(file peerstore.FileMetadata, in *peer.ProveStorageRequest, resp *peer.ProveStorageResponse) bool {
+	start := in.GetBlockIndex() * in.GetBlockSize()
+	if int64(len(resp.GetBlock())) != min(in.GetBlockSize(), file.Size-start) {
+		return false
+	}
+
+	blocks := (file.Size + in.GetBlockSize() - 1) / in.GetBlockSize()
+	return merkle.Verify(resp.GetRoot(), merkle.Leaf(resp.GetBlock()), int(in.GetBlockIndex()), int(blocks), resp.GetPath())
+}
+
+// verifiers returns a few other holders of the content the prober reached, in random order.
+// Like reporters, holders behind the same IP address count once and the ones behind the
+// address of the peer do not count, they could all be the peer.
+func (a *Auditor) verifiers(host string, checksum string) []string {
+	now := time.Now()
+
+	peers := a.store.GetPeersForChecksum(checksum)
+	mrand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+
+	seen := map[string]bool{ipOf(host): true}
+	var hosts []string
+	for _, p := range peers {
+		//peers not probed yet have no RTT.
+		if p.Health != peerstore.Healthy || p.RTT <= 0 || now.Before(p.BannedUntil) || seen[ipOf(p.Host)] {
+			continue
+		}
+		seen[ipOf(p.Host)] = true
+		hosts = append(hosts, p.Host)
+	}
+	return hosts[:min(maxVerifiers, len(hosts))]
+}
+
+// ipOf returns the IP address of a host, the host itself when it has no port.
+func ipOf(host string) string {
+	if ip, _, err := net.SplitHostPort(host); err == nil {
+		return ip
+	}
+	return host
+}
+
+func (a *Auditor) prove(ctx context.Context, host string, in *peer.ProveStorageRequest) (*peer.ProveStorageResponse, error) {
+	conn, err := grpc.NewClient(host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	return peer.NewPeerServiceClient(conn).ProveStorage(ctx, in)
+}
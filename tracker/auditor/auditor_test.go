@@ -0,0 +1,365 @@
+package auditor_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/merkle"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/auditor"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChallenge(t *testing.T) {
+	store := peerstore.New()
+
+	data := []byte("this is the content every holder should keep.")
+	file := peerstore.FileMetadata{Name: "file.txt", Size: int64(len(data)), Checksum: "hash"}
+
+	honest := startPeer(t, data)
+	other := startPeer(t, data)
+	liar := startPeer(t, bytes.Repeat([]byte("x"), len(data)))
+	lost := startPeer(t, nil)
+	forger := servePeer(t, &peerServiceMock{data: data, forge: true})
+
+	for _, host := range []string{honest, other, liar, lost, forger} {
+		register(store, host, file)
+	}
+
+	a := auditor.New(&auditor.Config{Store: store, RangeSize: 8})
+
+	tests := map[string]struct {
+		host     string
+		file     peerstore.FileMetadata
+		expected auditor.Verdict
+	}{
+		"honest holder": {
+			host:     honest,
+			file:     file,
+			expected: auditor.Passed,
+		},
+		"wrong proof": {
+			host:     liar,
+			file:     file,
+			expected: auditor.Failed,
+		},
+		"content not kept": {
+			host:     lost,
+			file:     file,
+			expected: auditor.Failed,
+		},
+		"block out of its tree": {
+			host:     forger,
+			file:     file,
+			expected: auditor.Failed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			verdict, err := a.Challenge(context.Background(), test.host, test.file)
+			if err != nil {
+				t.Fatalf("expected to challenge peer: %s", err)
+			}
+			if verdict != test.expected {
+				t.Fatalf("verdict=%s, got %s", test.expected, verdict)
+			}
+		})
+	}
+}
+
+func TestChallengeOneAgainstOne(t *testing.T) {
+	store := peerstore.New()
+
+	data := []byte("this is the content every holder should keep.")
+	file := peerstore.FileMetadata{Name: "file.txt", Size: int64(len(data)), Checksum: "hash"}
+
+	honest := startPeer(t, data)
+	liar := startPeer(t, bytes.Repeat([]byte("x"), len(data)))
+	register(store, honest, file)
+	register(store, liar, file)
+
+	a := auditor.New(&auditor.Config{Store: store, RangeSize: 8})
+
+	//with a single other holder there is no telling which one lies.
+	for _, host := range []string{honest, liar} {
+		verdict, err := a.Challenge(context.Background(), host, file)
+		if err != nil {
+			t.Fatalf("expected to challenge peer: %s", err)
+		}
+		if verdict != auditor.Inconclusive {
+			t.Fatalf("verdict=%s, got %s", auditor.Inconclusive, verdict)
+		}
+	}
+}
+
+func TestChallengeSingleHolder(t *testing.T) {
+	store := peerstore.New()
+
+	data := []byte("this is the content only a single peer keeps.")
+	file := peerstore.FileMetadata{Name: "single.txt", Size: int64(len(data)), Checksum: "single"}
+
+	mock := peerServiceMock{data: data}
+	host := servePeer(t, &mock)
+	register(store, host, file)
+
+	a := auditor.New(&auditor.Config{Store: store, RangeSize: 8})
+
+	challenge := func(expected auditor.Verdict) {
+		t.Helper()
+		verdict, err := a.Challenge(context.Background(), host, file)
+		if err != nil {
+			t.Fatalf("expected to challenge peer: %s", err)
+		}
+		if verdict != expected {
+			t.Fatalf("verdict=%s, got %s", expected, verdict)
+		}
+	}
+
+	//the first challenge takes the root as the peer hands it out, later ones hold the
+	//peer to it.
+	challenge(auditor.Inconclusive)
+	challenge(auditor.Passed)
+
+	mock.setData(bytes.Repeat([]byte("x"), len(data)))
+	challenge(auditor.Failed)
+
+	mock.setData(data)
+	challenge(auditor.Passed)
+}
+
+func TestChallengeVerifiers(t *testing.T) {
+	store := peerstore.New()
+
+	data := []byte("this is the content every holder should keep.")
+	forged := bytes.Repeat([]byte("x"), len(data))
+	file := peerstore.FileMetadata{Name: "file.txt", Size: int64(len(data)), Checksum: "hash"}
+
+	liar := startPeer(t, forged)
+	accomplice := startPeer(t, forged)
+	register(store, liar, file)
+	register(store, accomplice, file)
+
+	a := auditor.New(&auditor.Config{Store: store, RangeSize: 8})
+
+	challenge := func(host string, expected auditor.Verdict) {
+		t.Helper()
+		verdict, err := a.Challenge(context.Background(), host, file)
+		if err != nil {
+			t.Fatalf("expected to challenge peer: %s", err)
+		}
+		if verdict != expected {
+			t.Fatalf("verdict=%s, got %s", expected, verdict)
+		}
+	}
+
+	//a single holder agreeing passes the peer without vouching for its tree.
+	challenge(liar, auditor.Passed)
+
+	honest := startPeer(t, data)
+	register(store, honest, file)
+	if err := store.RemovePeerByHost(accomplice); err != nil {
+		t.Fatalf("expected to remove peer: %s", err)
+	}
+	challenge(liar, auditor.Inconclusive)
+
+	//holders the prober did not reach, or behind the address of the peer, do not count.
+	unprobed := startPeer(t, data)
+	store.RegisterPeer(unprobed, []peerstore.FileMetadata{file})
+	sibling := listenPeer(t, ipOf(t, liar), &peerServiceMock{data: data})
+	register(store, sibling, file)
+	challenge(liar, auditor.Inconclusive)
+
+	//two holders agreeing vouch for the tree, the peer is held to it from now on.
+	if err := store.RemovePeerByHost(liar); err != nil {
+		t.Fatalf("expected to remove peer: %s", err)
+	}
+	second := startPeer(t, data)
+	register(store, second, file)
+	challenge(honest, auditor.Passed)
+
+	for _, host := range []string{sibling, second} {
+		if err := store.RemovePeerByHost(host); err != nil {
+			t.Fatalf("expected to remove peer: %s", err)
+		}
+	}
+	register(store, startPeer(t, forged), file)
+	challenge(honest, auditor.Passed)
+}
+
+func TestAuditAll(t *testing.T) {
+	store := peerstore.New()
+
+	data := []byte("this is the content every holder should keep.")
+	file := peerstore.FileMetadata{Name: "file.txt", Size: int64(len(data)), Checksum: "hash"}
+
+	honest := startPeer(t, data)
+	mock := peerServiceMock{}
+	lost := servePeer(t, &mock)
+	register(store, honest, file)
+	register(store, lost, file)
+
+	a := auditor.New(&auditor.Config{Store: store, Peers: 10})
+	a.AuditAll(context.Background())
+
+	expectHolders := func(hosts ...string) {
+		t.Helper()
+		var got []string
+		for _, p := range store.GetPeersForFile(file.Name) {
+			got = append(got, p.Host)
+		}
+		slices.Sort(got)
+		slices.Sort(hosts)
+		if !slices.Equal(got, hosts) {
+			t.Fatalf("expected %v to keep the file, got %v", hosts, got)
+		}
+	}
+	expectHolders(honest)
+
+	var reported peerstore.Peer
+	for _, p := range store.GetAllPeers() {
+		if p.Host == lost {
+			reported = p
+		}
+	}
+	if reported.Penalty < auditor.DefaultPenalty-0.1 {
+		t.Fatalf("expected %s to be reported, got a penalty of %f", lost, reported.Penalty)
+	}
+
+	//the catalog is left as the peer announced it, syncing it again changes nothing.
+	seq, _, err := store.Catalog(lost)
+	if err != nil {
+		t.Fatalf("expected to get catalog: %s", err)
+	}
+	if seq != 0 {
+		t.Fatalf("seq=0, got %d", seq)
+	}
+	store.UpdatePeer(lost, []peerstore.FileMetadata{file})
+	store.RegisterPeer(lost, []peerstore.FileMetadata{file})
+	expectHolders(honest)
+
+	//the peer is challenged on the content again and wins it back once it proves it.
+	mock.setData(data)
+	a.AuditAll(context.Background())
+	expectHolders(honest, lost)
+
+	//inactive auditors leave peers alone.
+	mock.setData(nil)
+	inactive := auditor.New(&auditor.Config{Store: store, Peers: 10, Active: func() bool { return false }})
+	inactive.AuditAll(context.Background())
+	expectHolders(honest, lost)
+}
+
+// =============================================================================
+// utils
+
+// startPeer serves a peer keeping data as the content of every checksum, a peer without
+// data keeps nothing.
+func startPeer(t *testing.T, data []byte) string {
+	return servePeer(t, &peerServiceMock{data: data})
+}
+
+// servePeer serves a peer on an address of its own, verifiers behind the same address count
+// once.
+func servePeer(t *testing.T, ps *peerServiceMock) string {
+	n := lastIP.Add(1)
+	return listenPeer(t, fmt.Sprintf("127.0.%d.%d", n/250, n%250+1), ps)
+}
+
+// lastIP counts the loopback addresses handed out.
+var lastIP atomic.Int32
+
+// ipOf returns the IP address of a host.
+func ipOf(t *testing.T, host string) string {
+	ip, _, err := net.SplitHostPort(host)
+	if err != nil {
+		t.Fatalf("expected to split host: %s", err)
+	}
+	return ip
+}
+
+func listenPeer(t *testing.T, ip string, ps *peerServiceMock) string {
+	lis, err := net.Listen("tcp", net.JoinHostPort(ip, "0"))
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	server := grpc.NewServer()
+	peer.RegisterPeerServiceServer(server, ps)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+// register registers a host keeping the file, as a peer the prober reached.
+func register(store *peerstore.Store, host string, file peerstore.FileMetadata) {
+	store.RegisterPeer(host, []peerstore.FileMetadata{file})
+	store.RecordProbeSuccess(host, time.Millisecond)
+}
+
+// ==============================================================================
+// mocks
+
+type peerServiceMock struct {
+	peer.UnimplementedPeerServiceServer
+	mu   sync.Mutex
+	data []byte
+	// forge hands out a block other than the one of the tree.
+	forge bool
+}
+
+func (ps *peerServiceMock) setData(data []byte) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.data = data
+}
+
+func (ps *peerServiceMock) ProveStorage(ctx context.Context, in *peer.ProveStorageRequest) (*peer.ProveStorageResponse, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.data == nil {
+		return nil, status.Error(codes.NotFound, "content not found")
+	}
+
+	end := in.Offset + in.Length
+	if end > int64(len(ps.data)) {
+		return nil, status.Error(codes.OutOfRange, "range past the end")
+	}
+
+	sum := sha256.Sum256(append(slices.Clone(in.Nonce), ps.data[in.Offset:end]...))
+	resp := peer.ProveStorageResponse{Proof: hex.EncodeToString(sum[:])}
+	if in.BlockSize <= 0 {
+		return &resp, nil
+	}
+
+	var leaves [][]byte
+	for start := int64(0); start < int64(len(ps.data)); start += in.BlockSize {
+		block := ps.data[start:min(start+in.BlockSize, int64(len(ps.data)))]
+		if start/in.BlockSize == in.BlockIndex {
+			resp.Block = block
+		}
+		leaves = append(leaves, merkle.Leaf(block))
+	}
+	resp.Path = merkle.Proof(leaves, int(in.BlockIndex))
+	resp.Root = merkle.Root(leaves)
+
+	if ps.forge {
+		resp.Block = bytes.Repeat([]byte("x"), len(resp.Block))
+	}
+	return &resp, nil
+}
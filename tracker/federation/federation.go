@@ -0,0 +1,183 @@
+// Package federation lets independent trackers find the files of each other's peers.
+// Every tracker pulls the catalog summaries of its siblings every interval, lookups then
+// hand out the peers registered with them next to the local ones.
+//
+// Only the local peers of a tracker are in its summary, so files are shared between
+// siblings but not passed on to the siblings of siblings.
+package federation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// DefaultInterval is the time between two pulls of the sibling summaries.
+	DefaultInterval = 30 * time.Second
+	// DefaultTimeout is how long a sibling can take to send its summary.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Peer is a peer registered with a sibling tracker.
+type Peer struct {
+	peerstore.Peer
+	// Tracker is the address of the sibling the peer is registered with.
+	Tracker string
+}
+
+// entry is a distinct content under a name and the peers of a sibling serving it.
+type entry struct {
+	file  peerstore.FileMetadata
+	hosts []string
+}
+
+// Federation keeps the last catalog summary of every sibling tracker.
+type Federation struct {
+	id       string
+	siblings []string
+	interval time.Duration
+	timeout  time.Duration
+
+	mu sync.RWMutex
+	// catalogs are the entries of every sibling by file name, siblings that could not
+	// be reached on the last pull have none.
+	catalogs map[string]map[string][]entry
+}
+
+// Config represents all the settings of a federation, zero values fall back to defaults.
+type Config struct {
+	// ID is the address of this tracker, sent along to the siblings.
+	ID string
+	// Siblings are the addresses of the federated trackers.
+	Siblings []string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// New creates a new federation, it knows no remote peers until the first pull.
+func New(conf *Config) *Federation {
+	f := Federation{
+		id:       conf.ID,
+		siblings: conf.Siblings,
+		interval: conf.Interval,
+		timeout:  conf.Timeout,
+		catalogs: make(map[string]map[string][]entry),
+	}
+
+	if f.interval <= 0 {
+		f.interval = DefaultInterval
+	}
+
+	if f.timeout <= 0 {
+		f.timeout = DefaultTimeout
+	}
+	return &f
+}
+
+// Run pulls the summaries of the siblings right away and then every interval until
+// ctx is done.
+func (f *Federation) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		f.SyncAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncAll pulls the summary of every sibling. The peers of a sibling that can not be
+// reached are forgotten until it answers again, they may be gone by now.
+func (f *Federation) SyncAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(len(f.siblings))
+	for _, sibling := range f.siblings {
+		go func() {
+			defer wg.Done()
+
+			catalog, err := f.fetch(ctx, sibling)
+			if err != nil {
+				log.Printf("summary of tracker %s: %s\n", sibling, err)
+			}
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if catalog == nil {
+				delete(f.catalogs, sibling)
+				return
+			}
+			f.catalogs[sibling] = catalog
+		}()
+	}
+	wg.Wait()
+}
+
+// PeersForFile returns the peers of the siblings serving name, in the order siblings
+// are configured. A host known to several siblings is returned once.
+func (f *Federation) PeersForFile(name string) []Peer {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+
+	var peers []Peer
+	for _, sibling := range f.siblings {
+		for _, e := range f.catalogs[sibling][name] {
+			for _, host := range e.hosts {
+				if _, ok := seen[host]; ok {
+					continue
+				}
+				seen[host] = struct{}{}
+
+				peers = append(peers, Peer{
+					Peer:    peerstore.Peer{Host: host, Files: []peerstore.FileMetadata{e.file}},
+					Tracker: sibling,
+				})
+			}
+		}
+	}
+	return peers
+}
+
+// fetch gets the summary of a sibling, by file name.
+func (f *Federation) fetch(ctx context.Context, sibling string) (map[string][]entry, error) {
+	conn, err := grpc.NewClient(sibling, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	client := tracker.NewTrackerServiceClient(conn)
+	resp, err := client.GetCatalogSummary(ctx, &tracker.GetCatalogSummaryRequest{Tracker: f.id})
+	if err != nil {
+		return nil, fmt.Errorf("get catalog summary: %w", err)
+	}
+
+	catalog := make(map[string][]entry)
+	for _, e := range resp.GetEntries() {
+		file := peerstore.FileMetadata{
+			Name:     e.GetFile().GetName(),
+			Size:     e.GetFile().GetSize(),
+			Checksum: e.GetFile().GetChecksum(),
+		}
+		catalog[file.Name] = append(catalog[file.Name], entry{file: file, hosts: slices.Clone(e.GetHosts())})
+	}
+	return catalog, nil
+}
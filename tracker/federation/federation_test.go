@@ -0,0 +1,101 @@
+package federation_test
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/federation"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/service"
+	"google.golang.org/grpc"
+)
+
+func TestGetPeersForFileFederated(t *testing.T) {
+	report := &tracker.File{Name: "report.pdf", Size: 100, Checksum: "v1"}
+	notes := &tracker.File{Name: "notes.txt", Size: 10, Checksum: "notes"}
+
+	siblingStore := peerstore.New()
+	sibling := service.New(&service.Config{Store: siblingStore})
+	register(t, sibling, "10.0.1.1:50051", report, notes)
+	register(t, sibling, "10.0.1.2:50051", report)
+	register(t, sibling, "10.0.1.3:50051", report)
+
+	//unreachable peers are not shared.
+	dead := peerstore.Thresholds{SuspectAfter: 1, DeadAfter: 1}
+	if _, err := siblingStore.RecordProbeFailure("10.0.1.3:50051", dead); err != nil {
+		t.Fatalf("expected to record probe failure: %s", err)
+	}
+
+	addr, stop := serve(t, sibling)
+
+	fed := federation.New(&federation.Config{ID: "local", Siblings: []string{addr}})
+	local := service.New(&service.Config{Store: peerstore.New(), Federation: fed})
+	register(t, local, "10.0.0.1:50051", report)
+	//registered with both trackers, handed out once as a local peer.
+	register(t, local, "10.0.1.2:50051", report)
+
+	fed.SyncAll(context.Background())
+
+	resp, err := local.GetPeersForFile(context.Background(), &tracker.GetPeersForFileRequest{FileName: "report.pdf"})
+	if err != nil {
+		t.Fatalf("expected to get peers for file: %s", err)
+	}
+
+	var localHosts, remoteHosts []string
+	for i, p := range resp.Peers {
+		if !p.Remote {
+			if len(remoteHosts) > 0 {
+				t.Fatalf("expected local peers first, got %s at %d", p.Host, i)
+			}
+			localHosts = append(localHosts, p.Host)
+			continue
+		}
+		if p.Tracker != addr {
+			t.Fatalf("tracker=%s, got %s", addr, p.Tracker)
+		}
+		remoteHosts = append(remoteHosts, p.Host)
+	}
+	slices.Sort(localHosts)
+
+	if !slices.Equal(localHosts, []string{"10.0.0.1:50051", "10.0.1.2:50051"}) {
+		t.Fatalf("expected both local peers, got %v", localHosts)
+	}
+	if !slices.Equal(remoteHosts, []string{"10.0.1.1:50051"}) {
+		t.Fatalf("expected the live remote peer only, got %v", remoteHosts)
+	}
+	if len(resp.Versions) != 1 || resp.Versions[0].PeerCount != 3 {
+		t.Fatalf("expected remote peers to count for the versions, got %v", resp.Versions)
+	}
+
+	//peers of a sibling that went away are forgotten.
+	stop()
+	fed.SyncAll(context.Background())
+
+	if peers := fed.PeersForFile("report.pdf"); len(peers) != 0 {
+		t.Fatalf("expected no remote peers, got %v", peers)
+	}
+}
+
+func register(t *testing.T, s *service.Service, host string, files ...*tracker.File) {
+	if _, err := s.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Files: files}); err != nil {
+		t.Fatalf("expected to register peer[%s]: %s", host, err)
+	}
+}
+
+// serve serves s on a random port, the returned func stops it.
+func serve(t *testing.T, s *service.Service) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected to listen: %s", err)
+	}
+
+	server := grpc.NewServer()
+	tracker.RegisterTrackerServiceServer(server, s)
+	go server.Serve(lis)
+
+	t.Cleanup(server.Stop)
+	return lis.Addr().String(), server.Stop
+}
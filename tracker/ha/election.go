@@ -0,0 +1,254 @@
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/cluster"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+)
+
+// lead keeps leading until another leader outranks this one or ctx is done. Two
+// leaders only exist after a partition, the one with the lower term steps down.
+func (n *Node) lead(ctx context.Context) {
+	ticker := time.NewTicker(n.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n.mu.Lock()
+		term := n.term
+		n.mu.Unlock()
+
+		for _, s := range n.statuses(ctx) {
+			if !s.Leader || !n.outranks(s, term) {
+				continue
+			}
+
+			n.mu.Lock()
+			n.stepDownLocked(s.Id)
+			n.lastContact = time.Now()
+			n.mu.Unlock()
+
+			log.Printf("tracker %s stepping down, %s leads term %d\n", n.id, s.Id, s.Term)
+			return
+		}
+	}
+}
+
+// follow replicates the log of the current leader until it fails. Without a leader
+// for the failover timeout, the reachable member with the most of the log is promoted.
+func (n *Node) follow(ctx context.Context) {
+	statuses := n.statuses(ctx)
+
+	var leader *cluster.StatusResponse
+	for _, s := range statuses {
+		if s.Leader && (leader == nil || s.Term > leader.Term) {
+			leader = s
+		}
+	}
+
+	if leader != nil {
+		n.mu.Lock()
+		n.leaderID = leader.Id
+		n.term = max(n.term, leader.Term)
+		n.mu.Unlock()
+
+		if err := n.replicate(ctx, leader.Id); err != nil && ctx.Err() == nil {
+			log.Printf("tracker %s replicating from %s: %s\n", n.id, leader.Id, err)
+
+			//a leader turning this node down, like one stepping down, is asked again later.
+			select {
+			case <-ctx.Done():
+			case <-time.After(n.checkInterval):
+			}
+		}
+		return
+	}
+
+	n.mu.Lock()
+	silent := time.Since(n.lastContact) >= n.failoverTimeout
+	if silent && n.candidateLocked(statuses) == n.id {
+		var term uint64
+		for _, s := range statuses {
+			term = max(term, s.Term)
+		}
+		n.promoteLocked(term)
+		log.Printf("tracker %s promoted to leader of term %d\n", n.id, n.term)
+		n.mu.Unlock()
+		return
+	}
+	n.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(n.checkInterval):
+	}
+}
+
+// replicate applies the log of leader until the stream fails, or the leader goes
+// silent for the failover timeout.
+func (n *Node) replicate(ctx context.Context, leader string) error {
+	client, conn, err := dial(leader)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	//a leader that stops sending is as good as gone.
+	watchdog := time.AfterFunc(n.failoverTimeout, cancel)
+	defer watchdog.Stop()
+
+	stream, err := client.Replicate(n.withSecret(ctx), &cluster.ReplicateRequest{Follower: n.id})
+	if err != nil {
+		return fmt.Errorf("replicate: %w", err)
+	}
+
+	for {
+		entry, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("recv: %w", err)
+		}
+		watchdog.Reset(n.failoverTimeout)
+
+		if err := n.apply(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// apply applies an entry of the leader's log to the local store.
+func (n *Node) apply(entry *cluster.LogEntry) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.lastContact = time.Now()
+	n.term = max(n.term, entry.Term)
+
+	switch {
+	case entry.Snapshot != nil:
+		var ops []peerstore.Op
+		if err := json.Unmarshal(entry.Snapshot, &ops); err != nil {
+			return fmt.Errorf("unmarshal snapshot: %w", err)
+		}
+		if err := n.restoreLocked(ops); err != nil {
+			return fmt.Errorf("restore snapshot: %w", err)
+		}
+	case entry.Op != nil:
+		var op peerstore.Op
+		if err := json.Unmarshal(entry.Op, &op); err != nil {
+			return fmt.Errorf("unmarshal op: %w", err)
+		}
+		//a follower that can not apply an op drifted, it starts over from a snapshot.
+		if err := n.Store.Apply(op); err != nil {
+			return fmt.Errorf("apply op %d: %w", entry.Index, err)
+		}
+	default:
+		//the leader is alive, nothing changed.
+		return nil
+	}
+
+	n.index = entry.Index
+	return nil
+}
+
+// restoreLocked makes the local store match a snapshot, callers must hold n.mu.
+func (n *Node) restoreLocked(ops []peerstore.Op) error {
+	keep := make(map[string]struct{})
+	for _, op := range ops {
+		if op.Kind == peerstore.OpRegister {
+			keep[op.Host] = struct{}{}
+		}
+	}
+
+	for _, host := range n.Store.Hosts() {
+		if _, ok := keep[host]; ok {
+			continue
+		}
+		if err := n.Store.Apply(peerstore.Op{Kind: peerstore.OpUnRegister, Host: host}); err != nil {
+			return err
+		}
+	}
+
+	for _, op := range ops {
+		if err := n.Store.Apply(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statuses asks every other member for its status, members that can not be reached
+// are left out.
+func (n *Node) statuses(ctx context.Context) []*cluster.StatusResponse {
+	ctx, cancel := context.WithTimeout(ctx, n.checkInterval)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		statuses []*cluster.StatusResponse
+	)
+	for _, member := range n.members {
+		if member == n.id {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			client, conn, err := dial(member)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			s, err := client.Status(n.withSecret(ctx), &cluster.StatusRequest{})
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			statuses = append(statuses, s)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// outranks reports whether the leader behind s wins over this node leading term.
+func (n *Node) outranks(s *cluster.StatusResponse, term uint64) bool {
+	if s.Term != term {
+		return s.Term > term
+	}
+	return slices.Index(n.members, s.Id) < slices.Index(n.members, n.id)
+}
+
+// candidateLocked returns the member to promote among the ones that answered and this
+// node: the one that applied the most of the log, the lowest id on a tie. Promoting a
+// member behind the others would have them drop what they have over its snapshot.
+// Callers must hold n.mu.
+func (n *Node) candidateLocked(statuses []*cluster.StatusResponse) string {
+	best, index := n.id, n.index
+	for _, s := range statuses {
+		if s.Index > index || (s.Index == index && s.Id < best) {
+			best, index = s.Id, s.Index
+		}
+	}
+	return best
+}
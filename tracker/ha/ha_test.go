@@ -0,0 +1,333 @@
+package ha_test
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/ha"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/cluster"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestFailover(t *testing.T) {
+	var (
+		listeners []net.Listener
+		members   []string
+	)
+	for range 3 {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("expected to listen: %s", err)
+		}
+		listeners = append(listeners, lis)
+	}
+
+	//members that applied as much of the log are promoted by id, lowest first.
+	slices.SortFunc(listeners, func(a, b net.Listener) int {
+		return strings.Compare(a.Addr().String(), b.Addr().String())
+	})
+	for _, lis := range listeners {
+		members = append(members, lis.Addr().String())
+	}
+
+	var (
+		nodes []*ha.Node
+		stops []func()
+	)
+	for i := range members {
+		node, stop := startTracker(t, listeners[i], members)
+		nodes = append(nodes, node)
+		stops = append(stops, stop)
+	}
+
+	//the lowest id leads, the others follow it.
+	waitFor(t, "first member to lead", func() bool {
+		return nodes[0].IsLeader() && nodes[1].Leader() == members[0] && nodes[2].Leader() == members[0]
+	})
+
+	first := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash1"}
+	register(t, members[0], "10.0.0.1:50051", first)
+
+	waitFor(t, "followers to replicate the peer", func() bool {
+		return hasPeer(nodes[1], "10.0.0.1:50051") && hasPeer(nodes[2], "10.0.0.1:50051")
+	})
+
+	//drains, bans and unproven contents reach the followers too.
+	if err := nodes[0].SetDraining("10.0.0.1:50051", true); err != nil {
+		t.Fatalf("expected to set draining: %s", err)
+	}
+	if err := nodes[0].SetUnproven("10.0.0.1:50051", first.Checksum, true); err != nil {
+		t.Fatalf("expected to mark content: %s", err)
+	}
+	reputation := peerstore.Reputation{HalfLife: time.Hour, BanThreshold: 1, BanDuration: time.Hour, MinReporters: 1}
+	if _, err := nodes[0].ReportPeer("10.0.0.1:50051", peerstore.Report{Reporter: "a", Penalty: 2}, reputation); err != nil {
+		t.Fatalf("expected to report peer: %s", err)
+	}
+	waitFor(t, "followers to replicate the drain, the ban and the mark", func() bool {
+		for _, node := range nodes[1:] {
+			p, ok := peerOf(node, "10.0.0.1:50051")
+			if !ok || !p.Draining || !time.Now().Before(p.BannedUntil) || len(p.Unproven) != 1 {
+				return false
+			}
+		}
+		return true
+	})
+	if err := nodes[0].SetDraining("10.0.0.1:50051", false); err != nil {
+		t.Fatalf("expected to set draining: %s", err)
+	}
+
+	//followers turn down changes.
+	client := trackerClient(t, members[1])
+	_, err := client.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: "10.0.0.9:50051"})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("code=%s, got %s", codes.Unavailable, status.Code(err))
+	}
+
+	//the second member takes over once the leader is gone.
+	stops[0]()
+	waitFor(t, "second member to lead", func() bool {
+		return nodes[1].IsLeader() && nodes[2].Leader() == members[1]
+	})
+
+	if !hasPeer(nodes[1], "10.0.0.1:50051") {
+		t.Fatal("expected the new leader to keep the replicated peer")
+	}
+
+	second := peerstore.FileMetadata{Name: "file2.txt", Size: 20, Checksum: "hash2"}
+	register(t, members[1], "10.0.0.2:50051", second)
+
+	waitFor(t, "follower to replicate the new peer", func() bool {
+		return hasPeer(nodes[2], "10.0.0.2:50051")
+	})
+}
+
+func TestNoPromotionAtBoot(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected to listen: %s", err)
+	}
+
+	//a restarted member gives the leader a failover timeout to show up before taking over.
+	node, _ := startTracker(t, lis, []string{lis.Addr().String()})
+	time.Sleep(200 * time.Millisecond)
+	if node.IsLeader() {
+		t.Fatal("expected the node not to lead right after starting")
+	}
+
+	waitFor(t, "node to lead", node.IsLeader)
+}
+
+func TestPromoteMostUpToDate(t *testing.T) {
+	var (
+		listeners []net.Listener
+		members   []string
+	)
+	for range 2 {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("expected to listen: %s", err)
+		}
+		listeners = append(listeners, lis)
+		members = append(members, lis.Addr().String())
+	}
+
+	//the member behind the other one is not promoted, whatever its id.
+	behind, _ := startTracker(t, listeners[0], members)
+	ahead, err := ha.New(&ha.Config{Store: peerstore.New(), ID: members[1], Members: members, Secret: secret})
+	if err != nil {
+		t.Fatalf("expected to create node: %s", err)
+	}
+	server := grpc.NewServer()
+	cluster.RegisterClusterServiceServer(server, &indexed{Node: ahead, index: 10})
+	go server.Serve(listeners[1])
+	t.Cleanup(server.Stop)
+
+	time.Sleep(time.Second)
+	if behind.IsLeader() {
+		t.Fatal("expected the member behind not to be promoted")
+	}
+}
+
+func TestNewNotMember(t *testing.T) {
+	_, err := ha.New(&ha.Config{Store: peerstore.New(), ID: "127.0.0.1:1", Members: []string{"127.0.0.1:2"}, Secret: secret})
+	if err == nil {
+		t.Fatal("expected an error for a tracker outside the cluster")
+	}
+}
+
+func TestNewWithoutSecret(t *testing.T) {
+	_, err := ha.New(&ha.Config{Store: peerstore.New(), ID: "127.0.0.1:1", Members: []string{"127.0.0.1:1"}})
+	if err == nil {
+		t.Fatal("expected an error for a cluster without a secret")
+	}
+}
+
+func TestClusterSecret(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected to listen: %s", err)
+	}
+	node, _ := startTracker(t, lis, []string{lis.Addr().String()})
+	waitFor(t, "node to lead", node.IsLeader)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("expected to dial: %s", err)
+	}
+	defer conn.Close()
+	client := cluster.NewClusterServiceClient(conn)
+
+	//callers outside the cluster get neither the role nor the store.
+	for _, ctx := range []context.Context{
+		context.Background(),
+		metadata.AppendToOutgoingContext(context.Background(), ha.SecretKey, "not-the-secret"),
+	} {
+		if _, err := client.Status(ctx, &cluster.StatusRequest{}); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("code=%s, got %v", codes.PermissionDenied, err)
+		}
+
+		stream, err := client.Replicate(ctx, &cluster.ReplicateRequest{Follower: "intruder"})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("code=%s, got %v", codes.PermissionDenied, err)
+		}
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), ha.SecretKey, secret)
+	s, err := client.Status(ctx, &cluster.StatusRequest{})
+	if err != nil || !s.Leader {
+		t.Fatalf("expected members to get the role of the leader, got %v, %v", s, err)
+	}
+}
+
+func TestFollowerRejectsChanges(t *testing.T) {
+	node, err := ha.New(&ha.Config{Store: peerstore.New(), ID: "127.0.0.1:1", Members: []string{"127.0.0.1:1"}, Secret: secret})
+	if err != nil {
+		t.Fatalf("expected to create node: %s", err)
+	}
+
+	if err := node.RegisterPeer("10.0.0.1:50051", nil); err != ha.ErrNotLeader {
+		t.Fatalf("err=%v, got %v", ha.ErrNotLeader, err)
+	}
+
+	if _, err := node.ReportPeer("10.0.0.1:50051", peerstore.Report{Reporter: "a", Penalty: 1}, peerstore.DefaultReputation); err != ha.ErrNotLeader {
+		t.Fatalf("err=%v, got %v", ha.ErrNotLeader, err)
+	}
+
+	expired, err := node.RemoveExpired(0)
+	if err != nil || len(expired) != 0 {
+		t.Fatalf("expected followers not to remove peers, got %v, %v", expired, err)
+	}
+}
+
+// secret is the cluster secret shared by the members in tests.
+const secret = "cluster-secret"
+
+// startTracker serves a tracker backed by a cluster node on lis, the returned func stops it.
+func startTracker(t *testing.T, lis net.Listener, members []string) (*ha.Node, func()) {
+	node, err := ha.New(&ha.Config{
+		Store:           peerstore.New(),
+		ID:              lis.Addr().String(),
+		Members:         members,
+		Secret:          secret,
+		FailoverTimeout: 500 * time.Millisecond,
+		CheckInterval:   50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected to create node: %s", err)
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(node.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(node.StreamInterceptor()),
+	)
+	tracker.RegisterTrackerServiceServer(server, service.New(&service.Config{Store: node}))
+	cluster.RegisterClusterServiceServer(server, node)
+	go server.Serve(lis)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go node.Run(ctx)
+
+	stop := func() {
+		cancel()
+		server.Stop()
+	}
+	t.Cleanup(stop)
+	return node, stop
+}
+
+// indexed reports a node as having applied index ops of the log.
+type indexed struct {
+	*ha.Node
+	index uint64
+}
+
+func (i *indexed) Status(ctx context.Context, in *cluster.StatusRequest) (*cluster.StatusResponse, error) {
+	s, err := i.Node.Status(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s.Index = i.index
+	return s, nil
+}
+
+func trackerClient(t *testing.T, addr string) tracker.TrackerServiceClient {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("expected to dial %s: %s", addr, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return tracker.NewTrackerServiceClient(conn)
+}
+
+func register(t *testing.T, addr string, host string, file peerstore.FileMetadata) {
+	client := trackerClient(t, addr)
+
+	in := tracker.RegisterPeerRequest{
+		Host:  host,
+		Files: []*tracker.File{{Name: file.Name, Size: file.Size, Checksum: file.Checksum}},
+	}
+	resp, err := client.RegisterPeer(context.Background(), &in)
+	if err != nil {
+		t.Fatalf("expected to register peer[%s]: %s", host, err)
+	}
+	if resp.StatusCode != int64(codes.OK) {
+		t.Fatalf("code=%d, got %d: %s", codes.OK, resp.StatusCode, resp.Message)
+	}
+}
+
+func peerOf(node *ha.Node, host string) (peerstore.Peer, bool) {
+	for _, p := range node.GetAllPeers() {
+		if p.Host == host {
+			return p, true
+		}
+	}
+	return peerstore.Peer{}, false
+}
+
+func hasPeer(node *ha.Node, host string) bool {
+	return slices.Contains(node.Hosts(), host)
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
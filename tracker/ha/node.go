@@ -0,0 +1,385 @@
+// Package ha keeps the tracker available when a tracker process fails. Trackers form a
+// cluster where the leader accepts every change of the peer store and ships its op log
+// to the followers, followers serve reads and promote one of them when the leader fails.
+//
+// Replication is asynchronous: changes the leader accepted but did not ship yet are
+// lost on failover. Peers repair that on their own, their heartbeats tell the new leader
+// about catalog drift and peers it does not know register again.
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/cluster"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// DefaultFailoverTimeout is how long followers go without hearing from the leader
+	// before promoting one of them.
+	DefaultFailoverTimeout = 5 * time.Second
+	// DefaultCheckInterval is how often the leader shows followers it is alive and
+	// checks that no other leader took over.
+	DefaultCheckInterval = time.Second
+
+	// SecretKey is the gRPC metadata key members send the cluster secret in.
+	SecretKey = "cluster-secret"
+
+	// logBuffer is the number of ops a follower can lag behind before it is dropped,
+	// a dropped follower connects again and starts over from a snapshot.
+	logBuffer = 1024
+)
+
+// ErrNotLeader is returned by the changes of the store made on a follower.
+var ErrNotLeader = errors.New("not the leader")
+
+// Store is the local peer store of a tracker, ops of the leader are applied to it.
+type Store interface {
+	peerstore.Storer
+	Apply(op peerstore.Op) error
+}
+
+// Node is the member of a cluster running in this tracker. It is the peer store of
+// the tracker: reads are served by the local store, changes are only accepted while
+// leading and shipped to the followers.
+type Node struct {
+	cluster.UnimplementedClusterServiceServer
+	Store
+
+	id              string
+	members         []string
+	secret          string
+	failoverTimeout time.Duration
+	checkInterval   time.Duration
+
+	// mu serializes changes of the store with shipping them, so followers apply ops in
+	// the order the leader did, and guards the role of the node.
+	mu       sync.Mutex
+	leader   bool
+	leaderID string
+	term     uint64
+	index    uint64
+	// lastContact is the last time this node heard from a leader.
+	lastContact time.Time
+	followers   map[chan *cluster.LogEntry]struct{}
+}
+
+var _ peerstore.Storer = (*Node)(nil)
+
+// Config represents all the settings of a node, zero values fall back to defaults.
+type Config struct {
+	Store Store
+	// ID is the address other trackers reach this one on.
+	ID string
+	// Members are the addresses of every tracker of the cluster, this one included. When
+	// no leader is heard of for the failover timeout, the reachable member that applied
+	// the most of the log is promoted.
+	Members []string
+	// Secret is shared by every member, the cluster service only answers callers
+	// presenting it since it streams the whole store.
+	Secret          string
+	FailoverTimeout time.Duration
+	CheckInterval   time.Duration
+}
+
+// New creates a new node, it follows until Run finds or becomes the leader.
+func New(conf *Config) (*Node, error) {
+	if !slices.Contains(conf.Members, conf.ID) {
+		return nil, fmt.Errorf("tracker %s is not a member of the cluster", conf.ID)
+	}
+	if conf.Secret == "" {
+		return nil, errors.New("cluster secret is required")
+	}
+
+	n := Node{
+		Store:           conf.Store,
+		id:              conf.ID,
+		members:         conf.Members,
+		secret:          conf.Secret,
+		failoverTimeout: conf.FailoverTimeout,
+		checkInterval:   conf.CheckInterval,
+		followers:       make(map[chan *cluster.LogEntry]struct{}),
+		//a node that just started gives the leader a failover timeout to show up.
+		lastContact: time.Now(),
+	}
+
+	if n.failoverTimeout <= 0 {
+		n.failoverTimeout = DefaultFailoverTimeout
+	}
+
+	if n.checkInterval <= 0 {
+		n.checkInterval = DefaultCheckInterval
+	}
+	return &n, nil
+}
+
+// IsLeader reports whether this node currently accepts changes.
+func (n *Node) IsLeader() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.leader
+}
+
+// Leader returns the address of the leader as known by this node, empty when unknown.
+func (n *Node) Leader() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.leaderID
+}
+
+// Run leads or follows until ctx is done.
+func (n *Node) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if n.IsLeader() {
+			n.lead(ctx)
+		} else {
+			n.follow(ctx)
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.stepDownLocked("")
+}
+
+// =============================================================================
+// changes of the store, only accepted while leading
+
+// RegisterPeer adds a new peer with its files, in the given namespaces, and ships the change.
+func (n *Node) RegisterPeer(host string, files []peerstore.FileMetadata, namespaces ...string) error {
+	op := peerstore.Op{Kind: peerstore.OpRegister, Host: host, Files: files, Namespaces: namespaces}
+	return n.commit(op, func() error {
+		return n.Store.RegisterPeer(host, files, namespaces...)
+	})
+}
+
+// UpdatePeer replaces the files of a peer and ships the change.
+func (n *Node) UpdatePeer(host string, updates []peerstore.FileMetadata) error {
+	return n.commit(peerstore.Op{Kind: peerstore.OpUpdate, Host: host, Files: updates}, func() error {
+		return n.Store.UpdatePeer(host, updates)
+	})
+}
+
+// RemovePeerByHost removes a peer and ships the change.
+func (n *Node) RemovePeerByHost(host string) error {
+	return n.commit(peerstore.Op{Kind: peerstore.OpUnRegister, Host: host}, func() error {
+		return n.Store.RemovePeerByHost(host)
+	})
+}
+
+// SetCatalogSeq records the sequence number of a peer's catalog and ships the change.
+func (n *Node) SetCatalogSeq(host string, seq uint64) error {
+	return n.commit(peerstore.Op{Kind: peerstore.OpCatalogSeq, Host: host, Seq: seq}, func() error {
+		return n.Store.SetCatalogSeq(host, seq)
+	})
+}
+
+// SetTokenHash records the hash of the token of a peer and ships the change.
+func (n *Node) SetTokenHash(host string, hash string) error {
+	return n.commit(peerstore.Op{Kind: peerstore.OpToken, Host: host, TokenHash: hash}, func() error {
+		return n.Store.SetTokenHash(host, hash)
+	})
+}
+
+// SetLabels replaces the topology labels of a peer and ships the change.
+func (n *Node) SetLabels(host string, labels map[string]string) error {
+	return n.commit(peerstore.Op{Kind: peerstore.OpLabels, Host: host, Labels: labels}, func() error {
+		return n.Store.SetLabels(host, labels)
+	})
+}
+
+// ApplyChanges applies a catalog change and ships it.
+func (n *Node) ApplyChanges(host string, seq uint64, added []peerstore.FileMetadata, removed []string) (string, error) {
+	var digest string
+	op := peerstore.Op{Kind: peerstore.OpAnnounce, Host: host, Files: added, Removed: removed, Seq: seq}
+	err := n.commit(op, func() error {
+		var err error
+		digest, err = n.Store.ApplyChanges(host, seq, added, removed)
+		return err
+	})
+	return digest, err
+}
+
+// SetDraining marks a peer as leaving the network or serving again and ships the change.
+func (n *Node) SetDraining(host string, draining bool) error {
+	return n.commit(peerstore.Op{Kind: peerstore.OpDraining, Host: host, Draining: draining}, func() error {
+		return n.Store.SetDraining(host, draining)
+	})
+}
+
+// SetUnproven marks a content of a peer as one it failed to prove it keeps, or lifts the
+// mark, and ships the change.
+func (n *Node) SetUnproven(host string, checksum string, unproven bool) error {
+	op := peerstore.Op{Kind: peerstore.OpUnproven, Host: host, Checksum: checksum, Unproven: unproven}
+	return n.commit(op, func() error {
+		return n.Store.SetUnproven(host, checksum, unproven)
+	})
+}
+
+// RestoreRecord replaces the record of a host and ships the change.
+func (n *Node) RestoreRecord(host string, rec peerstore.Record) error {
+	op := peerstore.Op{Kind: peerstore.OpRecord, Host: host, Record: &rec}
+	return n.commit(op, func() error {
+		return n.Store.RestoreRecord(host, rec)
+	})
+}
+
+// ReportPeer records a report against a peer and ships it, along with the ban it ended
+// with, so every tracker bans the peer alike.
+func (n *Node) ReportPeer(host string, rep peerstore.Report, r peerstore.Reputation) (peerstore.Standing, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.leader {
+		return peerstore.Standing{}, ErrNotLeader
+	}
+
+	standing, err := n.Store.ReportPeer(host, rep, r)
+	if err != nil {
+		return peerstore.Standing{}, err
+	}
+	n.shipLocked(peerstore.Op{Kind: peerstore.OpReport, Host: host, Report: &rep, Reputation: &r, BannedUntil: &standing.BannedUntil})
+	return standing, nil
+}
+
+// RemoveExpired removes the peers with expired leases and ships their removal. Leases
+// are only renewed on the leader, so followers never remove peers on their own.
+func (n *Node) RemoveExpired(ttl time.Duration) ([]string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.leader {
+		return nil, nil
+	}
+
+	expired, err := n.Store.RemoveExpired(ttl)
+	for _, host := range expired {
+		n.shipLocked(peerstore.Op{Kind: peerstore.OpUnRegister, Host: host})
+	}
+	return expired, err
+}
+
+// commit applies a change to the local store and ships op, the change it describes,
+// to the followers once it succeeded.
+func (n *Node) commit(op peerstore.Op, apply func() error) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.leader {
+		return ErrNotLeader
+	}
+
+	if err := apply(); err != nil {
+		return err
+	}
+	n.shipLocked(op)
+	return nil
+}
+
+// shipLocked hands op to every follower, callers must hold n.mu. Followers that lag
+// too far behind are dropped.
+func (n *Node) shipLocked(op peerstore.Op) {
+	n.index++
+
+	payload, err := json.Marshal(op)
+	if err != nil {
+		//ops are plain data, this is a bug.
+		panic(fmt.Sprintf("marshal op: %s", err))
+	}
+
+	entry := cluster.LogEntry{Index: n.index, Term: n.term, Op: payload}
+	for ch := range n.followers {
+		select {
+		case ch <- &entry:
+		default:
+			close(ch)
+			delete(n.followers, ch)
+		}
+	}
+}
+
+// snapshotLocked returns the ops rebuilding the whole local store, callers must hold n.mu.
+func (n *Node) snapshotLocked() ([]peerstore.Op, error) {
+	var ops []peerstore.Op
+	for _, p := range n.Store.GetAllPeers() {
+		seq, _, err := n.Store.Catalog(p.Host)
+		if err != nil {
+			return nil, fmt.Errorf("catalog: %w", err)
+		}
+		tokenHash, err := n.Store.TokenHash(p.Host)
+		if err != nil {
+			return nil, fmt.Errorf("token hash: %w", err)
+		}
+
+		ops = append(ops,
+			peerstore.Op{Kind: peerstore.OpRegister, Host: p.Host, Files: p.Files, Namespaces: p.Namespaces},
+			peerstore.Op{Kind: peerstore.OpCatalogSeq, Host: p.Host, Seq: seq},
+			peerstore.Op{Kind: peerstore.OpToken, Host: p.Host, TokenHash: tokenHash},
+			peerstore.Op{Kind: peerstore.OpLabels, Host: p.Host, Labels: p.Labels},
+			peerstore.Op{Kind: peerstore.OpDraining, Host: p.Host, Draining: p.Draining},
+		)
+		for _, checksum := range p.Unproven {
+			ops = append(ops, peerstore.Op{Kind: peerstore.OpUnproven, Host: p.Host, Checksum: checksum, Unproven: true})
+		}
+
+		//reports are replayed as of now, the ban comes along as it is.
+		rec := n.Store.Record(p.Host)
+		for _, report := range rec.Reports {
+			ops = append(ops, peerstore.Op{
+				Kind:        peerstore.OpReport,
+				Host:        p.Host,
+				Report:      &report,
+				Reputation:  &peerstore.Reputation{HalfLife: rec.HalfLife},
+				BannedUntil: &rec.BannedUntil,
+			})
+		}
+	}
+	return ops, nil
+}
+
+// promoteLocked makes this node the leader with a term above every term seen so far,
+// callers must hold n.mu.
+func (n *Node) promoteLocked(term uint64) {
+	n.leader = true
+	n.leaderID = n.id
+	n.term = max(n.term, term) + 1
+
+	//leases are only renewed on the leader, peers get a fresh one to send their next heartbeat.
+	for _, host := range n.Store.Hosts() {
+		_ = n.Store.RenewLease(host)
+	}
+}
+
+// stepDownLocked makes this node follow leader, callers must hold n.mu. Followers of
+// this node are dropped, so they look for the new leader.
+func (n *Node) stepDownLocked(leader string) {
+	n.leader = false
+	n.leaderID = leader
+	for ch := range n.followers {
+		close(ch)
+		delete(n.followers, ch)
+	}
+}
+
+// withSecret returns ctx sending the cluster secret to other members.
+func (n *Node) withSecret(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, SecretKey, n.secret)
+}
+
+// dial connects to another member of the cluster.
+func dial(member string) (cluster.ClusterServiceClient, *grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(member, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return cluster.NewClusterServiceClient(conn), conn, nil
+}
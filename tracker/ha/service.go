@@ -0,0 +1,161 @@
+package ha
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/cluster"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// writes are the tracker calls changing the store, only the leader serves them.
+var writes = []string{
+	tracker.TrackerService_RegisterPeer_FullMethodName,
+	tracker.TrackerService_UnRegisterPeer_FullMethodName,
+	tracker.TrackerService_UpdatePeer_FullMethodName,
+	tracker.TrackerService_Heartbeat_FullMethodName,
+	tracker.TrackerService_AnnounceChanges_FullMethodName,
+	tracker.TrackerService_DrainPeer_FullMethodName,
+	tracker.TrackerService_ImportState_FullMethodName,
+	tracker.TrackerService_ReportPeer_FullMethodName,
+}
+
+// Status reports the role of this tracker to other members.
+func (n *Node) Status(ctx context.Context, in *cluster.StatusRequest) (*cluster.StatusResponse, error) {
+	if err := n.checkSecret(ctx); err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return &cluster.StatusResponse{
+		Id:       n.id,
+		Leader:   n.leader,
+		Term:     n.term,
+		LeaderId: n.leaderID,
+		Index:    n.index,
+	}, nil
+}
+
+// Replicate streams a snapshot of the store followed by every op applied after it,
+// along with empty entries every check interval to show the leader is alive. Only other
+// members get it.
+func (n *Node) Replicate(in *cluster.ReplicateRequest, stream grpc.ServerStreamingServer[cluster.LogEntry]) error {
+	if err := n.checkSecret(stream.Context()); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	if !n.leader {
+		leader := n.leaderID
+		n.mu.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "tracker %s is not the leader, leader is %q", n.id, leader)
+	}
+
+	ops, err := n.snapshotLocked()
+	if err != nil {
+		n.mu.Unlock()
+		return status.Errorf(codes.Internal, "snapshot: %s", err)
+	}
+
+	snapshot, err := json.Marshal(ops)
+	if err != nil {
+		n.mu.Unlock()
+		return status.Errorf(codes.Internal, "marshal snapshot: %s", err)
+	}
+
+	first := cluster.LogEntry{Index: n.index, Term: n.term, Snapshot: snapshot}
+	ch := make(chan *cluster.LogEntry, logBuffer)
+	n.followers[ch] = struct{}{}
+	n.mu.Unlock()
+
+	defer n.unfollow(ch)
+
+	if err := stream.Send(&first); err != nil {
+		return status.Errorf(codes.Internal, "send snapshot: %s", err)
+	}
+
+	ticker := time.NewTicker(n.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case entry, ok := <-ch:
+			if !ok {
+				return status.Errorf(codes.Unavailable, "follower %s dropped, lagging behind or leader stepped down", in.GetFollower())
+			}
+			if err := stream.Send(entry); err != nil {
+				return status.Errorf(codes.Internal, "send entry: %s", err)
+			}
+		case <-ticker.C:
+			if err := stream.Send(&cluster.LogEntry{Term: first.Term}); err != nil {
+				return status.Errorf(codes.Internal, "send entry: %s", err)
+			}
+		}
+	}
+}
+
+// UnaryInterceptor rejects the tracker calls changing the store while following, they
+// have to be sent to the leader.
+func (n *Node) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := n.checkWrite(info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is the UnaryInterceptor of streaming calls.
+func (n *Node) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := n.checkWrite(info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (n *Node) checkWrite(method string) error {
+	if !slices.Contains(writes, method) {
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.leader {
+		return status.Errorf(codes.Unavailable, "tracker %s is not the leader, leader is %q", n.id, n.leaderID)
+	}
+	return nil
+}
+
+// checkSecret checks that the caller presented the cluster secret.
+func (n *Node) checkSecret(ctx context.Context) error {
+	for _, secret := range metadata.ValueFromIncomingContext(ctx, SecretKey) {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(n.secret)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.PermissionDenied, "the cluster secret is required")
+}
+
+func (n *Node) unfollow(ch chan *cluster.LogEntry) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.followers[ch]; ok {
+		close(ch)
+		delete(n.followers, ch)
+	}
+}
@@ -1,16 +1,28 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/auditor"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/federation"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/ha"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/cluster"
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/prober"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/ratelimit"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/replicator"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/selection"
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/service"
 	"google.golang.org/grpc"
 )
@@ -33,13 +45,194 @@ func run() error {
 		return fmt.Errorf("listen: %w", err)
 	}
 
+	leaseTTL, err := durationEnv("TRACKER_LEASE_TTL", service.DefaultLeaseTTL)
+	if err != nil {
+		return err
+	}
+
+	heartbeatInterval, err := durationEnv("TRACKER_HEARTBEAT_INTERVAL", service.DefaultHeartbeatInterval)
+	if err != nil {
+		return err
+	}
+
+	probeInterval, err := durationEnv("TRACKER_PROBE_INTERVAL", prober.DefaultInterval)
+	if err != nil {
+		return err
+	}
+
+	snapshotInterval, err := durationEnv("TRACKER_SNAPSHOT_INTERVAL", peerstore.DefaultSnapshotInterval)
+	if err != nil {
+		return err
+	}
+
+	var policy selection.Policy = selection.Random{}
+	if name := os.Getenv("TRACKER_SELECTION_POLICY"); name != "" {
+		policy, err = selection.Parse(name)
+		if err != nil {
+			return fmt.Errorf("environment variable 'TRACKER_SELECTION_POLICY': %w", err)
+		}
+	}
+
+	maxPeers, err := intEnv("TRACKER_MAX_PEERS", service.DefaultMaxPeers)
+	if err != nil {
+		return err
+	}
+
+	maxFiles, err := intEnv("TRACKER_MAX_FILES", service.DefaultMaxFiles)
+	if err != nil {
+		return err
+	}
+
+	//rate limiting is off unless asked for, callers behind the same NAT share a budget
+	var rateLimit ratelimit.Limit
+	if def := os.Getenv("TRACKER_RATE_LIMIT"); def != "" {
+		rateLimit, err = ratelimit.ParseLimit(def)
+		if err != nil {
+			return fmt.Errorf("environment variable 'TRACKER_RATE_LIMIT': %w", err)
+		}
+	}
+
+	methodLimits, err := ratelimit.ParseMethods(os.Getenv("TRACKER_RATE_LIMITS"))
+	if err != nil {
+		return fmt.Errorf("environment variable 'TRACKER_RATE_LIMITS': %w", err)
+	}
+
+	//0 turns replication repair off
+	replicationFactor, err := intEnv("TRACKER_REPLICATION_FACTOR", 0)
+	if err != nil {
+		return err
+	}
+
+	repairInterval, err := durationEnv("TRACKER_REPAIR_INTERVAL", replicator.DefaultInterval)
+	if err != nil {
+		return err
+	}
+
+	//0 turns storage audits off
+	auditPeers, err := intEnv("TRACKER_AUDIT_PEERS", auditor.DefaultPeers)
+	if err != nil {
+		return err
+	}
+
+	auditInterval, err := durationEnv("TRACKER_AUDIT_INTERVAL", auditor.DefaultInterval)
+	if err != nil {
+		return err
+	}
+
+	failoverTimeout, err := durationEnv("TRACKER_FAILOVER_TIMEOUT", ha.DefaultFailoverTimeout)
+	if err != nil {
+		return err
+	}
+
+	federationInterval, err := durationEnv("TRACKER_FEDERATION_INTERVAL", federation.DefaultInterval)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//==========================================================================
+	//peers store, kept on disk when a data dir is configured
+	var local ha.Store = peerstore.New()
+
+	if dataDir := os.Getenv("TRACKER_DATA_DIR"); dataDir != "" {
+		diskStore, err := peerstore.Open(dataDir)
+		if err != nil {
+			return fmt.Errorf("open peer store: %w", err)
+		}
+		defer diskStore.Close()
+
+		log.Printf("peer store loaded from %s with %d peers\n", dataDir, len(diskStore.Hosts()))
+		go diskStore.RunSnapshots(ctx, snapshotInterval)
+		local = diskStore
+	}
+
+	//==========================================================================
+	//every caller gets its own budget of calls per rpc
+	limiter := ratelimit.New(&ratelimit.Config{Limit: rateLimit, Methods: methodLimits})
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(limiter.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(limiter.StreamInterceptor()),
+	}
+
 	//==========================================================================
-	//peers store
-	store := peerstore.New()
-	service := service.New(store)
+	//cluster of trackers, only the leader accepts changes and ships them to followers
+	var store peerstore.Storer = local
+	active := func() bool { return true }
+
+	var node *ha.Node
+	if members := os.Getenv("TRACKER_CLUSTER"); members != "" {
+		id := os.Getenv("TRACKER_ID")
+		if id == "" {
+			id = trackerHost
+		}
+
+		node, err = ha.New(&ha.Config{
+			Store:           local,
+			ID:              id,
+			Members:         strings.Split(members, ","),
+			Secret:          os.Getenv("TRACKER_CLUSTER_SECRET"),
+			FailoverTimeout: failoverTimeout,
+		})
+		if err != nil {
+			return fmt.Errorf("new cluster node: %w", err)
+		}
+		go node.Run(ctx)
+
+		store = node
+		active = node.IsLeader
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(node.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(node.StreamInterceptor()),
+		)
+	}
+
+	conf := service.Config{
+		Store:             store,
+		LeaseTTL:          leaseTTL,
+		HeartbeatInterval: heartbeatInterval,
+		Selection:         policy,
+		MaxPeers:          maxPeers,
+		MaxFiles:          maxFiles,
+		AdminToken:        os.Getenv("TRACKER_ADMIN_TOKEN"),
+	}
+
+	//hand out the peers of sibling trackers too
+	if siblings := os.Getenv("TRACKER_SIBLINGS"); siblings != "" {
+		conf.Federation = federation.New(&federation.Config{
+			ID:       trackerHost,
+			Siblings: strings.Split(siblings, ","),
+			Interval: federationInterval,
+		})
+		go conf.Federation.Run(ctx)
+	}
+	service := service.New(&conf)
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(serverOpts...)
 	tracker.RegisterTrackerServiceServer(server, service)
+	if node != nil {
+		cluster.RegisterClusterServiceServer(server, node)
+	}
+
+	//evict peers that stopped sending heartbeats, followers leave it to the leader
+	go service.RunReaper(ctx, heartbeatInterval)
+
+	//actively check that registered peers are reachable
+	prober := prober.New(&prober.Config{Store: store, Interval: probeInterval})
+	go prober.Run(ctx)
+
+	//copy files served by too few peers to other peers
+	if replicationFactor > 1 {
+		replicator := replicator.New(&replicator.Config{Store: store, Factor: replicationFactor, Interval: repairInterval, Active: active})
+		go replicator.Run(ctx)
+	}
+
+	//challenge peers to prove they keep the files they announced
+	if auditPeers > 0 {
+		audits := auditor.New(&auditor.Config{Store: store, Peers: auditPeers, Interval: auditInterval, Active: active})
+		go audits.Run(ctx)
+	}
 
 	shutdownCh := make(chan os.Signal, 1)
 	signal.Notify(shutdownCh, syscall.SIGTERM, syscall.SIGINT)
@@ -60,3 +253,31 @@ func run() error {
 		return nil
 	}
 }
+
+// durationEnv reads a duration like "30s" from the environment, falling back to def when unset.
+func durationEnv(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable '%s': %w", key, err)
+	}
+	return d, nil
+}
+
+// intEnv reads a number from the environment, falling back to def when unset.
+func intEnv(key string, def int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable '%s': %w", key, err)
+	}
+	return n, nil
+}
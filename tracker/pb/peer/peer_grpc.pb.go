@@ -0,0 +1,403 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.12.4
+// source: peer.proto
+
+package peer
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PeerService_Ping_FullMethodName                   = "/proto.PeerService/Ping"
+	PeerService_CheckFileExistence_FullMethodName     = "/proto.PeerService/CheckFileExistence"
+	PeerService_GetFileMetadata_FullMethodName        = "/proto.PeerService/GetFileMetadata"
+	PeerService_DownloadFile_FullMethodName           = "/proto.PeerService/DownloadFile"
+	PeerService_DownloadFileByChecksum_FullMethodName = "/proto.PeerService/DownloadFileByChecksum"
+	PeerService_UploadFile_FullMethodName             = "/proto.PeerService/UploadFile"
+	PeerService_ReplicateFile_FullMethodName          = "/proto.PeerService/ReplicateFile"
+	PeerService_ProveStorage_FullMethodName           = "/proto.PeerService/ProveStorage"
+)
+
+// PeerServiceClient is the client API for PeerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PeerServiceClient interface {
+	// Ping RPC to check if the peer is online and responsive.
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	// Check if a specific file exists on the peer
+	CheckFileExistence(ctx context.Context, in *CheckFileExistenceRequest, opts ...grpc.CallOption) (*CheckFileExistenceResponse, error)
+	// Get the metadata for a filename.
+	GetFileMetadata(ctx context.Context, in *GetFileMetadataRequest, opts ...grpc.CallOption) (*GetFileMetadataResponse, error)
+	// Download File is used to download a file from another peer chunk by chunk.
+	DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error)
+	// DownloadFileByChecksum is used to download the exact content matching a checksum, whatever its name is.
+	DownloadFileByChecksum(ctx context.Context, in *DownloadFileByChecksumRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error)
+	// UploadFile is used to by client to upload a file into peer.
+	UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse], error)
+	// ReplicateFile is used by the tracker to have the peer pull a copy of a content from other peers, when it has too few replicas.
+	ReplicateFile(ctx context.Context, in *ReplicateFileRequest, opts ...grpc.CallOption) (*ReplicateFileResponse, error)
+	// ProveStorage is used by the tracker to check that the peer really keeps a content it announced, by having it hash a random byte range of it.
+	ProveStorage(ctx context.Context, in *ProveStorageRequest, opts ...grpc.CallOption) (*ProveStorageResponse, error)
+}
+
+type peerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPeerServiceClient(cc grpc.ClientConnInterface) PeerServiceClient {
+	return &peerServiceClient{cc}
+}
+
+func (c *peerServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PingResponse)
+	err := c.cc.Invoke(ctx, PeerService_Ping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) CheckFileExistence(ctx context.Context, in *CheckFileExistenceRequest, opts ...grpc.CallOption) (*CheckFileExistenceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckFileExistenceResponse)
+	err := c.cc.Invoke(ctx, PeerService_CheckFileExistence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) GetFileMetadata(ctx context.Context, in *GetFileMetadataRequest, opts ...grpc.CallOption) (*GetFileMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFileMetadataResponse)
+	err := c.cc.Invoke(ctx, PeerService_GetFileMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PeerService_ServiceDesc.Streams[0], PeerService_DownloadFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadFileRequest, FileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_DownloadFileClient = grpc.ServerStreamingClient[FileChunk]
+
+func (c *peerServiceClient) DownloadFileByChecksum(ctx context.Context, in *DownloadFileByChecksumRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PeerService_ServiceDesc.Streams[1], PeerService_DownloadFileByChecksum_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadFileByChecksumRequest, FileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_DownloadFileByChecksumClient = grpc.ServerStreamingClient[FileChunk]
+
+func (c *peerServiceClient) UploadFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PeerService_ServiceDesc.Streams[2], PeerService_UploadFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadFileChunk, UploadFileResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_UploadFileClient = grpc.ClientStreamingClient[UploadFileChunk, UploadFileResponse]
+
+func (c *peerServiceClient) ReplicateFile(ctx context.Context, in *ReplicateFileRequest, opts ...grpc.CallOption) (*ReplicateFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplicateFileResponse)
+	err := c.cc.Invoke(ctx, PeerService_ReplicateFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) ProveStorage(ctx context.Context, in *ProveStorageRequest, opts ...grpc.CallOption) (*ProveStorageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProveStorageResponse)
+	err := c.cc.Invoke(ctx, PeerService_ProveStorage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PeerServiceServer is the server API for PeerService service.
+// All implementations must embed UnimplementedPeerServiceServer
+// for forward compatibility.
+type PeerServiceServer interface {
+	// Ping RPC to check if the peer is online and responsive.
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	// Check if a specific file exists on the peer
+	CheckFileExistence(context.Context, *CheckFileExistenceRequest) (*CheckFileExistenceResponse, error)
+	// Get the metadata for a filename.
+	GetFileMetadata(context.Context, *GetFileMetadataRequest) (*GetFileMetadataResponse, error)
+	// Download File is used to download a file from another peer chunk by chunk.
+	DownloadFile(*DownloadFileRequest, grpc.ServerStreamingServer[FileChunk]) error
+	// DownloadFileByChecksum is used to download the exact content matching a checksum, whatever its name is.
+	DownloadFileByChecksum(*DownloadFileByChecksumRequest, grpc.ServerStreamingServer[FileChunk]) error
+	// UploadFile is used to by client to upload a file into peer.
+	UploadFile(grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]) error
+	// ReplicateFile is used by the tracker to have the peer pull a copy of a content from other peers, when it has too few replicas.
+	ReplicateFile(context.Context, *ReplicateFileRequest) (*ReplicateFileResponse, error)
+	// ProveStorage is used by the tracker to check that the peer really keeps a content it announced, by having it hash a random byte range of it.
+	ProveStorage(context.Context, *ProveStorageRequest) (*ProveStorageResponse, error)
+	mustEmbedUnimplementedPeerServiceServer()
+}
+
+// UnimplementedPeerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPeerServiceServer struct{}
+
+func (UnimplementedPeerServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedPeerServiceServer) CheckFileExistence(context.Context, *CheckFileExistenceRequest) (*CheckFileExistenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckFileExistence not implemented")
+}
+func (UnimplementedPeerServiceServer) GetFileMetadata(context.Context, *GetFileMetadataRequest) (*GetFileMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileMetadata not implemented")
+}
+func (UnimplementedPeerServiceServer) DownloadFile(*DownloadFileRequest, grpc.ServerStreamingServer[FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
+}
+func (UnimplementedPeerServiceServer) DownloadFileByChecksum(*DownloadFileByChecksumRequest, grpc.ServerStreamingServer[FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadFileByChecksum not implemented")
+}
+func (UnimplementedPeerServiceServer) UploadFile(grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
+}
+func (UnimplementedPeerServiceServer) ReplicateFile(context.Context, *ReplicateFileRequest) (*ReplicateFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplicateFile not implemented")
+}
+func (UnimplementedPeerServiceServer) ProveStorage(context.Context, *ProveStorageRequest) (*ProveStorageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProveStorage not implemented")
+}
+func (UnimplementedPeerServiceServer) mustEmbedUnimplementedPeerServiceServer() {}
+func (UnimplementedPeerServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafePeerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PeerServiceServer will
+// result in compilation errors.
+type UnsafePeerServiceServer interface {
+	mustEmbedUnimplementedPeerServiceServer()
+}
+
+func RegisterPeerServiceServer(s grpc.ServiceRegistrar, srv PeerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPeerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PeerService_ServiceDesc, srv)
+}
+
+func _PeerService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_CheckFileExistence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckFileExistenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).CheckFileExistence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_CheckFileExistence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).CheckFileExistence(ctx, req.(*CheckFileExistenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_GetFileMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).GetFileMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_GetFileMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).GetFileMetadata(ctx, req.(*GetFileMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_DownloadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PeerServiceServer).DownloadFile(m, &grpc.GenericServerStream[DownloadFileRequest, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_DownloadFileServer = grpc.ServerStreamingServer[FileChunk]
+
+func _PeerService_DownloadFileByChecksum_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadFileByChecksumRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PeerServiceServer).DownloadFileByChecksum(m, &grpc.GenericServerStream[DownloadFileByChecksumRequest, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_DownloadFileByChecksumServer = grpc.ServerStreamingServer[FileChunk]
+
+func _PeerService_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PeerServiceServer).UploadFile(&grpc.GenericServerStream[UploadFileChunk, UploadFileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_UploadFileServer = grpc.ClientStreamingServer[UploadFileChunk, UploadFileResponse]
+
+func _PeerService_ReplicateFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplicateFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).ReplicateFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_ReplicateFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).ReplicateFile(ctx, req.(*ReplicateFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_ProveStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProveStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).ProveStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_ProveStorage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).ProveStorage(ctx, req.(*ProveStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PeerService_ServiceDesc is the grpc.ServiceDesc for PeerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.PeerService",
+	HandlerType: (*PeerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ping",
+			Handler:    _PeerService_Ping_Handler,
+		},
+		{
+			MethodName: "CheckFileExistence",
+			Handler:    _PeerService_CheckFileExistence_Handler,
+		},
+		{
+			MethodName: "GetFileMetadata",
+			Handler:    _PeerService_GetFileMetadata_Handler,
+		},
+		{
+			MethodName: "ReplicateFile",
+			Handler:    _PeerService_ReplicateFile_Handler,
+		},
+		{
+			MethodName: "ProveStorage",
+			Handler:    _PeerService_ProveStorage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DownloadFile",
+			Handler:       _PeerService_DownloadFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadFileByChecksum",
+			Handler:       _PeerService_DownloadFileByChecksum_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadFile",
+			Handler:       _PeerService_UploadFile_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "peer.proto",
+}
@@ -19,11 +19,25 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TrackerService_RegisterPeer_FullMethodName    = "/proto.TrackerService/RegisterPeer"
-	TrackerService_UnRegisterPeer_FullMethodName  = "/proto.TrackerService/UnRegisterPeer"
-	TrackerService_GetPeers_FullMethodName        = "/proto.TrackerService/GetPeers"
-	TrackerService_GetPeersForFile_FullMethodName = "/proto.TrackerService/GetPeersForFile"
-	TrackerService_UpdatePeer_FullMethodName      = "/proto.TrackerService/UpdatePeer"
+	TrackerService_RegisterPeer_FullMethodName        = "/proto.TrackerService/RegisterPeer"
+	TrackerService_UnRegisterPeer_FullMethodName      = "/proto.TrackerService/UnRegisterPeer"
+	TrackerService_GetPeers_FullMethodName            = "/proto.TrackerService/GetPeers"
+	TrackerService_ListPeers_FullMethodName           = "/proto.TrackerService/ListPeers"
+	TrackerService_GetPeersForFile_FullMethodName     = "/proto.TrackerService/GetPeersForFile"
+	TrackerService_GetPeersForChecksum_FullMethodName = "/proto.TrackerService/GetPeersForChecksum"
+	TrackerService_GetFileVersions_FullMethodName     = "/proto.TrackerService/GetFileVersions"
+	TrackerService_UpdatePeer_FullMethodName          = "/proto.TrackerService/UpdatePeer"
+	TrackerService_Heartbeat_FullMethodName           = "/proto.TrackerService/Heartbeat"
+	TrackerService_AnnounceChanges_FullMethodName     = "/proto.TrackerService/AnnounceChanges"
+	TrackerService_SearchFiles_FullMethodName         = "/proto.TrackerService/SearchFiles"
+	TrackerService_WatchEvents_FullMethodName         = "/proto.TrackerService/WatchEvents"
+	TrackerService_GetStats_FullMethodName            = "/proto.TrackerService/GetStats"
+	TrackerService_ListConflicts_FullMethodName       = "/proto.TrackerService/ListConflicts"
+	TrackerService_DrainPeer_FullMethodName           = "/proto.TrackerService/DrainPeer"
+	TrackerService_GetCatalogSummary_FullMethodName   = "/proto.TrackerService/GetCatalogSummary"
+	TrackerService_ExportState_FullMethodName         = "/proto.TrackerService/ExportState"
+	TrackerService_ImportState_FullMethodName         = "/proto.TrackerService/ImportState"
+	TrackerService_ReportPeer_FullMethodName          = "/proto.TrackerService/ReportPeer"
 )
 
 // TrackerServiceClient is the client API for TrackerService service.
@@ -33,8 +47,40 @@ type TrackerServiceClient interface {
 	RegisterPeer(ctx context.Context, in *RegisterPeerRequest, opts ...grpc.CallOption) (*RegisterPeerResponse, error)
 	UnRegisterPeer(ctx context.Context, in *UnRegisterPeerRequest, opts ...grpc.CallOption) (*UnRegisterPeerResponse, error)
 	GetPeers(ctx context.Context, in *GetPeersRequest, opts ...grpc.CallOption) (*GetPeersResponse, error)
+	// ListPeers streams every peer one at a time, for networks too big for a single response.
+	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Peer], error)
 	GetPeersForFile(ctx context.Context, in *GetPeersForFileRequest, opts ...grpc.CallOption) (*GetPeersResponse, error)
+	// GetPeersForChecksum returns the peers holding the exact content, whatever they named it.
+	GetPeersForChecksum(ctx context.Context, in *GetPeersForChecksumRequest, opts ...grpc.CallOption) (*GetPeersResponse, error)
+	// GetFileVersions returns the distinct contents peers keep under a file name, unlike
+	// GetPeersForFile it does not count as a lookup of the file.
+	GetFileVersions(ctx context.Context, in *GetFileVersionsRequest, opts ...grpc.CallOption) (*GetFileVersionsResponse, error)
 	UpdatePeer(ctx context.Context, in *UpdatePeerRequest, opts ...grpc.CallOption) (*UpdatePeerResponse, error)
+	// Heartbeat renews the lease of a registered peer.
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	// AnnounceChanges applies the files a peer added or removed since its last announcement.
+	AnnounceChanges(ctx context.Context, in *AnnounceChangesRequest, opts ...grpc.CallOption) (*AnnounceChangesResponse, error)
+	// SearchFiles finds files on the network by name pattern and size.
+	SearchFiles(ctx context.Context, in *SearchFilesRequest, opts ...grpc.CallOption) (*SearchFilesResponse, error)
+	// WatchEvents streams changes of the network as they happen.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// GetStats reports the size and health of the network.
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	// ListConflicts lists the file names peers keep different contents under.
+	ListConflicts(ctx context.Context, in *ListConflictsRequest, opts ...grpc.CallOption) (*ListConflictsResponse, error)
+	// DrainPeer takes a peer out of the network without losing files: it is no longer handed
+	// out, the files only it serves are copied to other peers, then it is unregistered.
+	DrainPeer(ctx context.Context, in *DrainPeerRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DrainProgress], error)
+	// GetCatalogSummary lists the files served by the peers of this tracker, federated
+	// trackers hand those peers out next to their own.
+	GetCatalogSummary(ctx context.Context, in *GetCatalogSummaryRequest, opts ...grpc.CallOption) (*GetCatalogSummaryResponse, error)
+	// ExportState dumps every peer and file of the tracker into a versioned JSON snapshot.
+	ExportState(ctx context.Context, in *ExportStateRequest, opts ...grpc.CallOption) (*ExportStateResponse, error)
+	// ImportState loads a snapshot made by ExportState into a tracker without peers.
+	ImportState(ctx context.Context, in *ImportStateRequest, opts ...grpc.CallOption) (*ImportStateResponse, error)
+	// ReportPeer records that a peer misbehaved while serving a download, peers reported
+	// too often are handed out last, then banned for a while.
+	ReportPeer(ctx context.Context, in *ReportPeerRequest, opts ...grpc.CallOption) (*ReportPeerResponse, error)
 }
 
 type trackerServiceClient struct {
@@ -75,6 +121,25 @@ func (c *trackerServiceClient) GetPeers(ctx context.Context, in *GetPeersRequest
 	return out, nil
 }
 
+func (c *trackerServiceClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Peer], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrackerService_ServiceDesc.Streams[0], TrackerService_ListPeers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListPeersRequest, Peer]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrackerService_ListPeersClient = grpc.ServerStreamingClient[Peer]
+
 func (c *trackerServiceClient) GetPeersForFile(ctx context.Context, in *GetPeersForFileRequest, opts ...grpc.CallOption) (*GetPeersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetPeersResponse)
@@ -85,6 +150,26 @@ func (c *trackerServiceClient) GetPeersForFile(ctx context.Context, in *GetPeers
 	return out, nil
 }
 
+func (c *trackerServiceClient) GetPeersForChecksum(ctx context.Context, in *GetPeersForChecksumRequest, opts ...grpc.CallOption) (*GetPeersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPeersResponse)
+	err := c.cc.Invoke(ctx, TrackerService_GetPeersForChecksum_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) GetFileVersions(ctx context.Context, in *GetFileVersionsRequest, opts ...grpc.CallOption) (*GetFileVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFileVersionsResponse)
+	err := c.cc.Invoke(ctx, TrackerService_GetFileVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trackerServiceClient) UpdatePeer(ctx context.Context, in *UpdatePeerRequest, opts ...grpc.CallOption) (*UpdatePeerResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdatePeerResponse)
@@ -95,6 +180,134 @@ func (c *trackerServiceClient) UpdatePeer(ctx context.Context, in *UpdatePeerReq
 	return out, nil
 }
 
+func (c *trackerServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, TrackerService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) AnnounceChanges(ctx context.Context, in *AnnounceChangesRequest, opts ...grpc.CallOption) (*AnnounceChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnnounceChangesResponse)
+	err := c.cc.Invoke(ctx, TrackerService_AnnounceChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) SearchFiles(ctx context.Context, in *SearchFilesRequest, opts ...grpc.CallOption) (*SearchFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchFilesResponse)
+	err := c.cc.Invoke(ctx, TrackerService_SearchFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrackerService_ServiceDesc.Streams[1], TrackerService_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrackerService_WatchEventsClient = grpc.ServerStreamingClient[Event]
+
+func (c *trackerServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, TrackerService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) ListConflicts(ctx context.Context, in *ListConflictsRequest, opts ...grpc.CallOption) (*ListConflictsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConflictsResponse)
+	err := c.cc.Invoke(ctx, TrackerService_ListConflicts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) DrainPeer(ctx context.Context, in *DrainPeerRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DrainProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrackerService_ServiceDesc.Streams[2], TrackerService_DrainPeer_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DrainPeerRequest, DrainProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrackerService_DrainPeerClient = grpc.ServerStreamingClient[DrainProgress]
+
+func (c *trackerServiceClient) GetCatalogSummary(ctx context.Context, in *GetCatalogSummaryRequest, opts ...grpc.CallOption) (*GetCatalogSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCatalogSummaryResponse)
+	err := c.cc.Invoke(ctx, TrackerService_GetCatalogSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) ExportState(ctx context.Context, in *ExportStateRequest, opts ...grpc.CallOption) (*ExportStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportStateResponse)
+	err := c.cc.Invoke(ctx, TrackerService_ExportState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) ImportState(ctx context.Context, in *ImportStateRequest, opts ...grpc.CallOption) (*ImportStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportStateResponse)
+	err := c.cc.Invoke(ctx, TrackerService_ImportState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerServiceClient) ReportPeer(ctx context.Context, in *ReportPeerRequest, opts ...grpc.CallOption) (*ReportPeerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportPeerResponse)
+	err := c.cc.Invoke(ctx, TrackerService_ReportPeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TrackerServiceServer is the server API for TrackerService service.
 // All implementations must embed UnimplementedTrackerServiceServer
 // for forward compatibility.
@@ -102,8 +315,40 @@ type TrackerServiceServer interface {
 	RegisterPeer(context.Context, *RegisterPeerRequest) (*RegisterPeerResponse, error)
 	UnRegisterPeer(context.Context, *UnRegisterPeerRequest) (*UnRegisterPeerResponse, error)
 	GetPeers(context.Context, *GetPeersRequest) (*GetPeersResponse, error)
+	// ListPeers streams every peer one at a time, for networks too big for a single response.
+	ListPeers(*ListPeersRequest, grpc.ServerStreamingServer[Peer]) error
 	GetPeersForFile(context.Context, *GetPeersForFileRequest) (*GetPeersResponse, error)
+	// GetPeersForChecksum returns the peers holding the exact content, whatever they named it.
+	GetPeersForChecksum(context.Context, *GetPeersForChecksumRequest) (*GetPeersResponse, error)
+	// GetFileVersions returns the distinct contents peers keep under a file name, unlike
+	// GetPeersForFile it does not count as a lookup of the file.
+	GetFileVersions(context.Context, *GetFileVersionsRequest) (*GetFileVersionsResponse, error)
 	UpdatePeer(context.Context, *UpdatePeerRequest) (*UpdatePeerResponse, error)
+	// Heartbeat renews the lease of a registered peer.
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	// AnnounceChanges applies the files a peer added or removed since its last announcement.
+	AnnounceChanges(context.Context, *AnnounceChangesRequest) (*AnnounceChangesResponse, error)
+	// SearchFiles finds files on the network by name pattern and size.
+	SearchFiles(context.Context, *SearchFilesRequest) (*SearchFilesResponse, error)
+	// WatchEvents streams changes of the network as they happen.
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error
+	// GetStats reports the size and health of the network.
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	// ListConflicts lists the file names peers keep different contents under.
+	ListConflicts(context.Context, *ListConflictsRequest) (*ListConflictsResponse, error)
+	// DrainPeer takes a peer out of the network without losing files: it is no longer handed
+	// out, the files only it serves are copied to other peers, then it is unregistered.
+	DrainPeer(*DrainPeerRequest, grpc.ServerStreamingServer[DrainProgress]) error
+	// GetCatalogSummary lists the files served by the peers of this tracker, federated
+	// trackers hand those peers out next to their own.
+	GetCatalogSummary(context.Context, *GetCatalogSummaryRequest) (*GetCatalogSummaryResponse, error)
+	// ExportState dumps every peer and file of the tracker into a versioned JSON snapshot.
+	ExportState(context.Context, *ExportStateRequest) (*ExportStateResponse, error)
+	// ImportState loads a snapshot made by ExportState into a tracker without peers.
+	ImportState(context.Context, *ImportStateRequest) (*ImportStateResponse, error)
+	// ReportPeer records that a peer misbehaved while serving a download, peers reported
+	// too often are handed out last, then banned for a while.
+	ReportPeer(context.Context, *ReportPeerRequest) (*ReportPeerResponse, error)
 	mustEmbedUnimplementedTrackerServiceServer()
 }
 
@@ -123,12 +368,54 @@ func (UnimplementedTrackerServiceServer) UnRegisterPeer(context.Context, *UnRegi
 func (UnimplementedTrackerServiceServer) GetPeers(context.Context, *GetPeersRequest) (*GetPeersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPeers not implemented")
 }
+func (UnimplementedTrackerServiceServer) ListPeers(*ListPeersRequest, grpc.ServerStreamingServer[Peer]) error {
+	return status.Errorf(codes.Unimplemented, "method ListPeers not implemented")
+}
 func (UnimplementedTrackerServiceServer) GetPeersForFile(context.Context, *GetPeersForFileRequest) (*GetPeersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPeersForFile not implemented")
 }
+func (UnimplementedTrackerServiceServer) GetPeersForChecksum(context.Context, *GetPeersForChecksumRequest) (*GetPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeersForChecksum not implemented")
+}
+func (UnimplementedTrackerServiceServer) GetFileVersions(context.Context, *GetFileVersionsRequest) (*GetFileVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileVersions not implemented")
+}
 func (UnimplementedTrackerServiceServer) UpdatePeer(context.Context, *UpdatePeerRequest) (*UpdatePeerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdatePeer not implemented")
 }
+func (UnimplementedTrackerServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedTrackerServiceServer) AnnounceChanges(context.Context, *AnnounceChangesRequest) (*AnnounceChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnnounceChanges not implemented")
+}
+func (UnimplementedTrackerServiceServer) SearchFiles(context.Context, *SearchFilesRequest) (*SearchFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchFiles not implemented")
+}
+func (UnimplementedTrackerServiceServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedTrackerServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedTrackerServiceServer) ListConflicts(context.Context, *ListConflictsRequest) (*ListConflictsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConflicts not implemented")
+}
+func (UnimplementedTrackerServiceServer) DrainPeer(*DrainPeerRequest, grpc.ServerStreamingServer[DrainProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method DrainPeer not implemented")
+}
+func (UnimplementedTrackerServiceServer) GetCatalogSummary(context.Context, *GetCatalogSummaryRequest) (*GetCatalogSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCatalogSummary not implemented")
+}
+func (UnimplementedTrackerServiceServer) ExportState(context.Context, *ExportStateRequest) (*ExportStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportState not implemented")
+}
+func (UnimplementedTrackerServiceServer) ImportState(context.Context, *ImportStateRequest) (*ImportStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportState not implemented")
+}
+func (UnimplementedTrackerServiceServer) ReportPeer(context.Context, *ReportPeerRequest) (*ReportPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportPeer not implemented")
+}
 func (UnimplementedTrackerServiceServer) mustEmbedUnimplementedTrackerServiceServer() {}
 func (UnimplementedTrackerServiceServer) testEmbeddedByValue()                        {}
 
@@ -204,6 +491,17 @@ func _TrackerService_GetPeers_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrackerService_ListPeers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPeersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrackerServiceServer).ListPeers(m, &grpc.GenericServerStream[ListPeersRequest, Peer]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrackerService_ListPeersServer = grpc.ServerStreamingServer[Peer]
+
 func _TrackerService_GetPeersForFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetPeersForFileRequest)
 	if err := dec(in); err != nil {
@@ -222,6 +520,42 @@ func _TrackerService_GetPeersForFile_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrackerService_GetPeersForChecksum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeersForChecksumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).GetPeersForChecksum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_GetPeersForChecksum_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).GetPeersForChecksum(ctx, req.(*GetPeersForChecksumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_GetFileVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).GetFileVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_GetFileVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).GetFileVersions(ctx, req.(*GetFileVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrackerService_UpdatePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdatePeerRequest)
 	if err := dec(in); err != nil {
@@ -240,6 +574,190 @@ func _TrackerService_UpdatePeer_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrackerService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_AnnounceChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnounceChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).AnnounceChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_AnnounceChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).AnnounceChanges(ctx, req.(*AnnounceChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_SearchFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).SearchFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_SearchFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).SearchFiles(ctx, req.(*SearchFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrackerServiceServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrackerService_WatchEventsServer = grpc.ServerStreamingServer[Event]
+
+func _TrackerService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_ListConflicts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConflictsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).ListConflicts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_ListConflicts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).ListConflicts(ctx, req.(*ListConflictsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_DrainPeer_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DrainPeerRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrackerServiceServer).DrainPeer(m, &grpc.GenericServerStream[DrainPeerRequest, DrainProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrackerService_DrainPeerServer = grpc.ServerStreamingServer[DrainProgress]
+
+func _TrackerService_GetCatalogSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatalogSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).GetCatalogSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_GetCatalogSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).GetCatalogSummary(ctx, req.(*GetCatalogSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_ExportState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).ExportState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_ExportState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).ExportState(ctx, req.(*ExportStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_ImportState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).ImportState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_ImportState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).ImportState(ctx, req.(*ImportStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackerService_ReportPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServiceServer).ReportPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackerService_ReportPeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServiceServer).ReportPeer(ctx, req.(*ReportPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TrackerService_ServiceDesc is the grpc.ServiceDesc for TrackerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -263,11 +781,71 @@ var TrackerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPeersForFile",
 			Handler:    _TrackerService_GetPeersForFile_Handler,
 		},
+		{
+			MethodName: "GetPeersForChecksum",
+			Handler:    _TrackerService_GetPeersForChecksum_Handler,
+		},
+		{
+			MethodName: "GetFileVersions",
+			Handler:    _TrackerService_GetFileVersions_Handler,
+		},
 		{
 			MethodName: "UpdatePeer",
 			Handler:    _TrackerService_UpdatePeer_Handler,
 		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _TrackerService_Heartbeat_Handler,
+		},
+		{
+			MethodName: "AnnounceChanges",
+			Handler:    _TrackerService_AnnounceChanges_Handler,
+		},
+		{
+			MethodName: "SearchFiles",
+			Handler:    _TrackerService_SearchFiles_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _TrackerService_GetStats_Handler,
+		},
+		{
+			MethodName: "ListConflicts",
+			Handler:    _TrackerService_ListConflicts_Handler,
+		},
+		{
+			MethodName: "GetCatalogSummary",
+			Handler:    _TrackerService_GetCatalogSummary_Handler,
+		},
+		{
+			MethodName: "ExportState",
+			Handler:    _TrackerService_ExportState_Handler,
+		},
+		{
+			MethodName: "ImportState",
+			Handler:    _TrackerService_ImportState_Handler,
+		},
+		{
+			MethodName: "ReportPeer",
+			Handler:    _TrackerService_ReportPeer_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPeers",
+			Handler:       _TrackerService_ListPeers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _TrackerService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DrainPeer",
+			Handler:       _TrackerService_DrainPeer_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "tracker.proto",
 }
@@ -0,0 +1,42 @@
+package peerstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Digest is an order independent fingerprint of a catalog: the xor of the hashes of
+// every file. Toggling the same file twice cancels out, so it is updated one change at
+// a time instead of rehashing the whole catalog. Peers compute it the same way.
+type Digest [sha256.Size]byte
+
+// Toggle adds the file to the digest, or removes it when it was already part of it.
+func (d *Digest) Toggle(file FileMetadata) {
+	h := sha256.New()
+	h.Write([]byte(file.Name))
+	h.Write([]byte{0})
+	h.Write([]byte(file.Checksum))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(file.Size, 10)))
+
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	for i := range d {
+		d[i] ^= sum[i]
+	}
+}
+
+// String returns the hex representation of the digest.
+func (d Digest) String() string {
+	return hex.EncodeToString(d[:])
+}
+
+// digestOf computes the digest of a whole catalog.
+func digestOf(files Files) Digest {
+	var d Digest
+	for _, file := range files {
+		d.Toggle(file)
+	}
+	return d
+}
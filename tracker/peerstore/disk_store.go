@@ -0,0 +1,466 @@
+package peerstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// WALFile is the name of the write-ahead log inside the data dir.
+	WALFile = "peers.wal"
+	// SnapshotFile is the name of the compacted snapshot inside the data dir.
+	SnapshotFile = "peers.snapshot"
+	// DefaultSnapshotInterval is how often the write-ahead log is compacted.
+	DefaultSnapshotInterval = 5 * time.Minute
+
+	// headerSize is the length prefix plus the crc32 of every wal record.
+	headerSize = 8
+	// maxRecordSize guards against allocating garbage lengths of a torn header.
+	maxRecordSize = 64 * 1024 * 1024
+)
+
+// DiskStore is a Store that survives restarts. Every mutation is appended to a
+// write-ahead log before it is applied in memory, and the log is periodically
+// compacted into a snapshot. Leases, probe results, loads and draining are not persisted,
+// peers replayed on boot get a fresh lease to send their next heartbeat. Reports are, and
+// fade away from the replay on.
+type DiskStore struct {
+	*Store
+
+	// mu serializes wal appends with the in memory apply, so the log order is
+	// always the order the store saw.
+	mu  sync.Mutex
+	dir string
+	wal *os.File
+}
+
+var _ Storer = (*DiskStore)(nil)
+
+// Open loads the store kept in dir, replaying the snapshot and the log on top of it.
+// A record that was only partially written when the tracker crashed is dropped.
+func Open(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("mkdir: %w", err)
+	}
+
+	store := New()
+	if err := loadSnapshot(filepath.Join(dir, SnapshotFile), store); err != nil {
+		return nil, fmt.Errorf("load snapshot: %w", err)
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, WALFile), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open wal: %w", err)
+	}
+
+	if err := replay(wal, store); err != nil {
+		wal.Close()
+		return nil, fmt.Errorf("replay wal: %w", err)
+	}
+
+	return &DiskStore{
+		Store: store,
+		dir:   dir,
+		wal:   wal,
+	}, nil
+}
+
+// RegisterPeer logs and adds a new peer with its files, in the given namespaces.
+func (d *DiskStore) RegisterPeer(host string, files []FileMetadata, namespaces ...string) error {
+	return d.commit(Op{Kind: OpRegister, Host: host, Files: files, Namespaces: namespaces})
+}
+
+// UpdatePeer logs and replaces the files of a peer.
+func (d *DiskStore) UpdatePeer(host string, updates []FileMetadata) error {
+	return d.commit(Op{Kind: OpUpdate, Host: host, Files: updates})
+}
+
+// SetCatalogSeq logs and records the sequence number of a peer's catalog.
+func (d *DiskStore) SetCatalogSeq(host string, seq uint64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.Store.GetPeerByHost(host); err != nil {
+		return err
+	}
+	return d.appendAndApply(Op{Kind: OpCatalogSeq, Host: host, Seq: seq})
+}
+
+// SetTokenHash logs and records the hash of the token of a peer.
+func (d *DiskStore) SetTokenHash(host string, hash string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.Store.GetPeerByHost(host); err != nil {
+		return err
+	}
+	return d.appendAndApply(Op{Kind: OpToken, Host: host, TokenHash: hash})
+}
+
+// SetLabels logs and replaces the topology labels of a peer.
+func (d *DiskStore) SetLabels(host string, labels map[string]string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.Store.GetPeerByHost(host); err != nil {
+		return err
+	}
+	return d.appendAndApply(Op{Kind: OpLabels, Host: host, Labels: labels})
+}
+
+// ApplyChanges logs and applies a catalog change. Changes out of sequence are rejected
+// before they reach the log, so the log always replays cleanly.
+func (d *DiskStore) ApplyChanges(host string, seq uint64, added []FileMetadata, removed []string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	last, _, err := d.Store.Catalog(host)
+	if err != nil {
+		return "", err
+	}
+
+	if seq != last+1 {
+		return "", ErrSequenceGap
+	}
+
+	op := Op{Kind: OpAnnounce, Host: host, Files: added, Removed: removed, Seq: seq}
+	if err := d.appendAndApply(op); err != nil {
+		return "", err
+	}
+
+	_, digest, err := d.Store.Catalog(host)
+	return digest, err
+}
+
+// RemovePeerByHost logs and removes a peer, or returns ErrPeerNotFound.
+func (d *DiskStore) RemovePeerByHost(host string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.Store.GetPeerByHost(host); err != nil {
+		return err
+	}
+
+	return d.appendAndApply(Op{Kind: OpUnRegister, Host: host})
+}
+
+// RemoveExpired removes the peers with expired leases and logs their removal.
+func (d *DiskStore) RemoveExpired(ttl time.Duration) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expired, err := d.Store.RemoveExpired(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range expired {
+		if err := d.append(Op{Kind: OpUnRegister, Host: host}); err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+// SetUnproven logs and marks a content of a peer as one it failed to prove it keeps, or
+// lifts the mark.
+func (d *DiskStore) SetUnproven(host string, checksum string, unproven bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.Store.GetPeerByHost(host); err != nil {
+		return err
+	}
+	return d.appendAndApply(Op{Kind: OpUnproven, Host: host, Checksum: checksum, Unproven: unproven})
+}
+
+// ReportPeer records a report against a peer and logs it, along with the ban it ended
+// with, so the log replays the same ban.
+func (d *DiskStore) ReportPeer(host string, rep Report, r Reputation) (Standing, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	standing, err := d.Store.ReportPeer(host, rep, r)
+	if err != nil {
+		return Standing{}, err
+	}
+
+	op := Op{Kind: OpReport, Host: host, Report: &rep, Reputation: &r, BannedUntil: &standing.BannedUntil}
+	if err := d.append(op); err != nil {
+		return Standing{}, err
+	}
+	return standing, nil
+}
+
+// RestoreRecord logs and replaces the record of a host.
+func (d *DiskStore) RestoreRecord(host string, rec Record) error {
+	return d.commit(Op{Kind: OpRecord, Host: host, Record: &rec})
+}
+
+// Apply logs and applies an op, like the ones a follower receives from its leader.
+func (d *DiskStore) Apply(op Op) error {
+	switch op.Kind {
+	case OpCatalogSeq:
+		return d.SetCatalogSeq(op.Host, op.Seq)
+	case OpToken:
+		return d.SetTokenHash(op.Host, op.TokenHash)
+	case OpLabels:
+		return d.SetLabels(op.Host, op.Labels)
+	case OpAnnounce:
+		_, err := d.ApplyChanges(op.Host, op.Seq, op.Files, op.Removed)
+		return err
+	case OpUnproven:
+		return d.SetUnproven(op.Host, op.Checksum, op.Unproven)
+	case OpReport:
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		if _, err := d.Store.GetPeerByHost(op.Host); err != nil {
+			return err
+		}
+		return d.appendAndApply(op)
+	case OpDraining:
+		//like probes and loads, draining is not kept across restarts.
+		return d.Store.Apply(op)
+	case OpUnRegister:
+		err := d.RemovePeerByHost(op.Host)
+		if err != nil && !errors.Is(err, ErrPeerNotFound) {
+			return err
+		}
+		return nil
+	default:
+		return d.commit(op)
+	}
+}
+
+// Snapshot compacts the current state into the snapshot file and resets the log.
+func (d *DiskStore) Snapshot() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	snap, err := TakeSnapshot(d.Store)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	//write next to the old one and swap, so a crash never leaves half a snapshot behind.
+	path := filepath.Join(d.dir, SnapshotFile)
+	tmp := path + ".tmp"
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+
+	if err := syncDir(d.dir); err != nil {
+		return err
+	}
+
+	//every op in the log is part of the snapshot now.
+	if err := d.wal.Truncate(0); err != nil {
+		return fmt.Errorf("truncate wal: %w", err)
+	}
+
+	if _, err := d.wal.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seek wal: %w", err)
+	}
+	return d.wal.Sync()
+}
+
+// RunSnapshots compacts the log every interval until ctx is done, intervals that are not
+// positive fall back to DefaultSnapshotInterval.
+func (d *DiskStore) RunSnapshots(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSnapshotInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Snapshot(); err != nil {
+				log.Printf("snapshot peer store: %s\n", err)
+			}
+		}
+	}
+}
+
+// Close closes the log.
+func (d *DiskStore) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.wal.Close()
+}
+
+// commit appends op to the log and then applies it to the in memory store.
+func (d *DiskStore) commit(op Op) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.appendAndApply(op)
+}
+
+// appendAndApply writes op to the log and applies it, callers must hold d.mu.
+func (d *DiskStore) appendAndApply(op Op) error {
+	if err := d.append(op); err != nil {
+		return err
+	}
+	return d.Store.Apply(op)
+}
+
+// append writes a single record to the log and syncs it to disk.
+// A record is laid out as: length(4 bytes) | crc32 of payload(4 bytes) | payload.
+func (d *DiskStore) append(op Op) error {
+	payload, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("marshal op: %w", err)
+	}
+
+	record := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[headerSize:], payload)
+
+	if _, err := d.wal.Write(record); err != nil {
+		return fmt.Errorf("write wal: %w", err)
+	}
+
+	if err := d.wal.Sync(); err != nil {
+		return fmt.Errorf("sync wal: %w", err)
+	}
+	return nil
+}
+
+// replay applies every complete record of the log to store. The log is cut at the
+// first torn or corrupted record and left positioned at its end for appends.
+func replay(wal *os.File, store *Store) error {
+	reader := bufio.NewReader(wal)
+	var offset int64
+
+	for {
+		op, n, err := readRecord(reader)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			log.Printf("wal: dropping torn record at offset %d: %s\n", offset, err)
+			if err := wal.Truncate(offset); err != nil {
+				return fmt.Errorf("truncate: %w", err)
+			}
+			break
+		}
+
+		if err := store.Apply(op); err != nil {
+			return fmt.Errorf("apply op at offset %d: %w", offset, err)
+		}
+		offset += n
+	}
+
+	if _, err := wal.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek: %w", err)
+	}
+	return nil
+}
+
+var errCorruptedRecord = errors.New("corrupted record")
+
+// readRecord reads a single record and returns it with the number of bytes it took.
+func readRecord(r io.Reader) (Op, int64, error) {
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		if err == io.EOF && n == 0 {
+			return Op{}, 0, io.EOF
+		}
+		return Op{}, 0, fmt.Errorf("read header: %w", err)
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	checksum := binary.BigEndian.Uint32(header[4:8])
+	if length > maxRecordSize {
+		return Op{}, 0, errCorruptedRecord
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return Op{}, 0, fmt.Errorf("read payload: %w", err)
+	}
+
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return Op{}, 0, errCorruptedRecord
+	}
+
+	var op Op
+	if err := json.Unmarshal(payload, &op); err != nil {
+		return Op{}, 0, fmt.Errorf("unmarshal: %w", err)
+	}
+	return op, int64(headerSize) + int64(length), nil
+}
+
+func loadSnapshot(path string, store *Store) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	return Restore(store, snap)
+}
+
+func writeFileSync(path string, data []byte) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+	return nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("open dir: %w", err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("sync dir: %w", err)
+	}
+	return nil
+}
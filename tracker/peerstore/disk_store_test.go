@@ -0,0 +1,311 @@
+package peerstore_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+)
+
+func TestDiskStoreReplay(t *testing.T) {
+	dir := t.TempDir()
+	store := openDiskStore(t, dir)
+
+	file1 := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	file2 := peerstore.FileMetadata{Name: "file2.txt", Size: 20, Checksum: "hash-2"}
+
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{file1}, "team-a"))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:8000", []peerstore.FileMetadata{file1}))
+	mustNotFail(t, store.UpdatePeer("127.0.0.1:9000", []peerstore.FileMetadata{file1, file2}))
+	mustNotFail(t, store.SetLabels("127.0.0.1:9000", map[string]string{"region": "eu"}))
+	mustNotFail(t, store.RemovePeerByHost("127.0.0.1:8000"))
+
+	if err := store.RemovePeerByHost("127.0.0.1:8000"); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+	mustNotFail(t, store.Close())
+
+	//boot again
+	store = openDiskStore(t, dir)
+	defer store.Close()
+
+	files, err := store.GetPeerByHost("127.0.0.1:9000")
+	if err != nil {
+		t.Fatalf("expected peer to be replayed: %s", err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("length=%d, got %d", 2, len(files))
+	}
+
+	labels, err := store.Labels("127.0.0.1:9000")
+	mustNotFail(t, err)
+	if labels["region"] != "eu" {
+		t.Fatalf("region=%s, got %s", "eu", labels["region"])
+	}
+
+	if peers := store.GetPeersForFile("file2.txt", "team-a"); len(peers) != 1 {
+		t.Fatalf("expected the peer to be replayed in its namespace, got %+v", peers)
+	}
+
+	if _, err := store.GetPeerByHost("127.0.0.1:8000"); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+}
+
+func TestDiskStoreSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	store := openDiskStore(t, dir)
+
+	file := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{file}))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:8000", []peerstore.FileMetadata{file}))
+	mustNotFail(t, store.SetTokenHash("127.0.0.1:9000", "hash-of-9000"))
+
+	if err := store.Snapshot(); err != nil {
+		t.Fatalf("expected to snapshot the store: %s", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, peerstore.WALFile))
+	if err != nil {
+		t.Fatalf("expected wal to exist: %s", err)
+	}
+
+	if info.Size() != 0 {
+		t.Fatalf("expected wal to be compacted, got %d bytes", info.Size())
+	}
+
+	//mutations after the snapshot go to the log
+	mustNotFail(t, store.RemovePeerByHost("127.0.0.1:8000"))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:7000", []peerstore.FileMetadata{file}))
+	mustNotFail(t, store.SetTokenHash("127.0.0.1:7000", "hash-of-7000"))
+	mustNotFail(t, store.Close())
+
+	store = openDiskStore(t, dir)
+	defer store.Close()
+
+	peers := store.GetPeersForFile(file.Name)
+	if len(peers) != 2 {
+		t.Fatalf("length=%d, got %d", 2, len(peers))
+	}
+
+	for _, host := range []string{"127.0.0.1:9000", "127.0.0.1:7000"} {
+		if _, err := store.GetPeerByHost(host); err != nil {
+			t.Errorf("expected peer %s to be restored: %s", host, err)
+		}
+	}
+
+	//tokens come back from both the snapshot and the log
+	tokens := map[string]string{"127.0.0.1:9000": "hash-of-9000", "127.0.0.1:7000": "hash-of-7000"}
+	for host, expected := range tokens {
+		hash, err := store.TokenHash(host)
+		mustNotFail(t, err)
+		if hash != expected {
+			t.Errorf("token hash=%s, got %s", expected, hash)
+		}
+	}
+}
+
+func TestRunSnapshots(t *testing.T) {
+	store := openDiskStore(t, t.TempDir())
+	defer store.Close()
+
+	//intervals that are not positive fall back to the default one.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		store.RunSnapshots(ctx, 0)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected snapshots to stop")
+	}
+}
+
+func TestDiskStoreTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	store := openDiskStore(t, dir)
+
+	file := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{file}))
+
+	walPath := filepath.Join(dir, peerstore.WALFile)
+	info, err := os.Stat(walPath)
+	if err != nil {
+		t.Fatalf("expected wal to exist: %s", err)
+	}
+	complete := info.Size()
+
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:8000", []peerstore.FileMetadata{file}))
+	mustNotFail(t, store.Close())
+
+	//crash in the middle of writing the second record
+	info, err = os.Stat(walPath)
+	if err != nil {
+		t.Fatalf("expected wal to exist: %s", err)
+	}
+	torn := complete + (info.Size()-complete)/2
+	if err := os.Truncate(walPath, torn); err != nil {
+		t.Fatalf("failed to truncate wal: %s", err)
+	}
+
+	store = openDiskStore(t, dir)
+	if _, err := store.GetPeerByHost("127.0.0.1:9000"); err != nil {
+		t.Fatalf("expected complete record to be replayed: %s", err)
+	}
+
+	if _, err := store.GetPeerByHost("127.0.0.1:8000"); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+
+	//torn tail must be cut, so new records are readable after it
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:7000", []peerstore.FileMetadata{file}))
+	mustNotFail(t, store.Close())
+
+	store = openDiskStore(t, dir)
+	defer store.Close()
+
+	for _, host := range []string{"127.0.0.1:9000", "127.0.0.1:7000"} {
+		if _, err := store.GetPeerByHost(host); err != nil {
+			t.Errorf("expected peer %s to be replayed: %s", host, err)
+		}
+	}
+}
+
+func TestDiskStoreCorruptedRecord(t *testing.T) {
+	dir := t.TempDir()
+	store := openDiskStore(t, dir)
+
+	file := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{file}))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:8000", []peerstore.FileMetadata{file}))
+	mustNotFail(t, store.Close())
+
+	//flip the last byte of the log, the checksum of the last record no longer matches.
+	walPath := filepath.Join(dir, peerstore.WALFile)
+	data, err := os.ReadFile(walPath)
+	if err != nil {
+		t.Fatalf("failed to read wal: %s", err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(walPath, data, 0644); err != nil {
+		t.Fatalf("failed to write wal: %s", err)
+	}
+
+	store = openDiskStore(t, dir)
+	defer store.Close()
+
+	if len(store.Hosts()) != 1 {
+		t.Fatalf("length=%d, got %d", 1, len(store.Hosts()))
+	}
+}
+
+// =============================================================================
+// utils
+func openDiskStore(t *testing.T, dir string) *peerstore.DiskStore {
+	store, err := peerstore.Open(dir)
+	if err != nil {
+		t.Fatalf("failed to open disk store: %s", err)
+	}
+	return store
+}
+
+func mustNotFail(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestDiskStoreReplayAnnouncements(t *testing.T) {
+	dir := t.TempDir()
+	store := openDiskStore(t, dir)
+
+	file1 := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	file2 := peerstore.FileMetadata{Name: "file2.txt", Size: 20, Checksum: "hash-2"}
+
+	host := "127.0.0.1:9000"
+	mustNotFail(t, store.RegisterPeer(host, []peerstore.FileMetadata{file1}))
+	mustNotFail(t, store.SetCatalogSeq(host, 1))
+	if err := store.Snapshot(); err != nil {
+		t.Fatalf("expected to snapshot the store: %s", err)
+	}
+
+	if _, err := store.ApplyChanges(host, 2, []peerstore.FileMetadata{file2}, nil); err != nil {
+		t.Fatalf("expected to apply changes: %s", err)
+	}
+
+	//rejected changes never reach the log
+	if _, err := store.ApplyChanges(host, 9, nil, []string{file1.Name}); !errors.Is(err, peerstore.ErrSequenceGap) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrSequenceGap, err)
+	}
+
+	_, digest, _ := store.Catalog(host)
+	mustNotFail(t, store.Close())
+
+	store = openDiskStore(t, dir)
+	defer store.Close()
+
+	seq, replayed, err := store.Catalog(host)
+	if err != nil {
+		t.Fatalf("expected peer to be replayed: %s", err)
+	}
+
+	if seq != 2 {
+		t.Errorf("seq=%d, got %d", 2, seq)
+	}
+
+	if replayed != digest {
+		t.Errorf("digest=%s, got %s", digest, replayed)
+	}
+}
+
+func TestDiskStoreReplayRecords(t *testing.T) {
+	dir := t.TempDir()
+	store := openDiskStore(t, dir)
+
+	file := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	host := "127.0.0.1:9000"
+	mustNotFail(t, store.RegisterPeer(host, []peerstore.FileMetadata{file}))
+	mustNotFail(t, store.SetUnproven(host, file.Checksum, true))
+
+	rep := peerstore.Report{Reporter: "127.0.0.1:8000", Penalty: 5}
+	if _, err := store.ReportPeer(host, rep, peerstore.DefaultReputation); err != nil {
+		t.Fatalf("expected to report peer: %s", err)
+	}
+
+	//marks of hosts that are not registered never reach the log
+	if err := store.SetUnproven("127.0.0.1:7000", file.Checksum, true); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+
+	expectRecord := func(store *peerstore.DiskStore) {
+		t.Helper()
+		if peers := store.GetPeersForFile(file.Name); len(peers) != 0 {
+			t.Errorf("expected the unproven content to stay out of lookups, got %+v", peers)
+		}
+
+		for _, p := range store.GetAllPeers() {
+			if p.Host == host && p.Penalty <= 0 {
+				t.Errorf("expected the report against %s to be kept, got a penalty of %f", host, p.Penalty)
+			}
+		}
+	}
+
+	//boot from the log, then from the snapshot
+	for range 2 {
+		mustNotFail(t, store.Close())
+		store = openDiskStore(t, dir)
+		expectRecord(store)
+		mustNotFail(t, store.Snapshot())
+	}
+	mustNotFail(t, store.Close())
+}
@@ -0,0 +1,219 @@
+package peerstore
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCursorExpired is returned when the events after a cursor are no longer in history.
+	ErrCursorExpired = errors.New("cursor expired")
+	// ErrWatcherLagged is returned once a watcher fell too far behind and was dropped.
+	ErrWatcherLagged = errors.New("watcher lagged behind")
+)
+
+const (
+	// eventHistory is how many past events are kept for watchers to resume from.
+	eventHistory = 1024
+	// watcherBuffer is how many events a watcher can fall behind before being dropped.
+	watcherBuffer = 256
+)
+
+// EventKind represents the kind of change an event reports.
+type EventKind int
+
+const (
+	EventPeerRegistered EventKind = iota
+	EventPeerUnregistered
+	EventFileAnnounced
+	EventFileRemoved
+)
+
+// String implements the fmt.Stringer.
+func (k EventKind) String() string {
+	switch k {
+	case EventPeerRegistered:
+		return "peer-registered"
+	case EventPeerUnregistered:
+		return "peer-unregistered"
+	case EventFileAnnounced:
+		return "file-announced"
+	case EventFileRemoved:
+		return "file-removed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event represents a single change of the store.
+type Event struct {
+	Seq  uint64
+	Kind EventKind
+	Host string
+	// File is only set for file events.
+	File FileMetadata
+	Time time.Time
+	// Namespaces are the namespaces of the peer, only watchers in one of them see the event.
+	Namespaces []string
+}
+
+// feed hands out the events of a store to its watchers and keeps a bounded history
+// of them, so watchers can resume where they left off.
+type feed struct {
+	mu       sync.Mutex
+	seq      uint64
+	history  []Event
+	watchers map[*Watcher]struct{}
+}
+
+func newFeed() *feed {
+	return &feed{
+		watchers: map[*Watcher]struct{}{},
+	}
+}
+
+// publish records a new event and sends it to every watcher, watchers that are not
+// keeping up are dropped instead of blocking the store.
+func (f *feed) publish(kind EventKind, host string, namespaces []string, file FileMetadata) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	ev := Event{
+		Seq:        f.seq,
+		Kind:       kind,
+		Host:       host,
+		File:       file,
+		Time:       time.Now(),
+		Namespaces: namespaces,
+	}
+
+	if len(f.history) == eventHistory {
+		f.history = f.history[1:]
+	}
+	f.history = append(f.history, ev)
+
+	for w := range f.watchers {
+		select {
+		case w.events <- ev:
+		default:
+			delete(f.watchers, w)
+			close(w.events)
+		}
+	}
+}
+
+// watch creates a watcher receiving the events after the given seq, zero only
+// receives new events.
+func (f *feed) watch(after uint64) (*Watcher, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := Watcher{
+		feed:   f,
+		events: make(chan Event, watcherBuffer),
+		cursor: f.seq,
+	}
+
+	if after > 0 {
+		oldest := f.seq - uint64(len(f.history))
+		if after < oldest || after > f.seq {
+			return nil, ErrCursorExpired
+		}
+		w.backlog = slices.Clone(f.history[after-oldest:])
+		w.cursor = after
+	}
+
+	f.watchers[&w] = struct{}{}
+	return &w, nil
+}
+
+// Watcher receives the events of a store in order.
+type Watcher struct {
+	feed    *feed
+	events  chan Event
+	backlog []Event
+	cursor  uint64
+}
+
+// Next blocks until the next event, ErrWatcherLagged is returned when the watcher
+// fell behind, it can resume by watching again from Cursor.
+func (w *Watcher) Next(ctx context.Context) (Event, error) {
+	if len(w.backlog) > 0 {
+		ev := w.backlog[0]
+		w.backlog = w.backlog[1:]
+		w.cursor = ev.Seq
+		return ev, nil
+	}
+
+	select {
+	case ev, ok := <-w.events:
+		if !ok {
+			return Event{}, ErrWatcherLagged
+		}
+		w.cursor = ev.Seq
+		return ev, nil
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	}
+}
+
+// Cursor returns the seq of the last event handed out by Next, or the one the
+// watcher started after.
+func (w *Watcher) Cursor() uint64 {
+	return w.cursor
+}
+
+// Close stops the watcher from receiving events.
+func (w *Watcher) Close() {
+	w.feed.mu.Lock()
+	defer w.feed.mu.Unlock()
+
+	if _, ok := w.feed.watchers[w]; ok {
+		delete(w.feed.watchers, w)
+		close(w.events)
+	}
+}
+
+// Watch returns a watcher receiving every change of the store after the given
+// event seq, or only new ones when it is zero. ErrCursorExpired is returned when
+// those events are no longer kept.
+func (s *Store) Watch(after uint64) (*Watcher, error) {
+	return s.events.watch(after)
+}
+
+// publishChanges publishes the file events turning the old files of a peer into the
+// new ones, callers must hold the write lock.
+func (s *Store) publishChanges(host string, namespaces []string, old Files, new Files) {
+	names := make([]string, 0, len(old)+len(new))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		before, had := old[name]
+		after, has := new[name]
+		switch {
+		case !has:
+			s.events.publish(EventFileRemoved, host, namespaces, before)
+		case !had || before != after:
+			s.events.publish(EventFileAnnounced, host, namespaces, after)
+		}
+	}
+}
+
+// publishLeave publishes the events of a peer leaving with its files, callers must
+// hold the write lock.
+func (s *Store) publishLeave(host string, namespaces []string, files Files) {
+	s.publishChanges(host, namespaces, files, nil)
+	s.events.publish(EventPeerUnregistered, host, namespaces, FileMetadata{})
+}
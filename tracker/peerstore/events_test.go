@@ -0,0 +1,114 @@
+package peerstore_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+)
+
+func TestWatch(t *testing.T) {
+	store := peerstore.New()
+
+	w, err := store.Watch(0)
+	mustNotFail(t, err)
+	defer w.Close()
+
+	report := peerstore.FileMetadata{Name: "report.pdf", Size: 10, Checksum: "report-v1"}
+	reportV2 := peerstore.FileMetadata{Name: "report.pdf", Size: 12, Checksum: "report-v2"}
+	notes := peerstore.FileMetadata{Name: "notes.txt", Size: 5, Checksum: "notes"}
+
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:8000", []peerstore.FileMetadata{report}))
+	_, err = store.ApplyChanges("127.0.0.1:8000", 1, []peerstore.FileMetadata{notes, report}, nil)
+	mustNotFail(t, err)
+	mustNotFail(t, store.UpdatePeer("127.0.0.1:8000", []peerstore.FileMetadata{reportV2, notes}))
+	mustNotFail(t, store.RemovePeerByHost("127.0.0.1:8000"))
+
+	expected := []string{
+		"peer-registered:",
+		"file-announced:report-v1",
+		//re-announcing the same content is not a change.
+		"file-announced:notes",
+		"file-announced:report-v2",
+		"file-removed:notes",
+		"file-removed:report-v2",
+		"peer-unregistered:",
+	}
+
+	got := nextEvents(t, w, len(expected))
+	if !slices.Equal(got, expected) {
+		t.Fatalf("events=%v, got %v", expected, got)
+	}
+
+	//resume right after the announcements
+	resumed, err := store.Watch(3)
+	mustNotFail(t, err)
+	defer resumed.Close()
+
+	got = nextEvents(t, resumed, len(expected)-3)
+	if !slices.Equal(got, expected[3:]) {
+		t.Fatalf("events=%v, got %v", expected[3:], got)
+	}
+
+	//a cursor from the future, like the one of a tracker before restart
+	if _, err := store.Watch(100); !errors.Is(err, peerstore.ErrCursorExpired) {
+		t.Fatalf("err=%v, got %v", peerstore.ErrCursorExpired, err)
+	}
+}
+
+func TestWatchHistoryAndLag(t *testing.T) {
+	store := peerstore.New()
+
+	w, err := store.Watch(0)
+	mustNotFail(t, err)
+	defer w.Close()
+
+	//every register is one event, enough to overflow both the watcher and the history
+	for i := range 2000 {
+		mustNotFail(t, store.RegisterPeer(time.Duration(i).String(), nil))
+	}
+
+	//the watcher keeps what it buffered before being dropped
+	var lagErr error
+	for lagErr == nil {
+		_, lagErr = w.Next(context.Background())
+	}
+	if !errors.Is(lagErr, peerstore.ErrWatcherLagged) {
+		t.Fatalf("err=%v, got %v", peerstore.ErrWatcherLagged, lagErr)
+	}
+
+	if _, err := store.Watch(w.Cursor()); !errors.Is(err, peerstore.ErrCursorExpired) {
+		t.Fatalf("err=%v, got %v", peerstore.ErrCursorExpired, err)
+	}
+
+	//the last events are still in history
+	resumed, err := store.Watch(1990)
+	mustNotFail(t, err)
+	defer resumed.Close()
+
+	ev, err := resumed.Next(context.Background())
+	mustNotFail(t, err)
+	if ev.Seq != 1991 {
+		t.Fatalf("seq=%d, got %d", 1991, ev.Seq)
+	}
+}
+
+func nextEvents(t *testing.T, w *peerstore.Watcher, n int) []string {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	events := make([]string, n)
+	for i := range events {
+		ev, err := w.Next(ctx)
+		if err != nil {
+			t.Fatalf("expected event %d: %s", i, err)
+		}
+		events[i] = ev.Kind.String() + ":" + ev.File.Checksum
+	}
+	return events
+}
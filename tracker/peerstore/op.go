@@ -0,0 +1,96 @@
+package peerstore
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OpKind represents the kind of a mutation applied to the store.
+type OpKind string
+
+const (
+	OpRegister   OpKind = "register"
+	OpUnRegister OpKind = "unregister"
+	OpUpdate     OpKind = "update"
+	OpAnnounce   OpKind = "announce"
+	OpCatalogSeq OpKind = "catalog_seq"
+	OpToken      OpKind = "token"
+	OpLabels     OpKind = "labels"
+	OpDraining   OpKind = "draining"
+	OpReport     OpKind = "report"
+	OpUnproven   OpKind = "unproven"
+	OpRecord     OpKind = "record"
+)
+
+// Op represents a single mutation of the store, ops are what gets written into
+// the write-ahead log and replayed on boot.
+type Op struct {
+	Kind  OpKind         `json:"kind"`
+	Host  string         `json:"host"`
+	Files []FileMetadata `json:"files,omitempty"`
+	// Removed and Seq are only set by announcements and catalog sequence updates.
+	Removed []string `json:"removed,omitempty"`
+	Seq     uint64   `json:"seq,omitempty"`
+	// TokenHash is only set by token updates.
+	TokenHash string `json:"token_hash,omitempty"`
+	// Labels is only set by label updates.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Namespaces is only set by registrations.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Draining is only set by draining updates.
+	Draining bool `json:"draining,omitempty"`
+	// Checksum and Unproven are only set by unproven updates.
+	Checksum string `json:"checksum,omitempty"`
+	Unproven bool   `json:"unproven,omitempty"`
+	// Report, Reputation and BannedUntil are only set by reports. BannedUntil is the ban
+	// the report ended with where it was made, it replaces the one of the store.
+	Report      *Report     `json:"report,omitempty"`
+	Reputation  *Reputation `json:"reputation,omitempty"`
+	BannedUntil *time.Time  `json:"banned_until,omitempty"`
+	// Record is only set by record restores.
+	Record *Record `json:"record,omitempty"`
+}
+
+// Apply applies an op to the store. Ops are idempotent, unregistering a peer that
+// is already gone is not an error so the same op can safely be replayed twice.
+func (s *Store) Apply(op Op) error {
+	switch op.Kind {
+	case OpRegister:
+		return s.RegisterPeer(op.Host, op.Files, op.Namespaces...)
+	case OpUpdate:
+		return s.UpdatePeer(op.Host, op.Files)
+	case OpCatalogSeq:
+		return s.SetCatalogSeq(op.Host, op.Seq)
+	case OpToken:
+		return s.SetTokenHash(op.Host, op.TokenHash)
+	case OpLabels:
+		return s.SetLabels(op.Host, op.Labels)
+	case OpAnnounce:
+		_, err := s.ApplyChanges(op.Host, op.Seq, op.Files, op.Removed)
+		return err
+	case OpDraining:
+		return s.SetDraining(op.Host, op.Draining)
+	case OpUnproven:
+		return s.SetUnproven(op.Host, op.Checksum, op.Unproven)
+	case OpRecord:
+		if op.Record == nil {
+			return fmt.Errorf("record op of %s without a record", op.Host)
+		}
+		return s.RestoreRecord(op.Host, *op.Record)
+	case OpReport:
+		if op.Report == nil || op.Reputation == nil {
+			return fmt.Errorf("report op of %s without a report", op.Host)
+		}
+		_, err := s.applyReport(op.Host, *op.Report, *op.Reputation, op.BannedUntil)
+		return err
+	case OpUnRegister:
+		err := s.RemovePeerByHost(op.Host)
+		if err != nil && !errors.Is(err, ErrPeerNotFound) {
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown op kind: %q", op.Kind)
+	}
+}
@@ -1,53 +1,252 @@
 package peerstore
 
 import (
+	"cmp"
 	"errors"
+	"maps"
+	"slices"
 	"sync"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
 )
 
-var ErrPeerNotFound = errors.New("peer not found")
+var (
+	ErrPeerNotFound = errors.New("peer not found")
+	// ErrSequenceGap is returned when a catalog change does not follow the last one applied.
+	ErrSequenceGap = errors.New("catalog sequence gap")
+)
 
 // FileMetadata represents all required info related to a file on the network.
 type FileMetadata struct {
-	Name     string
-	Size     int64
-	Checksum string
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
 }
 
 // Files is a collection of file metadata.
 type Files map[string]FileMetadata
 
+// Health represents the reachability of a peer as observed by probes.
+type Health int
+
+const (
+	// Healthy peers answered their last probe.
+	Healthy Health = iota
+	// Suspect peers failed some probes but are still handed out.
+	Suspect
+	// Dead peers are registered but unreachable.
+	Dead
+)
+
+// String implements the fmt.Stringer.
+func (h Health) String() string {
+	switch h {
+	case Healthy:
+		return "healthy"
+	case Suspect:
+		return "suspect"
+	case Dead:
+		return "dead"
+	default:
+		return "unknown"
+	}
+}
+
+// Thresholds controls after how many consecutive failed probes a peer changes health.
+type Thresholds struct {
+	SuspectAfter int
+	DeadAfter    int
+}
+
+// Load represents how busy a peer is serving files, as reported by the peer itself.
+type Load struct {
+	ActiveStreams  int64
+	BytesPerSecond int64
+}
+
 type Peer struct {
-	Host  string
-	Files []FileMetadata
+	Host     string
+	Files    []FileMetadata
+	Health   Health
+	LastSeen time.Time
+	RTT      time.Duration
+	Load     Load
+	// Labels describe the topology of the peer, like its region, zone and rack.
+	Labels map[string]string
+	// Draining peers are about to leave, they are no longer handed out for downloads.
+	Draining bool
+	// Namespaces are the swarms the peer joined, sorted. Its files are only visible
+	// to callers in one of them.
+	Namespaces []string
+	// Penalty weighs the recent reports against the peer, peers reported too often are
+	// banned until BannedUntil.
+	Penalty     float64
+	BannedUntil time.Time
+	// Unproven are the checksums of the contents the peer failed to prove it keeps,
+	// sorted. The peer is left out of the lookups of those contents.
+	Unproven []string
+}
+
+// Storer represents the behaviors of a peer store, either kept in memory or on disk.
+type Storer interface {
+	RegisterPeer(host string, files []FileMetadata, namespaces ...string) error
+	UpdatePeer(host string, updates []FileMetadata) error
+	RemovePeerByHost(host string) error
+	GetPeerByHost(host string) (Files, error)
+	GetAllPeers() []Peer
+	GetPeersPage(after string, limit int, namespaces ...string) ([]Peer, bool)
+	GetPeersForFile(file string, namespaces ...string) []Peer
+	GetPeersForChecksum(checksum string, namespaces ...string) []Peer
+	SearchFiles(q Query) []FileSummary
+	Conflicts(namespaces ...string) []Conflict
+	Hosts() []string
+	SetCatalogSeq(host string, seq uint64) error
+	SetTokenHash(host string, hash string) error
+	SetLabels(host string, labels map[string]string) error
+	Labels(host string) (map[string]string, error)
+	TokenHash(host string) (string, error)
+	ApplyChanges(host string, seq uint64, added []FileMetadata, removed []string) (string, error)
+	Catalog(host string) (uint64, string, error)
+	Watch(after uint64) (*Watcher, error)
+	Stats(namespaces ...string) Stats
+	UnderReplicated(factor int) []Replica
+	RenewLease(host string) error
+	RemoveExpired(ttl time.Duration) ([]string, error)
+	RecordProbeSuccess(host string, rtt time.Duration) error
+	RecordProbeFailure(host string, t Thresholds) (Health, error)
+	RecordLoad(host string, load Load) error
+	SetDraining(host string, draining bool) error
+	SetUnproven(host string, checksum string, unproven bool) error
+	ReportPeer(host string, rep Report, r Reputation) (Standing, error)
+	Record(host string) Record
+	Records() map[string]Record
+	RestoreRecord(host string, rec Record) error
+}
+
+// entry is everything the store keeps about a single peer.
+type entry struct {
+	files Files
+	// renewedAt is the last time the peer's lease was renewed, the lease
+	// expires once renewedAt + ttl is in the past.
+	renewedAt time.Time
+	// seq and digest identify the version of the catalog the peer announced last.
+	seq      uint64
+	digest   Digest
+	health   Health
+	failures int
+	lastSeen time.Time
+	rtt      time.Duration
+	load     Load
+	draining bool
+	// labels is replaced as a whole and never modified, so it is handed out as is.
+	labels map[string]string
+	// tokenHash is the hash of the secret the owner of the host has to present.
+	tokenHash string
+	// namespaces is normalized, replaced as a whole and never modified.
+	namespaces []string
 }
 
+// in reports whether the peer joined one of the namespaces, no namespaces at all
+// stand for every namespace.
+func (e *entry) in(namespaces []string) bool {
+	return len(namespaces) == 0 || namespace.Shares(e.namespaces, namespaces)
+}
+
+// toPeer creates a Peer out of an entry with the given files.
+func (e *entry) toPeer(host string, files []FileMetadata) Peer {
+	return Peer{
+		Host:       host,
+		Files:      files,
+		Health:     e.health,
+		LastSeen:   e.lastSeen,
+		RTT:        e.rtt,
+		Load:       e.load,
+		Labels:     e.labels,
+		Draining:   e.draining,
+		Namespaces: e.namespaces,
+	}
+}
+
+// hostSet is a set of peer hosts, counting how many of the host's files share the key,
+// since a peer can keep the same content under several names.
+type hostSet map[string]int
+
 // Store represents the in memory storage used to store peers and their files.
 type Store struct {
 	mu    sync.RWMutex
-	store map[string]Files
+	store map[string]*entry
+	// byName and byChecksum are inverted indexes of the files in store, so lookups
+	// do not have to scan every peer.
+	byName     map[string]hostSet
+	byChecksum map[string]hostSet
+	// standings keeps the records of the reported hosts, registered or not.
+	standings map[string]*standing
+	// unproven keeps the checksums every host failed to prove it keeps, by host. Marks
+	// outlive registrations so a peer can not shake them off by registering again.
+	unproven map[string]map[string]struct{}
+	// events hands out every change of the store to watchers.
+	events *feed
 }
 
+var _ Storer = (*Store)(nil)
+
 // New creates a new store.
 func New() *Store {
 	return &Store{
-		store: map[string]Files{},
+		store:      map[string]*entry{},
+		byName:     map[string]hostSet{},
+		byChecksum: map[string]hostSet{},
+		standings:  map[string]*standing{},
+		unproven:   map[string]map[string]struct{}{},
+		events:     newFeed(),
 	}
 }
 
-// RegisterPeer adds a new peer with its files into the store.
-func (p *Store) RegisterPeer(host string, files []FileMetadata) {
+// RegisterPeer adds a new peer with its files into the store and starts its lease. The
+// peer joins the namespaces, the default one when none are given.
+func (p *Store) RegisterPeer(host string, files []FileMetadata, namespaces ...string) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	p.store[host] = make(Files, len(files))
+	now := time.Now()
+	e := entry{
+		files:      make(Files, len(files)),
+		renewedAt:  now,
+		lastSeen:   now,
+		namespaces: namespace.Normalize(namespaces),
+	}
 	for _, file := range files {
-		p.store[host][file.Name] = file
+		e.files[file.Name] = file
 	}
+	e.digest = digestOf(e.files)
+
+	old, ok := p.store[host]
+	if ok {
+		//registering again keeps the owner of the host and its topology until told otherwise.
+		e.tokenHash = old.tokenHash
+		e.labels = old.labels
+		p.unindex(host, old.files)
+	}
+
+	switch {
+	case ok && slices.Equal(old.namespaces, e.namespaces):
+		p.publishChanges(host, e.namespaces, old.files, e.files)
+	default:
+		//watchers of namespaces the peer moved out of see it leave, the others see it join.
+		if ok {
+			p.publishLeave(host, old.namespaces, old.files)
+		}
+		p.events.publish(EventPeerRegistered, host, e.namespaces, FileMetadata{})
+		p.publishChanges(host, e.namespaces, nil, e.files)
+	}
+	p.store[host] = &e
+	p.index(host, e.files)
+	return nil
 }
 
-// GetPeerByHost will return the peer files in case peer found, otherwise returns
-// ErrPeerNotFound.
+// GetPeerByHost will return a copy of the peer files in case peer found, otherwise
+// returns ErrPeerNotFound.
 func (s *Store) GetPeerByHost(host string) (Files, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -56,7 +255,7 @@ func (s *Store) GetPeerByHost(host string) (Files, error) {
 	if !ok {
 		return nil, ErrPeerNotFound
 	}
-	return peer, nil
+	return maps.Clone(peer.files), nil
 }
 
 // RemovePeerByHost will remove a peer from store, or return ErrPeerNotFound.
@@ -64,11 +263,14 @@ func (s *Store) RemovePeerByHost(host string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.store[host]; !ok {
+	e, ok := s.store[host]
+	if !ok {
 		return ErrPeerNotFound
 	}
 
+	s.unindex(host, e.files)
 	delete(s.store, host)
+	s.publishLeave(host, e.namespaces, e.files)
 
 	return nil
 }
@@ -79,42 +281,216 @@ func (s *Store) GetAllPeers() []Peer {
 	defer s.mu.RUnlock()
 
 	peers := make([]Peer, 0, len(s.store))
+	now := time.Now()
 
 	for k, v := range s.store {
-		files := make([]FileMetadata, 0, len(v))
+		files := make([]FileMetadata, 0, len(v.files))
 
-		for _, file := range v {
+		for _, file := range v.files {
 			files = append(files, file)
 		}
-		peer := Peer{
-			Host:  k,
-			Files: files,
+		peers = append(peers, s.toPeer(k, v, files, now))
+	}
+	return peers
+}
+
+// GetPeersPage returns up to limit peers ordered by host, starting right after the
+// given host, and whether more peers follow. Given namespaces, only the peers in one
+// of them are returned.
+func (s *Store) GetPeersPage(after string, limit int, namespaces ...string) ([]Peer, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hosts := make([]string, 0, len(s.store))
+	for host, e := range s.store {
+		if host > after && e.in(namespaces) {
+			hosts = append(hosts, host)
+		}
+	}
+	slices.Sort(hosts)
+
+	more := len(hosts) > limit
+	if more {
+		hosts = hosts[:limit]
+	}
+
+	peers := make([]Peer, len(hosts))
+	now := time.Now()
+	for i, host := range hosts {
+		e := s.store[host]
+		files := make([]FileMetadata, 0, len(e.files))
+		for _, file := range e.files {
+			files = append(files, file)
+		}
+		peers[i] = s.toPeer(host, e, files, now)
+	}
+	return peers, more
+}
+
+// GetPeersForFile will return the list of peers that have the requested file. Given
+// namespaces, only the peers in one of them are returned.
+func (s *Store) GetPeersForFile(file string, namespaces ...string) []Peer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hosts := s.byName[file]
+	peers := make([]Peer, 0, len(hosts))
+	now := time.Now()
+
+	for host := range hosts {
+		e := s.store[host]
+		if !e.in(namespaces) || s.isUnproven(host, e.files[file].Checksum) {
+			continue
 		}
-		peers = append(peers, peer)
+		peers = append(peers, s.toPeer(host, e, []FileMetadata{e.files[file]}, now))
 	}
+
 	return peers
 }
 
-// GetPeersForFile will return the list of peers that have the requested file.
-func (s *Store) GetPeersForFile(file string) []Peer {
+// GetPeersForChecksum will return the list of peers that have the exact content,
+// every peer comes with all the names it keeps that content under. Given namespaces,
+// only the peers in one of them are returned.
+func (s *Store) GetPeersForChecksum(checksum string, namespaces ...string) []Peer {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	var peers []Peer
 
-	for host, files := range s.store {
-		if meta, ok := files[file]; ok {
-			var p Peer
-			p.Host = host
-			p.Files = append(p.Files, meta)
-			peers = append(peers, p)
+	hosts := s.byChecksum[checksum]
+	peers := make([]Peer, 0, len(hosts))
+	now := time.Now()
+
+	for host := range hosts {
+		e := s.store[host]
+		if !e.in(namespaces) || s.isUnproven(host, checksum) {
+			continue
 		}
+		var files []FileMetadata
+		for _, file := range e.files {
+			if file.Checksum == checksum {
+				files = append(files, file)
+			}
+		}
+		peers = append(peers, s.toPeer(host, e, files, now))
 	}
 
 	return peers
 }
 
+// Query represents the filters of a file search.
+type Query struct {
+	// Match reports whether a file name is part of the results.
+	Match func(name string) bool
+	// MinSize and MaxSize bound the file size, zero leaves that side unbounded.
+	MinSize int64
+	MaxSize int64
+	// Namespaces limits the search to the peers in one of them, empty searches them all.
+	Namespaces []string
+}
+
+// FileSummary represents a distinct content under a name and how many peers serve it.
+type FileSummary struct {
+	File  FileMetadata
+	Peers int
+}
+
+// SearchFiles returns every distinct name and checksum pair matching the query, along
+// with the number of reachable peers serving it. Results are in no particular order.
+func (s *Store) SearchFiles(q Query) []FileSummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []FileSummary
+	for name, hosts := range s.byName {
+		if q.Match != nil && !q.Match(name) {
+			continue
+		}
+
+		byChecksum := make(map[string]int)
+		for host := range hosts {
+			e := s.store[host]
+			if e.health == Dead || !e.in(q.Namespaces) {
+				continue
+			}
+
+			file := e.files[name]
+			if s.isUnproven(host, file.Checksum) {
+				continue
+			}
+			if file.Size < q.MinSize || (q.MaxSize > 0 && file.Size > q.MaxSize) {
+				continue
+			}
+
+			idx, ok := byChecksum[file.Checksum]
+			if !ok {
+				idx = len(results)
+				byChecksum[file.Checksum] = idx
+				results = append(results, FileSummary{File: file})
+			}
+			results[idx].Peers++
+		}
+	}
+
+	return results
+}
+
+// Conflict represents a file name reachable peers keep different contents under.
+type Conflict struct {
+	Name     string
+	Versions []FileSummary
+}
+
+// Conflicts returns every name with more than one content on reachable peers, ordered
+// by name, with the most replicated version of each first. Given namespaces, only the
+// peers in one of them are compared.
+func (s *Store) Conflicts(namespaces ...string) []Conflict {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var conflicts []Conflict
+	for name, hosts := range s.byName {
+		if len(hosts) < 2 {
+			continue
+		}
+
+		var versions []FileSummary
+		byChecksum := make(map[string]int)
+		for host := range hosts {
+			e := s.store[host]
+			if e.health == Dead || !e.in(namespaces) {
+				continue
+			}
+
+			file := e.files[name]
+			idx, ok := byChecksum[file.Checksum]
+			if !ok {
+				idx = len(versions)
+				byChecksum[file.Checksum] = idx
+				versions = append(versions, FileSummary{File: file})
+			}
+			versions[idx].Peers++
+		}
+
+		if len(versions) > 1 {
+			SortVersions(versions)
+			conflicts = append(conflicts, Conflict{Name: name, Versions: versions})
+		}
+	}
+
+	slices.SortFunc(conflicts, func(a, b Conflict) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return conflicts
+}
+
+// SortVersions orders the contents of a name from the most replicated to the least,
+// ties are broken by checksum so the order is stable.
+func SortVersions(versions []FileSummary) {
+	slices.SortFunc(versions, func(a, b FileSummary) int {
+		return cmp.Or(cmp.Compare(b.Peers, a.Peers), cmp.Compare(a.File.Checksum, b.File.Checksum))
+	})
+}
+
 // UpdatePeer will update files for a peer.
-func (s *Store) UpdatePeer(host string, updates []FileMetadata) {
+func (s *Store) UpdatePeer(host string, updates []FileMetadata) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	updatedFiles := make(Files, len(updates))
@@ -122,5 +498,327 @@ func (s *Store) UpdatePeer(host string, updates []FileMetadata) {
 	for _, update := range updates {
 		updatedFiles[update.Name] = update
 	}
-	s.store[host] = updatedFiles
+
+	e, ok := s.store[host]
+	if !ok {
+		e = &entry{namespaces: namespace.Normalize(nil)}
+		s.store[host] = e
+		s.events.publish(EventPeerRegistered, host, e.namespaces, FileMetadata{})
+	}
+	s.publishChanges(host, e.namespaces, e.files, updatedFiles)
+	s.unindex(host, e.files)
+	e.files = updatedFiles
+	e.digest = digestOf(updatedFiles)
+	s.index(host, e.files)
+	e.renewedAt = time.Now()
+	return nil
+}
+
+// SetCatalogSeq records the sequence number of the catalog a peer sent whole.
+func (s *Store) SetCatalogSeq(host string, seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return ErrPeerNotFound
+	}
+	e.seq = seq
+	return nil
+}
+
+// SetTokenHash records the hash of the token the owner of a peer has to present.
+func (s *Store) SetTokenHash(host string, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return ErrPeerNotFound
+	}
+	e.tokenHash = hash
+	return nil
+}
+
+// SetLabels replaces the topology labels of a peer.
+func (s *Store) SetLabels(host string, labels map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return ErrPeerNotFound
+	}
+	e.labels = maps.Clone(labels)
+	return nil
+}
+
+// Labels returns the topology labels of a peer.
+func (s *Store) Labels(host string) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return nil, ErrPeerNotFound
+	}
+	return e.labels, nil
+}
+
+// TokenHash returns the hash of the token of a peer, empty when it has none.
+func (s *Store) TokenHash(host string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return "", ErrPeerNotFound
+	}
+	return e.tokenHash, nil
+}
+
+// ApplyChanges adds and removes files of a peer and returns the digest of the resulting
+// catalog. The change must carry the sequence number right after the last one applied,
+// otherwise ErrSequenceGap is returned and nothing is changed.
+func (s *Store) ApplyChanges(host string, seq uint64, added []FileMetadata, removed []string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return "", ErrPeerNotFound
+	}
+
+	if seq != e.seq+1 {
+		return "", ErrSequenceGap
+	}
+
+	for _, name := range removed {
+		file, ok := e.files[name]
+		if !ok {
+			continue
+		}
+		s.unindex(host, Files{name: file})
+		e.digest.Toggle(file)
+		delete(e.files, name)
+		s.events.publish(EventFileRemoved, host, e.namespaces, file)
+	}
+
+	for _, file := range added {
+		old, ok := e.files[file.Name]
+		if ok {
+			s.unindex(host, Files{old.Name: old})
+			e.digest.Toggle(old)
+		}
+		if !ok || old != file {
+			s.events.publish(EventFileAnnounced, host, e.namespaces, file)
+		}
+		e.files[file.Name] = file
+		e.digest.Toggle(file)
+		s.index(host, Files{file.Name: file})
+	}
+
+	e.seq = seq
+	return e.digest.String(), nil
+}
+
+// Catalog returns the sequence number and digest of the catalog the store has for a peer.
+func (s *Store) Catalog(host string) (uint64, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return 0, "", ErrPeerNotFound
+	}
+	return e.seq, e.digest.String(), nil
+}
+
+// RenewLease extends the lease of a peer, or returns ErrPeerNotFound.
+func (s *Store) RenewLease(host string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return ErrPeerNotFound
+	}
+	now := time.Now()
+	e.renewedAt = now
+	e.lastSeen = now
+	return nil
+}
+
+// Hosts returns the hosts of every registered peer.
+func (s *Store) Hosts() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hosts := make([]string, 0, len(s.store))
+	for host := range s.store {
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// RecordProbeSuccess marks a peer as healthy after it answered a probe in rtt.
+func (s *Store) RecordProbeSuccess(host string, rtt time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return ErrPeerNotFound
+	}
+	e.health = Healthy
+	e.failures = 0
+	e.lastSeen = time.Now()
+	e.rtt = rtt
+	return nil
+}
+
+// RecordProbeFailure counts a failed probe against a peer and returns its new health.
+func (s *Store) RecordProbeFailure(host string, t Thresholds) (Health, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return Dead, ErrPeerNotFound
+	}
+
+	e.failures++
+	switch {
+	case e.failures >= t.DeadAfter:
+		e.health = Dead
+	case e.failures >= t.SuspectAfter:
+		e.health = Suspect
+	}
+	return e.health, nil
+}
+
+// RecordLoad keeps the load a peer reported last.
+func (s *Store) RecordLoad(host string, load Load) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return ErrPeerNotFound
+	}
+	e.load = load
+	return nil
+}
+
+// SetDraining marks a peer as leaving the network, or back to serving.
+func (s *Store) SetDraining(host string, draining bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.store[host]
+	if !ok {
+		return ErrPeerNotFound
+	}
+	e.draining = draining
+	return nil
+}
+
+// SetUnproven marks a content of a peer as one it failed to prove it keeps, or lifts
+// the mark. The catalog of the peer is left as the peer announced it, so its digest
+// still matches and the next sync does not undo the mark.
+func (s *Store) SetUnproven(host string, checksum string, unproven bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.store[host]; !ok {
+		return ErrPeerNotFound
+	}
+
+	marks, ok := s.unproven[host]
+	if !unproven {
+		delete(marks, checksum)
+		if len(marks) == 0 {
+			delete(s.unproven, host)
+		}
+		return nil
+	}
+
+	if !ok {
+		marks = map[string]struct{}{}
+		s.unproven[host] = marks
+	}
+	marks[checksum] = struct{}{}
+	return nil
+}
+
+// isUnproven reports whether host failed to prove it keeps the content, callers must
+// hold the read lock.
+func (s *Store) isUnproven(host string, checksum string) bool {
+	_, ok := s.unproven[host][checksum]
+	return ok
+}
+
+// RemoveExpired removes every peer that did not renew its lease within ttl and
+// returns their hosts.
+func (s *Store) RemoveExpired(ttl time.Duration) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []string
+	deadline := time.Now().Add(-ttl)
+
+	for host, e := range s.store {
+		if e.renewedAt.Before(deadline) {
+			expired = append(expired, host)
+			s.unindex(host, e.files)
+			delete(s.store, host)
+			s.publishLeave(host, e.namespaces, e.files)
+		}
+	}
+
+	//piggyback on the reaper to forget records that faded away, marks are kept until
+	//the host proves the content again.
+	s.forgetStandings(time.Now())
+	return expired, nil
+}
+
+// index adds the files of host into the inverted indexes, callers must hold the write lock.
+func (s *Store) index(host string, files Files) {
+	for _, file := range files {
+		addHost(s.byName, file.Name, host)
+		addHost(s.byChecksum, file.Checksum, host)
+	}
+}
+
+// unindex removes the files of host from the inverted indexes, callers must hold the write lock.
+func (s *Store) unindex(host string, files Files) {
+	for _, file := range files {
+		removeHost(s.byName, file.Name, host)
+		removeHost(s.byChecksum, file.Checksum, host)
+	}
+}
+
+func addHost(index map[string]hostSet, key string, host string) {
+	hosts, ok := index[key]
+	if !ok {
+		hosts = hostSet{}
+		index[key] = hosts
+	}
+	hosts[host]++
+}
+
+func removeHost(index map[string]hostSet, key string, host string) {
+	hosts, ok := index[key]
+	if !ok {
+		return
+	}
+
+	hosts[host]--
+	if hosts[host] <= 0 {
+		delete(hosts, host)
+	}
+
+	if len(hosts) == 0 {
+		delete(index, key)
+	}
 }
@@ -1,9 +1,12 @@
 package peerstore_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
 )
@@ -112,3 +115,441 @@ func TestGetAllPeers(t *testing.T) {
 
 	}
 }
+
+func TestRemoveExpired(t *testing.T) {
+	store := peerstore.New()
+	host := "127.0.0.1:50051"
+	store.RegisterPeer(host, []peerstore.FileMetadata{{Name: "file1.txt", Size: 10}})
+
+	if expired, _ := store.RemoveExpired(time.Hour); len(expired) != 0 {
+		t.Fatalf("expected no expired peers, got %v", expired)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := store.RenewLease(host); err != nil {
+		t.Fatalf("expected to renew lease: %s", err)
+	}
+
+	if expired, _ := store.RemoveExpired(time.Second); len(expired) != 0 {
+		t.Fatalf("expected renewed peer to be kept, got %v", expired)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	expired, err := store.RemoveExpired(time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected to remove expired peers: %s", err)
+	}
+	if len(expired) != 1 || expired[0] != host {
+		t.Fatalf("expired=[%s], got %v", host, expired)
+	}
+
+	if _, err := store.GetPeerByHost(host); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+
+	if err := store.RenewLease(host); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+}
+
+func TestRecordProbe(t *testing.T) {
+	store := peerstore.New()
+	host := "127.0.0.1:50051"
+	store.RegisterPeer(host, nil)
+	thresholds := peerstore.Thresholds{SuspectAfter: 2, DeadAfter: 3}
+
+	expected := []peerstore.Health{peerstore.Healthy, peerstore.Suspect, peerstore.Dead}
+	for i, want := range expected {
+		health, err := store.RecordProbeFailure(host, thresholds)
+		if err != nil {
+			t.Fatalf("expected to record failure: %s", err)
+		}
+		if health != want {
+			t.Fatalf("failure[%d]: health=%s, got %s", i+1, want, health)
+		}
+	}
+
+	if err := store.RecordProbeSuccess(host, 5*time.Millisecond); err != nil {
+		t.Fatalf("expected to record success: %s", err)
+	}
+
+	peer := store.GetAllPeers()[0]
+	if peer.Health != peerstore.Healthy {
+		t.Errorf("health=%s, got %s", peerstore.Healthy, peer.Health)
+	}
+	if peer.RTT != 5*time.Millisecond {
+		t.Errorf("rtt=%s, got %s", 5*time.Millisecond, peer.RTT)
+	}
+
+	if _, err := store.RecordProbeFailure("0.0.0.0:9000", thresholds); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+}
+
+func TestGetPeersForFileIndex(t *testing.T) {
+	store := peerstore.New()
+	file1 := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	file2 := peerstore.FileMetadata{Name: "file2.txt", Size: 20, Checksum: "hash-2"}
+
+	store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{file1})
+	store.RegisterPeer("127.0.0.1:8000", []peerstore.FileMetadata{file1, file2})
+
+	if peers := store.GetPeersForFile(file1.Name); len(peers) != 2 {
+		t.Fatalf("length=%d, got %d", 2, len(peers))
+	}
+
+	//file1 is gone from the second peer after an update
+	store.UpdatePeer("127.0.0.1:8000", []peerstore.FileMetadata{file2})
+	peers := store.GetPeersForFile(file1.Name)
+	if len(peers) != 1 || peers[0].Host != "127.0.0.1:9000" {
+		t.Fatalf("expected only 127.0.0.1:9000 to have %s, got %v", file1.Name, peers)
+	}
+
+	//re-registering replaces the old files as well
+	store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{file2})
+	if peers := store.GetPeersForFile(file1.Name); len(peers) != 0 {
+		t.Fatalf("length=%d, got %d", 0, len(peers))
+	}
+
+	store.RemovePeerByHost("127.0.0.1:8000")
+	peers = store.GetPeersForFile(file2.Name)
+	if len(peers) != 1 || peers[0].Host != "127.0.0.1:9000" {
+		t.Fatalf("expected only 127.0.0.1:9000 to have %s, got %v", file2.Name, peers)
+	}
+
+	if peers[0].Files[0] != file2 {
+		t.Fatalf("file=%v, got %v", file2, peers[0].Files[0])
+	}
+}
+
+func BenchmarkGetPeersForFile(b *testing.B) {
+	const filesPerPeer = 10
+	const replicas = 3
+
+	for _, size := range []int{100, 1_000, 10_000} {
+		b.Run(fmt.Sprintf("peers=%d", size), func(b *testing.B) {
+			store := peerstore.New()
+			for i := range size {
+				files := make([]peerstore.FileMetadata, filesPerPeer)
+				for j := range files {
+					name := fmt.Sprintf("file-%d-%d.txt", i, j)
+					files[j] = peerstore.FileMetadata{Name: name, Size: 1024, Checksum: name}
+				}
+
+				//a handful of peers hold the file we look for
+				if i < replicas {
+					files[0] = peerstore.FileMetadata{Name: "wanted.txt", Size: 1024, Checksum: "wanted"}
+				}
+				store.RegisterPeer(fmt.Sprintf("10.0.%d.%d:50052", i/256, i%256), files)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				if peers := store.GetPeersForFile("wanted.txt"); len(peers) != replicas {
+					b.Fatalf("length=%d, got %d", replicas, len(peers))
+				}
+			}
+		})
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	file1 := peerstore.FileMetadata{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	file2 := peerstore.FileMetadata{Name: "file2.txt", Size: 20, Checksum: "hash-2"}
+	file3 := peerstore.FileMetadata{Name: "file3.txt", Size: 30, Checksum: "hash-3"}
+
+	store := peerstore.New()
+	host := "127.0.0.1:9000"
+	store.RegisterPeer(host, []peerstore.FileMetadata{file1, file2})
+	if err := store.SetCatalogSeq(host, 2); err != nil {
+		t.Fatalf("expected to set catalog seq: %s", err)
+	}
+
+	digest, err := store.ApplyChanges(host, 3, []peerstore.FileMetadata{file3}, []string{file1.Name})
+	if err != nil {
+		t.Fatalf("expected to apply changes: %s", err)
+	}
+
+	if peers := store.GetPeersForFile(file1.Name); len(peers) != 0 {
+		t.Fatalf("expected %s to be removed, got %v", file1.Name, peers)
+	}
+
+	if peers := store.GetPeersForFile(file3.Name); len(peers) != 1 {
+		t.Fatalf("expected %s to be added, got %v", file3.Name, peers)
+	}
+
+	//digest must match the one of the same catalog sent whole
+	expected := peerstore.New()
+	expected.RegisterPeer(host, []peerstore.FileMetadata{file3, file2})
+	_, wanted, _ := expected.Catalog(host)
+	if digest != wanted {
+		t.Fatalf("digest=%s, got %s", wanted, digest)
+	}
+
+	//a missed change
+	if _, err := store.ApplyChanges(host, 5, []peerstore.FileMetadata{file1}, nil); !errors.Is(err, peerstore.ErrSequenceGap) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrSequenceGap, err)
+	}
+
+	seq, _, err := store.Catalog(host)
+	if err != nil {
+		t.Fatalf("expected to get catalog: %s", err)
+	}
+	if seq != 3 {
+		t.Fatalf("seq=%d, got %d", 3, seq)
+	}
+
+	if peers := store.GetPeersForFile(file1.Name); len(peers) != 0 {
+		t.Fatal("expected a rejected change to leave the catalog untouched")
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	store := peerstore.New()
+
+	report := peerstore.FileMetadata{Name: "report.pdf", Size: 10, Checksum: "report-a"}
+	reportB := peerstore.FileMetadata{Name: "report.pdf", Size: 12, Checksum: "report-b"}
+
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{report}, "team-a"))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9001", []peerstore.FileMetadata{report}, "team-a", "team-b"))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9002", []peerstore.FileMetadata{reportB}, "team-b"))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9003", []peerstore.FileMetadata{reportB}))
+
+	hostsOf := func(peers []peerstore.Peer) []string {
+		hosts := make([]string, len(peers))
+		for i, p := range peers {
+			hosts[i] = p.Host
+		}
+		slices.Sort(hosts)
+		return hosts
+	}
+
+	tests := map[string]struct {
+		namespaces []string
+		expected   []string
+	}{
+		"team-a":      {namespaces: []string{"team-a"}, expected: []string{"127.0.0.1:9000", "127.0.0.1:9001"}},
+		"team-b":      {namespaces: []string{"team-b"}, expected: []string{"127.0.0.1:9001", "127.0.0.1:9002"}},
+		"default":     {namespaces: []string{"default"}, expected: []string{"127.0.0.1:9003"}},
+		"unknown":     {namespaces: []string{"team-c"}, expected: []string{}},
+		"every peer":  {namespaces: nil, expected: []string{"127.0.0.1:9000", "127.0.0.1:9001", "127.0.0.1:9002", "127.0.0.1:9003"}},
+		"two of them": {namespaces: []string{"team-a", "default"}, expected: []string{"127.0.0.1:9000", "127.0.0.1:9001", "127.0.0.1:9003"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := hostsOf(store.GetPeersForFile("report.pdf", test.namespaces...)); !slices.Equal(got, test.expected) {
+				t.Errorf("peers for file=%v, got %v", test.expected, got)
+			}
+
+			page, _ := store.GetPeersPage("", 10, test.namespaces...)
+			if got := hostsOf(page); !slices.Equal(got, test.expected) {
+				t.Errorf("page=%v, got %v", test.expected, got)
+			}
+
+			if got := store.Stats(test.namespaces...).Peers; got != len(test.expected) {
+				t.Errorf("stats peers=%d, got %d", len(test.expected), got)
+			}
+		})
+	}
+
+	//both contents only meet in team-b.
+	if conflicts := store.Conflicts("team-a"); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts in team-a, got %+v", conflicts)
+	}
+	if conflicts := store.Conflicts("team-b"); len(conflicts) != 1 {
+		t.Errorf("expected a conflict in team-b, got %+v", conflicts)
+	}
+
+	results := store.SearchFiles(peerstore.Query{Namespaces: []string{"team-a"}})
+	if len(results) != 1 || results[0].File != report || results[0].Peers != 2 {
+		t.Errorf("expected report.pdf on two peers, got %+v", results)
+	}
+}
+
+func TestNamespacesChangeOnRegistration(t *testing.T) {
+	store := peerstore.New()
+	report := peerstore.FileMetadata{Name: "report.pdf", Size: 10, Checksum: "report"}
+
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{report}, "team-a"))
+
+	w, err := store.Watch(0)
+	mustNotFail(t, err)
+	defer w.Close()
+
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{report}, "team-b"))
+
+	//team-a sees the peer leave, team-b sees it join.
+	expected := []struct {
+		kind      peerstore.EventKind
+		namespace string
+	}{
+		{peerstore.EventFileRemoved, "team-a"},
+		{peerstore.EventPeerUnregistered, "team-a"},
+		{peerstore.EventPeerRegistered, "team-b"},
+		{peerstore.EventFileAnnounced, "team-b"},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for _, e := range expected {
+		ev, err := w.Next(ctx)
+		mustNotFail(t, err)
+		if ev.Kind != e.kind || !slices.Equal(ev.Namespaces, []string{e.namespace}) {
+			t.Fatalf("event=%s in %s, got %s in %v", e.kind, e.namespace, ev.Kind, ev.Namespaces)
+		}
+	}
+
+	if peers := store.GetPeersForFile("report.pdf", "team-a"); len(peers) != 0 {
+		t.Fatalf("expected the peer to have left team-a, got %+v", peers)
+	}
+}
+
+func TestReportPeer(t *testing.T) {
+	store := peerstore.New()
+	host := "127.0.0.1:50051"
+	store.RegisterPeer(host, nil)
+	reputation := peerstore.Reputation{HalfLife: time.Hour, BanThreshold: 10, BanDuration: time.Minute, MinReporters: 2}
+
+	report := func(host string, reporter string, penalty float64) peerstore.Standing {
+		t.Helper()
+		standing, err := store.ReportPeer(host, peerstore.Report{Reporter: reporter, Penalty: penalty}, reputation)
+		if err != nil {
+			t.Fatalf("expected to report peer: %s", err)
+		}
+		return standing
+	}
+
+	standing := report(host, "a", 5)
+	if standing.Penalty < 4.9 || standing.Banned(time.Now()) {
+		t.Fatalf("expected a penalty of 5 without a ban, got %+v", standing)
+	}
+
+	//a reporter only has its last report counted.
+	for range 5 {
+		standing = report(host, "a", 6)
+	}
+	if standing.Penalty < 5.9 || standing.Penalty > 6.1 || standing.Banned(time.Now()) {
+		t.Fatalf("expected a penalty of 6 without a ban, got %+v", standing)
+	}
+
+	standing = report(host, "b", 5)
+	if !standing.Banned(time.Now()) || standing.Banned(time.Now().Add(2*time.Minute)) {
+		t.Fatalf("expected peer to be banned for a minute, got %+v", standing)
+	}
+
+	//records survive registering again.
+	store.RegisterPeer(host, nil)
+	peer := store.GetAllPeers()[0]
+	if peer.Penalty < 10.9 || peer.BannedUntil != standing.BannedUntil {
+		t.Errorf("expected the record to be kept, got penalty %f banned until %s", peer.Penalty, peer.BannedUntil)
+	}
+
+	//anonymous reports never ban.
+	other := "127.0.0.1:50052"
+	store.RegisterPeer(other, nil)
+	for _, reporter := range []string{"x", "y", "z"} {
+		standing, err := store.ReportPeer(other, peerstore.Report{Reporter: reporter, Penalty: 5, Anonymous: true}, reputation)
+		if err != nil {
+			t.Fatalf("expected to report peer: %s", err)
+		}
+		if standing.Banned(time.Now()) {
+			t.Fatalf("expected anonymous reports not to ban, got %+v", standing)
+		}
+	}
+
+	//reporters behind the same address count once.
+	shared := "127.0.0.1:50053"
+	store.RegisterPeer(shared, nil)
+	for _, reporter := range []string{"a", "b", "c"} {
+		standing, err := store.ReportPeer(shared, peerstore.Report{Reporter: reporter, Penalty: 5, Source: "10.0.0.1"}, reputation)
+		if err != nil {
+			t.Fatalf("expected to report peer: %s", err)
+		}
+		if standing.Banned(time.Now()) {
+			t.Fatalf("expected reporters behind one address not to ban, got %+v", standing)
+		}
+	}
+
+	//penalties fade away.
+	reputation.HalfLife = 10 * time.Millisecond
+	report(other, "a", 8)
+	time.Sleep(50 * time.Millisecond)
+	standing = report(other, "b", 3)
+	if standing.Penalty >= 10 || standing.Banned(time.Now()) {
+		t.Errorf("expected the first penalties to fade, got %+v", standing)
+	}
+
+	if _, err := store.ReportPeer("0.0.0.0:9000", peerstore.Report{Reporter: "a", Penalty: 1}, reputation); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+}
+
+func TestSetUnproven(t *testing.T) {
+	store := peerstore.New()
+	liar := "127.0.0.1:50051"
+	honest := "127.0.0.1:50052"
+	file := peerstore.FileMetadata{Name: "file.txt", Size: 10, Checksum: "hash"}
+	store.RegisterPeer(liar, []peerstore.FileMetadata{file})
+	store.RegisterPeer(honest, []peerstore.FileMetadata{file})
+
+	if err := store.SetUnproven(liar, file.Checksum, true); err != nil {
+		t.Fatalf("expected to mark content: %s", err)
+	}
+
+	hosts := func() []string {
+		var hosts []string
+		for _, p := range store.GetPeersForFile(file.Name) {
+			hosts = append(hosts, p.Host)
+		}
+		for _, p := range store.GetPeersForChecksum(file.Checksum) {
+			hosts = append(hosts, p.Host)
+		}
+		return hosts
+	}
+
+	if got := hosts(); !slices.Equal(got, []string{honest, honest}) {
+		t.Fatalf("expected only %s to be handed out, got %v", honest, got)
+	}
+	if replicas := store.UnderReplicated(2); len(replicas) != 1 || !slices.Equal(replicas[0].Hosts, []string{honest}) {
+		t.Fatalf("expected the content to be under replicated, got %+v", replicas)
+	}
+
+	//the catalog is left alone, syncing it or registering again keeps the mark.
+	seq, _, _ := store.Catalog(liar)
+	if seq != 0 {
+		t.Fatalf("seq=0, got %d", seq)
+	}
+	store.UpdatePeer(liar, []peerstore.FileMetadata{file})
+	store.RegisterPeer(liar, []peerstore.FileMetadata{file})
+	if got := hosts(); !slices.Equal(got, []string{honest, honest}) {
+		t.Fatalf("expected only %s to be handed out, got %v", honest, got)
+	}
+
+	//neither does letting the lease run out.
+	if _, err := store.RemoveExpired(0); err != nil {
+		t.Fatalf("expected to remove expired peers: %s", err)
+	}
+	store.RegisterPeer(liar, []peerstore.FileMetadata{file})
+	store.RegisterPeer(honest, []peerstore.FileMetadata{file})
+	if got := hosts(); !slices.Equal(got, []string{honest, honest}) {
+		t.Fatalf("expected only %s to be handed out, got %v", honest, got)
+	}
+
+	for _, p := range store.GetAllPeers() {
+		if p.Host == liar && !slices.Equal(p.Unproven, []string{file.Checksum}) {
+			t.Fatalf("unproven=[%s], got %v", file.Checksum, p.Unproven)
+		}
+	}
+
+	if err := store.SetUnproven(liar, file.Checksum, false); err != nil {
+		t.Fatalf("expected to lift mark: %s", err)
+	}
+	if got := hosts(); len(got) != 4 {
+		t.Fatalf("expected both peers to be handed out, got %v", got)
+	}
+
+	if err := store.SetUnproven("0.0.0.0:9000", file.Checksum, true); !errors.Is(err, peerstore.ErrPeerNotFound) {
+		t.Fatalf("error=%v, got %v", peerstore.ErrPeerNotFound, err)
+	}
+}
@@ -0,0 +1,273 @@
+package peerstore
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// minPenalty is the penalty under which a report no longer counts.
+const minPenalty = 0.01
+
+// Reputation controls how the reports against a peer add up.
+type Reputation struct {
+	// HalfLife is how long it takes a penalty to lose half of its weight.
+	HalfLife time.Duration `json:"half_life"`
+	// BanThreshold is the penalty of signed reports banning a peer once reached, for
+	// BanDuration, as long as MinReporters different reporters made them.
+	BanThreshold float64       `json:"ban_threshold"`
+	BanDuration  time.Duration `json:"ban_duration"`
+	MinReporters int           `json:"min_reporters"`
+}
+
+// DefaultReputation halves penalties every 10 minutes and bans peers reaching a penalty
+// of 10, reported by at least 2 peers, for 15 minutes.
+var DefaultReputation = Reputation{HalfLife: 10 * time.Minute, BanThreshold: 10, BanDuration: 15 * time.Minute, MinReporters: 2}
+
+// Report is a complaint about a peer. A reporter only has a single report against a
+// peer at a time, reporting again replaces it.
+type Report struct {
+	// Reporter identifies who complains, like the host of a peer.
+	Reporter string  `json:"reporter"`
+	Penalty  float64 `json:"penalty"`
+	// Anonymous reports come from callers that did not prove who they are, they move
+	// the peer back in lookups but never ban it.
+	Anonymous bool `json:"anonymous,omitempty"`
+	// Source is the IP address the report came from, reporters behind the same one
+	// count once towards MinReporters.
+	Source string `json:"source,omitempty"`
+}
+
+// Standing is the record of a peer after the reports against it.
+type Standing struct {
+	Penalty     float64
+	BannedUntil time.Time
+}
+
+// Banned reports whether the peer is banned at t.
+func (s Standing) Banned(t time.Time) bool {
+	return t.Before(s.BannedUntil)
+}
+
+// standing is what the store keeps of the reports against a host. It outlives the
+// registration, so a peer can not clear its record by registering again.
+type standing struct {
+	// reports holds the last report of every reporter.
+	reports     map[string]*report
+	halfLife    time.Duration
+	bannedUntil time.Time
+}
+
+// report is a penalty as of at, it fades away from then on.
+type report struct {
+	penalty   float64
+	at        time.Time
+	anonymous bool
+	source    string
+}
+
+// penaltyAt returns the penalty of a report faded until t.
+func (r *report) penaltyAt(t time.Time, halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		return r.penalty
+	}
+	return r.penalty * math.Exp2(-t.Sub(r.at).Seconds()/halfLife.Seconds())
+}
+
+// standingAt returns the standing at t, with the penalties faded since their reports.
+func (s *standing) standingAt(t time.Time) Standing {
+	var penalty float64
+	for _, r := range s.reports {
+		penalty += r.penaltyAt(t, s.halfLife)
+	}
+	return Standing{Penalty: penalty, BannedUntil: s.bannedUntil}
+}
+
+// signedAt returns the penalty of the signed reports at t and the number of reporters
+// behind them, reporters sharing a source count once.
+func (s *standing) signedAt(t time.Time) (float64, int) {
+	var penalty float64
+	sources := make(map[string]struct{})
+	for reporter, r := range s.reports {
+		if r.anonymous {
+			continue
+		}
+		if p := r.penaltyAt(t, s.halfLife); p >= minPenalty {
+			penalty += p
+			sources[cmp.Or(r.source, reporter)] = struct{}{}
+		}
+	}
+	return penalty, len(sources)
+}
+
+// ReportPeer records a report against a registered peer, or returns ErrPeerNotFound.
+// A report replaces the previous one of the same reporter, keeping the higher of both
+// penalties. The peer is banned once signed reports of enough reporters reach the
+// threshold, reports against a banned peer do not extend its ban. Like probe results,
+// records are not persisted.
+func (s *Store) ReportPeer(host string, rep Report, r Reputation) (Standing, error) {
+	return s.applyReport(host, rep, r, nil)
+}
+
+// applyReport records a report like ReportPeer, the ban of the peer is replaced by
+// bannedUntil when set, like by the ban the report ended with on another tracker.
+func (s *Store) applyReport(host string, rep Report, r Reputation, bannedUntil *time.Time) (Standing, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.store[host]; !ok {
+		return Standing{}, ErrPeerNotFound
+	}
+
+	now := time.Now()
+	st, ok := s.standings[host]
+	if !ok {
+		st = &standing{reports: make(map[string]*report)}
+		s.standings[host] = st
+	}
+
+	penalty := rep.Penalty
+	if old, ok := st.reports[rep.Reporter]; ok {
+		penalty = max(penalty, old.penaltyAt(now, st.halfLife))
+	}
+	st.reports[rep.Reporter] = &report{penalty: penalty, at: now, anonymous: rep.Anonymous, source: rep.Source}
+	st.halfLife = r.HalfLife
+
+	//reports made in a row reach the threshold, whatever faded in between.
+	signed, reporters := st.signedAt(now)
+	banned := now.Before(st.bannedUntil)
+	if r.BanThreshold > 0 && signed+minPenalty >= r.BanThreshold && reporters >= max(1, r.MinReporters) && !banned {
+		st.bannedUntil = now.Add(r.BanDuration)
+	}
+	if bannedUntil != nil {
+		st.bannedUntil = *bannedUntil
+	}
+	return st.standingAt(now), nil
+}
+
+// Record is what the store keeps of the reports against a host, and of the contents it
+// failed to prove it keeps.
+type Record struct {
+	// Reports are the reports that did not fade away yet, with their penalties as of
+	// the time the record was taken, by reporter.
+	Reports     []Report      `json:"reports,omitempty"`
+	HalfLife    time.Duration `json:"half_life,omitempty"`
+	BannedUntil time.Time     `json:"banned_until"`
+	// Unproven are the checksums of the contents the host failed to prove it keeps, sorted.
+	Unproven []string `json:"unproven,omitempty"`
+}
+
+// empty reports whether the record holds nothing worth keeping at t.
+func (r Record) empty(t time.Time) bool {
+	return len(r.Reports) == 0 && !t.Before(r.BannedUntil) && len(r.Unproven) == 0
+}
+
+// Record returns the record of a host, empty when it was never reported nor failed to
+// prove a content.
+func (s *Store) Record(host string) Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.recordLocked(host, time.Now())
+}
+
+// Records returns the records of every host, registered or not, that has one.
+func (s *Store) Records() map[string]Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	records := make(map[string]Record)
+	for host := range s.standings {
+		records[host] = s.recordLocked(host, now)
+	}
+	for host := range s.unproven {
+		records[host] = s.recordLocked(host, now)
+	}
+
+	maps.DeleteFunc(records, func(_ string, rec Record) bool {
+		return rec.empty(now)
+	})
+	return records
+}
+
+// RestoreRecord replaces the record of a host, registered or not, like with one taken
+// by Record on another tracker. Reports fade away from now on.
+func (s *Store) RestoreRecord(host string, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	delete(s.standings, host)
+	if len(rec.Reports) > 0 || now.Before(rec.BannedUntil) {
+		st := standing{reports: make(map[string]*report), halfLife: rec.HalfLife, bannedUntil: rec.BannedUntil}
+		for _, r := range rec.Reports {
+			st.reports[r.Reporter] = &report{penalty: r.Penalty, at: now, anonymous: r.Anonymous, source: r.Source}
+		}
+		s.standings[host] = &st
+	}
+
+	delete(s.unproven, host)
+	if len(rec.Unproven) > 0 {
+		marks := make(map[string]struct{}, len(rec.Unproven))
+		for _, checksum := range rec.Unproven {
+			marks[checksum] = struct{}{}
+		}
+		s.unproven[host] = marks
+	}
+	return nil
+}
+
+// recordLocked returns the record of a host at now, callers must hold the read lock.
+func (s *Store) recordLocked(host string, now time.Time) Record {
+	var rec Record
+	if st, ok := s.standings[host]; ok {
+		rec.HalfLife = st.halfLife
+		rec.BannedUntil = st.bannedUntil
+		for reporter, r := range st.reports {
+			if penalty := r.penaltyAt(now, st.halfLife); penalty >= minPenalty {
+				rec.Reports = append(rec.Reports, Report{Reporter: reporter, Penalty: penalty, Anonymous: r.anonymous, Source: r.source})
+			}
+		}
+		slices.SortFunc(rec.Reports, func(a, b Report) int { return strings.Compare(a.Reporter, b.Reporter) })
+	}
+
+	for checksum := range s.unproven[host] {
+		rec.Unproven = append(rec.Unproven, checksum)
+	}
+	slices.Sort(rec.Unproven)
+	return rec
+}
+
+// toPeer creates a Peer out of the entry of host with the given files, along with its
+// record. Callers must hold the read lock.
+func (s *Store) toPeer(host string, e *entry, files []FileMetadata, now time.Time) Peer {
+	p := e.toPeer(host, files)
+	if st, ok := s.standings[host]; ok {
+		current := st.standingAt(now)
+		p.Penalty = current.Penalty
+		p.BannedUntil = current.BannedUntil
+	}
+	for checksum := range s.unproven[host] {
+		p.Unproven = append(p.Unproven, checksum)
+	}
+	slices.Sort(p.Unproven)
+	return p
+}
+
+// forgetStandings drops the reports that faded away and the records left without any,
+// callers must hold the write lock.
+func (s *Store) forgetStandings(now time.Time) {
+	for host, st := range s.standings {
+		for reporter, r := range st.reports {
+			if r.penaltyAt(now, st.halfLife) < minPenalty {
+				delete(st.reports, reporter)
+			}
+		}
+		if len(st.reports) == 0 && !now.Before(st.bannedUntil) {
+			delete(s.standings, host)
+		}
+	}
+}
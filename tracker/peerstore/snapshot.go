@@ -0,0 +1,185 @@
+package peerstore
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SnapshotVersion is bumped whenever the snapshot layout changes.
+const SnapshotVersion = 1
+
+// Snapshot is a versioned document holding everything a store persists about its peers.
+// Like the disk store, it leaves out leases, probe results, loads and draining.
+type Snapshot struct {
+	Version int            `json:"version"`
+	TakenAt time.Time      `json:"taken_at"`
+	Peers   []SnapshotPeer `json:"peers"`
+	// Records are the reports and unproven contents of every host, registered or not,
+	// by host. Left out of snapshots taken before they were kept.
+	Records map[string]Record `json:"records,omitempty"`
+}
+
+// SnapshotPeer is a peer in a snapshot, along with its files sorted by name.
+type SnapshotPeer struct {
+	Host      string            `json:"host"`
+	Files     []FileMetadata    `json:"files"`
+	Seq       uint64            `json:"seq"`
+	TokenHash string            `json:"token_hash,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	// Namespaces is left out of snapshots taken before namespaces existed, their peers
+	// are restored in the default namespace.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// TakeSnapshot returns the content of s, peers sorted by host.
+func TakeSnapshot(s Storer) (Snapshot, error) {
+	peers := s.GetAllPeers()
+	slices.SortFunc(peers, func(a, b Peer) int { return cmp.Compare(a.Host, b.Host) })
+
+	snap := Snapshot{
+		Version: SnapshotVersion,
+		TakenAt: time.Now().UTC(),
+		Peers:   make([]SnapshotPeer, len(peers)),
+		Records: s.Records(),
+	}
+	for i, p := range peers {
+		seq, _, err := s.Catalog(p.Host)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("catalog: %w", err)
+		}
+		tokenHash, err := s.TokenHash(p.Host)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("token hash: %w", err)
+		}
+
+		files := slices.Clone(p.Files)
+		slices.SortFunc(files, func(a, b FileMetadata) int { return cmp.Compare(a.Name, b.Name) })
+		snap.Peers[i] = SnapshotPeer{
+			Host:       p.Host,
+			Files:      files,
+			Seq:        seq,
+			TokenHash:  tokenHash,
+			Labels:     p.Labels,
+			Namespaces: p.Namespaces,
+		}
+	}
+	return snap, nil
+}
+
+// Restore registers every peer of snap in s, replacing the peers s already has under
+// the same hosts, along with the records of snap. Restored peers get a fresh lease and
+// their reports fade away from now on.
+func Restore(s Storer, snap Snapshot) error {
+	if snap.Version != SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d", snap.Version)
+	}
+
+	for _, p := range snap.Peers {
+		if err := s.RegisterPeer(p.Host, p.Files, p.Namespaces...); err != nil {
+			return fmt.Errorf("register peer[%s]: %w", p.Host, err)
+		}
+		if err := s.SetCatalogSeq(p.Host, p.Seq); err != nil {
+			return fmt.Errorf("set catalog seq of peer[%s]: %w", p.Host, err)
+		}
+		if err := s.SetTokenHash(p.Host, p.TokenHash); err != nil {
+			return fmt.Errorf("set token hash of peer[%s]: %w", p.Host, err)
+		}
+		if err := s.SetLabels(p.Host, p.Labels); err != nil {
+			return fmt.Errorf("set labels of peer[%s]: %w", p.Host, err)
+		}
+	}
+
+	for host, rec := range snap.Records {
+		if err := s.RestoreRecord(host, rec); err != nil {
+			return fmt.Errorf("restore record of [%s]: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// FileChange is a file of a peer that differs between two snapshots.
+type FileChange struct {
+	Host string       `json:"host"`
+	File FileMetadata `json:"file"`
+	// OldChecksum is only set on changed files, File holds the new content.
+	OldChecksum string `json:"old_checksum,omitempty"`
+}
+
+// Diff lists what changed from one snapshot to another. The files of added and
+// removed peers are listed as added and removed files too.
+type Diff struct {
+	AddedPeers   []string     `json:"added_peers"`
+	RemovedPeers []string     `json:"removed_peers"`
+	AddedFiles   []FileChange `json:"added_files"`
+	RemovedFiles []FileChange `json:"removed_files"`
+	// ChangedFiles are files kept under the same name with another checksum.
+	ChangedFiles []FileChange `json:"changed_files"`
+}
+
+// Empty reports whether both snapshots hold the same peers and files.
+func (d Diff) Empty() bool {
+	return len(d.AddedPeers) == 0 && len(d.RemovedPeers) == 0 &&
+		len(d.AddedFiles) == 0 && len(d.RemovedFiles) == 0 && len(d.ChangedFiles) == 0
+}
+
+// DiffSnapshots compares snapshot a with the later snapshot b. Every list is ordered by
+// host, then by file name.
+func DiffSnapshots(a Snapshot, b Snapshot) Diff {
+	before := filesByHost(a)
+	after := filesByHost(b)
+
+	var d Diff
+	for _, host := range sortedKeys(before, after) {
+		oldFiles, hadPeer := before[host]
+		newFiles, hasPeer := after[host]
+
+		switch {
+		case !hadPeer:
+			d.AddedPeers = append(d.AddedPeers, host)
+		case !hasPeer:
+			d.RemovedPeers = append(d.RemovedPeers, host)
+		}
+
+		for _, name := range sortedKeys(oldFiles, newFiles) {
+			oldFile, had := oldFiles[name]
+			newFile, has := newFiles[name]
+
+			switch {
+			case !had:
+				d.AddedFiles = append(d.AddedFiles, FileChange{Host: host, File: newFile})
+			case !has:
+				d.RemovedFiles = append(d.RemovedFiles, FileChange{Host: host, File: oldFile})
+			case oldFile.Checksum != newFile.Checksum:
+				d.ChangedFiles = append(d.ChangedFiles, FileChange{Host: host, File: newFile, OldChecksum: oldFile.Checksum})
+			}
+		}
+	}
+	return d
+}
+
+func filesByHost(snap Snapshot) map[string]map[string]FileMetadata {
+	hosts := make(map[string]map[string]FileMetadata, len(snap.Peers))
+	for _, p := range snap.Peers {
+		files := make(map[string]FileMetadata, len(p.Files))
+		for _, f := range p.Files {
+			files[f.Name] = f
+		}
+		hosts[p.Host] = files
+	}
+	return hosts
+}
+
+// sortedKeys returns the keys of both maps, sorted and without duplicates.
+func sortedKeys[V any](a map[string]V, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
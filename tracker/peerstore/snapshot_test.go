@@ -0,0 +1,116 @@
+package peerstore_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	store := peerstore.New()
+
+	report := peerstore.FileMetadata{Name: "report.pdf", Size: 10, Checksum: "report"}
+	notes := peerstore.FileMetadata{Name: "notes.txt", Size: 5, Checksum: "notes"}
+
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:9000", []peerstore.FileMetadata{report, notes}, "team-a"))
+	mustNotFail(t, store.SetCatalogSeq("127.0.0.1:9000", 7))
+	mustNotFail(t, store.SetTokenHash("127.0.0.1:9000", "token-hash"))
+	mustNotFail(t, store.SetLabels("127.0.0.1:9000", map[string]string{"zone": "a"}))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:8000", nil))
+
+	//records are kept, the ones of hosts that left too.
+	reputation := peerstore.Reputation{BanThreshold: 5, BanDuration: time.Hour, MinReporters: 1}
+	_, err := store.ReportPeer("127.0.0.1:9000", peerstore.Report{Reporter: "127.0.0.1:8000", Penalty: 5, Source: "10.0.0.8"}, reputation)
+	mustNotFail(t, err)
+	mustNotFail(t, store.SetUnproven("127.0.0.1:9000", report.Checksum, true))
+	mustNotFail(t, store.RegisterPeer("127.0.0.1:7000", []peerstore.FileMetadata{notes}))
+	mustNotFail(t, store.SetUnproven("127.0.0.1:7000", notes.Checksum, true))
+	mustNotFail(t, store.RemovePeerByHost("127.0.0.1:7000"))
+
+	snap, err := peerstore.TakeSnapshot(store)
+	mustNotFail(t, err)
+
+	//peers by host, files by name.
+	if snap.Peers[0].Host != "127.0.0.1:8000" || snap.Peers[1].Files[0].Name != "notes.txt" {
+		t.Fatalf("expected a sorted snapshot, got %+v", snap.Peers)
+	}
+
+	data, err := json.Marshal(snap)
+	mustNotFail(t, err)
+
+	var decoded peerstore.Snapshot
+	mustNotFail(t, json.Unmarshal(data, &decoded))
+
+	restored := peerstore.New()
+	mustNotFail(t, peerstore.Restore(restored, decoded))
+
+	again, err := peerstore.TakeSnapshot(restored)
+	mustNotFail(t, err)
+	if !reflect.DeepEqual(again.Peers, snap.Peers) {
+		t.Fatalf("peers=%+v, got %+v", snap.Peers, again.Peers)
+	}
+	if len(snap.Records) != 2 || snap.Records["127.0.0.1:7000"].Unproven[0] != notes.Checksum {
+		t.Fatalf("expected the records of both reported hosts, got %+v", snap.Records)
+	}
+	if !reflect.DeepEqual(again.Records, decoded.Records) {
+		t.Fatalf("records=%+v, got %+v", decoded.Records, again.Records)
+	}
+	if peer := restored.GetPeersForChecksum(report.Checksum); len(peer) != 0 {
+		t.Fatalf("expected the unproven content to stay out of lookups, got %v", peer)
+	}
+
+	decoded.Version = peerstore.SnapshotVersion + 1
+	if err := peerstore.Restore(peerstore.New(), decoded); err == nil {
+		t.Fatal("expected an error for an unsupported version")
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	report := peerstore.FileMetadata{Name: "report.pdf", Size: 10, Checksum: "report-v1"}
+	reportV2 := peerstore.FileMetadata{Name: "report.pdf", Size: 12, Checksum: "report-v2"}
+	notes := peerstore.FileMetadata{Name: "notes.txt", Size: 5, Checksum: "notes"}
+	slides := peerstore.FileMetadata{Name: "slides.pdf", Size: 50, Checksum: "slides"}
+
+	before := peerstore.Snapshot{
+		Version: peerstore.SnapshotVersion,
+		Peers: []peerstore.SnapshotPeer{
+			{Host: "127.0.0.1:9000", Files: []peerstore.FileMetadata{report, notes}},
+			{Host: "127.0.0.1:8000", Files: []peerstore.FileMetadata{slides}},
+		},
+	}
+	after := peerstore.Snapshot{
+		Version: peerstore.SnapshotVersion,
+		Peers: []peerstore.SnapshotPeer{
+			{Host: "127.0.0.1:9000", Files: []peerstore.FileMetadata{reportV2, slides}},
+			{Host: "127.0.0.1:7000", Files: []peerstore.FileMetadata{notes}},
+		},
+	}
+
+	diff := peerstore.DiffSnapshots(before, after)
+
+	expected := peerstore.Diff{
+		AddedPeers:   []string{"127.0.0.1:7000"},
+		RemovedPeers: []string{"127.0.0.1:8000"},
+		AddedFiles: []peerstore.FileChange{
+			{Host: "127.0.0.1:7000", File: notes},
+			{Host: "127.0.0.1:9000", File: slides},
+		},
+		RemovedFiles: []peerstore.FileChange{
+			{Host: "127.0.0.1:8000", File: slides},
+			{Host: "127.0.0.1:9000", File: notes},
+		},
+		ChangedFiles: []peerstore.FileChange{
+			{Host: "127.0.0.1:9000", File: reportV2, OldChecksum: "report-v1"},
+		},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Fatalf("diff=%+v, got %+v", expected, diff)
+	}
+
+	if !peerstore.DiffSnapshots(after, after).Empty() {
+		t.Fatal("expected no difference between a snapshot and itself")
+	}
+}
@@ -0,0 +1,122 @@
+package peerstore
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Stats represents the size of the network and how well its contents are replicated.
+type Stats struct {
+	Peers int
+	// Files is the number of distinct contents, by checksum.
+	Files int
+	// TotalBytes counts every replica, UniqueBytes every content once.
+	TotalBytes  int64
+	UniqueBytes int64
+	// Replicas maps a number of replicas to how many contents have that many.
+	Replicas map[int]int
+	// SingleReplica are the contents only one peer serves, ordered by name.
+	SingleReplica []FileMetadata
+}
+
+// Stats computes the statistics of every registered peer, reachable or not. Given
+// namespaces, only the peers in one of them are counted.
+func (s *Store) Stats(namespaces ...string) Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := Stats{
+		Replicas: make(map[int]int),
+	}
+
+	//one file per content, the first name in order when served under several.
+	contents := make(map[string]FileMetadata, len(s.byChecksum))
+	//number of peers serving every content.
+	hosts := make(map[string]int, len(s.byChecksum))
+	for _, e := range s.store {
+		if !e.in(namespaces) {
+			continue
+		}
+		stats.Peers++
+
+		//a peer keeping a content under several names is a single replica.
+		served := make(map[string]struct{}, len(e.files))
+		for _, file := range e.files {
+			stats.TotalBytes += file.Size
+
+			if c, ok := contents[file.Checksum]; !ok || file.Name < c.Name {
+				contents[file.Checksum] = file
+			}
+
+			if _, ok := served[file.Checksum]; !ok {
+				served[file.Checksum] = struct{}{}
+				hosts[file.Checksum]++
+			}
+		}
+	}
+	stats.Files = len(contents)
+
+	for checksum, n := range hosts {
+		file := contents[checksum]
+		stats.UniqueBytes += file.Size
+		stats.Replicas[n]++
+
+		if n == 1 {
+			stats.SingleReplica = append(stats.SingleReplica, file)
+		}
+	}
+
+	slices.SortFunc(stats.SingleReplica, func(a, b FileMetadata) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Checksum, b.Checksum))
+	})
+
+	return stats
+}
+
+// Replica represents a content and the reachable peers serving it.
+type Replica struct {
+	File  FileMetadata
+	Hosts []string
+}
+
+// UnderReplicated returns the contents served by fewer than factor reachable peers,
+// the least replicated first. Draining peers and peers that failed to prove they keep
+// the content do not count, and contents no other peer serves anymore are left out,
+// there is nobody to copy them from.
+func (s *Store) UnderReplicated(factor int) []Replica {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var replicas []Replica
+	for checksum, hosts := range s.byChecksum {
+		var r Replica
+		for host := range hosts {
+			e := s.store[host]
+			if e.health == Dead || e.draining || s.isUnproven(host, checksum) {
+				continue
+			}
+
+			//the first name in order when served under several.
+			for _, file := range e.files {
+				if file.Checksum == checksum && (r.File.Name == "" || file.Name < r.File.Name) {
+					r.File = file
+				}
+			}
+			r.Hosts = append(r.Hosts, host)
+		}
+
+		if len(r.Hosts) > 0 && len(r.Hosts) < factor {
+			slices.Sort(r.Hosts)
+			replicas = append(replicas, r)
+		}
+	}
+
+	slices.SortFunc(replicas, func(a, b Replica) int {
+		return cmp.Or(
+			cmp.Compare(len(a.Hosts), len(b.Hosts)),
+			cmp.Compare(a.File.Name, b.File.Name),
+			cmp.Compare(a.File.Checksum, b.File.Checksum),
+		)
+	})
+	return replicas
+}
@@ -0,0 +1,174 @@
+// Package prober actively checks the reachability of every registered peer.
+package prober
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// DefaultInterval is the time between two probing rounds.
+	DefaultInterval = 10 * time.Second
+	// DefaultTimeout is how long a single ping can take.
+	DefaultTimeout = 3 * time.Second
+)
+
+// DefaultThresholds marks a peer suspect after one failure and dead after three.
+var DefaultThresholds = peerstore.Thresholds{SuspectAfter: 1, DeadAfter: 3}
+
+// Prober pings every peer of the store and records the results.
+type Prober struct {
+	store      peerstore.Storer
+	interval   time.Duration
+	timeout    time.Duration
+	thresholds peerstore.Thresholds
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// Config represents all the settings of a prober, zero values fall back to defaults.
+type Config struct {
+	Store      peerstore.Storer
+	Interval   time.Duration
+	Timeout    time.Duration
+	Thresholds peerstore.Thresholds
+}
+
+// New creates a new prober.
+func New(conf *Config) *Prober {
+	p := Prober{
+		store:      conf.Store,
+		interval:   conf.Interval,
+		timeout:    conf.Timeout,
+		thresholds: conf.Thresholds,
+		conns:      make(map[string]*grpc.ClientConn),
+	}
+
+	if p.interval <= 0 {
+		p.interval = DefaultInterval
+	}
+
+	if p.timeout <= 0 {
+		p.timeout = DefaultTimeout
+	}
+
+	if p.thresholds.DeadAfter <= 0 {
+		p.thresholds = DefaultThresholds
+	}
+	return &p
+}
+
+// Run probes all peers every interval until ctx is done.
+func (p *Prober) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	defer p.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.ProbeAll(ctx)
+		}
+	}
+}
+
+// ProbeAll pings every registered peer concurrently and waits for all of them.
+func (p *Prober) ProbeAll(ctx context.Context) {
+	hosts := p.store.Hosts()
+	p.dropStaleConns(hosts)
+
+	var wg sync.WaitGroup
+	wg.Add(len(hosts))
+	for _, host := range hosts {
+		go func() {
+			defer wg.Done()
+			p.probe(ctx, host)
+		}()
+	}
+	wg.Wait()
+}
+
+// Close closes every connection the prober keeps.
+func (p *Prober) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for host, conn := range p.conns {
+		conn.Close()
+		delete(p.conns, host)
+	}
+}
+
+func (p *Prober) probe(ctx context.Context, host string) {
+	conn, err := p.conn(host)
+	if err != nil {
+		p.fail(host, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err = peer.NewPeerServiceClient(conn).Ping(ctx, &peer.PingRequest{Message: "tracker probe"})
+	if err != nil {
+		p.fail(host, err)
+		return
+	}
+
+	//peer may have been removed in the meantime, nothing to record then.
+	_ = p.store.RecordProbeSuccess(host, time.Since(start))
+}
+
+func (p *Prober) fail(host string, err error) {
+	health, storeErr := p.store.RecordProbeFailure(host, p.thresholds)
+	if storeErr != nil {
+		return
+	}
+	log.Printf("probe peer[%s] failed, now %s: %s\n", host, health, err)
+}
+
+// conn returns a cached connection to host or dials a new one.
+func (p *Prober) conn(host string) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn, ok := p.conns[host]; ok {
+		return conn, nil
+	}
+
+	conn, err := grpc.NewClient(host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	p.conns[host] = conn
+	return conn, nil
+}
+
+// dropStaleConns closes connections of peers that are no longer registered.
+func (p *Prober) dropStaleConns(hosts []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	registered := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		registered[host] = struct{}{}
+	}
+
+	for host, conn := range p.conns {
+		if _, ok := registered[host]; !ok {
+			conn.Close()
+			delete(p.conns, host)
+		}
+	}
+}
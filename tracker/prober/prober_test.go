@@ -0,0 +1,87 @@
+package prober_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/prober"
+	"google.golang.org/grpc"
+)
+
+func TestProbeAll(t *testing.T) {
+	alive := startPeer(t)
+
+	//grab a free port and release it, nothing will answer there.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	unreachable := lis.Addr().String()
+	lis.Close()
+
+	store := peerstore.New()
+	store.RegisterPeer(alive, nil)
+	store.RegisterPeer(unreachable, nil)
+
+	p := prober.New(&prober.Config{
+		Store:      store,
+		Thresholds: peerstore.Thresholds{SuspectAfter: 1, DeadAfter: 2},
+	})
+	defer p.Close()
+
+	p.ProbeAll(context.Background())
+	health := healthByHost(store)
+	if health[alive] != peerstore.Healthy {
+		t.Errorf("health=%s, got %s", peerstore.Healthy, health[alive])
+	}
+	if health[unreachable] != peerstore.Suspect {
+		t.Errorf("health=%s, got %s", peerstore.Suspect, health[unreachable])
+	}
+
+	p.ProbeAll(context.Background())
+	health = healthByHost(store)
+	if health[alive] != peerstore.Healthy {
+		t.Errorf("health=%s, got %s", peerstore.Healthy, health[alive])
+	}
+	if health[unreachable] != peerstore.Dead {
+		t.Errorf("health=%s, got %s", peerstore.Dead, health[unreachable])
+	}
+}
+
+// =============================================================================
+// utils
+func healthByHost(store *peerstore.Store) map[string]peerstore.Health {
+	health := make(map[string]peerstore.Health)
+	for _, p := range store.GetAllPeers() {
+		health[p.Host] = p.Health
+	}
+	return health
+}
+
+func startPeer(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	server := grpc.NewServer()
+	peer.RegisterPeerServiceServer(server, peerServiceMock{})
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+// ==============================================================================
+// mocks
+type peerServiceMock struct {
+	peer.UnimplementedPeerServiceServer
+}
+
+func (peerServiceMock) Ping(ctx context.Context, in *peer.PingRequest) (*peer.PingResponse, error) {
+	return &peer.PingResponse{Status: "OK", Message: in.GetMessage()}, nil
+}
@@ -0,0 +1,237 @@
+// Package ratelimit keeps a single caller from flooding the tracker, every caller gets
+// a token bucket per rpc and calls over the limit are rejected with ResourceExhausted.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// sweepInterval is how often the buckets that refilled are dropped.
+const sweepInterval = time.Minute
+
+// Limit is the size of a token bucket, a zero Rate turns limiting off. Without a Burst,
+// a caller can send a single second of calls at once.
+type Limit struct {
+	// Rate is the number of calls a second a caller can keep on sending.
+	Rate float64
+	// Burst is the number of calls a caller can send at once.
+	Burst int
+}
+
+// normalize gives a limit without a burst room for a single second of calls.
+func (l Limit) normalize() Limit {
+	if l.Burst < 1 {
+		l.Burst = max(1, int(math.Ceil(l.Rate)))
+	}
+	return l
+}
+
+// bucket holds the tokens left to a caller for a single rpc.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// key identifies the bucket of a caller for a single rpc.
+type key struct {
+	caller string
+	method string
+}
+
+// Limiter enforces the limits of every rpc on every caller.
+type Limiter struct {
+	limit   Limit
+	methods map[string]Limit
+
+	mu        sync.Mutex
+	buckets   map[key]*bucket
+	lastSweep time.Time
+}
+
+// Config represents all the settings of a limiter.
+type Config struct {
+	// Limit applies to the rpcs without their own limit, the zero Limit leaves them alone.
+	Limit Limit
+	// Methods holds the limits of single rpcs by name, like "UpdatePeer".
+	Methods map[string]Limit
+}
+
+// New creates a new limiter.
+func New(conf *Config) *Limiter {
+	methods := make(map[string]Limit, len(conf.Methods))
+	for name, m := range conf.Methods {
+		methods[name] = m.normalize()
+	}
+
+	return &Limiter{
+		limit:     conf.Limit.normalize(),
+		methods:   methods,
+		buckets:   make(map[key]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// ParseLimit parses a limit written as "rate:burst", like "0.5:5". The burst can be
+// left out, see Limit.
+func ParseLimit(def string) (Limit, error) {
+	rateDef, burstDef, hasBurst := strings.Cut(strings.TrimSpace(def), ":")
+
+	rate, err := strconv.ParseFloat(rateDef, 64)
+	if err != nil || rate < 0 {
+		return Limit{}, fmt.Errorf("invalid rate %q", rateDef)
+	}
+
+	var burst int
+	if hasBurst {
+		burst, err = strconv.Atoi(burstDef)
+		if err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("invalid burst %q", burstDef)
+		}
+	}
+	return Limit{Rate: rate, Burst: burst}.normalize(), nil
+}
+
+// ParseMethods parses the limits of single rpcs written as a comma separated list of
+// "name=rate:burst", like "UpdatePeer=0.2:2,Heartbeat=1:3".
+func ParseMethods(def string) (map[string]Limit, error) {
+	methods := make(map[string]Limit)
+	for _, item := range strings.Split(def, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, limitDef, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid limit %q, expected name=rate:burst", item)
+		}
+
+		limit, err := ParseLimit(limitDef)
+		if err != nil {
+			return nil, fmt.Errorf("limit of %s: %w", name, err)
+		}
+		methods[strings.TrimSpace(name)] = limit
+	}
+	return methods, nil
+}
+
+// UnaryInterceptor rejects the calls of callers that went over the limit of the rpc.
+func (l *Limiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.Allow(caller(ctx), info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is the UnaryInterceptor of streaming calls, a stream takes a single
+// token when opened.
+func (l *Limiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.Allow(caller(ss.Context()), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// Allow takes a token from the bucket of the caller for the method. Without tokens
+// left, it returns a ResourceExhausted error telling when to retry.
+func (l *Limiter) Allow(caller string, method string) error {
+	limit := l.limitOf(method)
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	k := key{caller: caller, method: method}
+	b, ok := l.buckets[k]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[k] = b
+	}
+
+	b.tokens = min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return nil
+	}
+
+	retryAfter := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	st := status.Newf(codes.ResourceExhausted, "rate limit of %s exceeded, retry in %s", path.Base(method), retryAfter.Round(time.Millisecond))
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// RetryAfter returns how long to wait before retrying a call rejected by a limiter,
+// or false when err does not tell.
+func RetryAfter(err error) (time.Duration, bool) {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+func (l *Limiter) limitOf(method string) Limit {
+	if limit, ok := l.methods[path.Base(method)]; ok {
+		return limit
+	}
+	return l.limit
+}
+
+// sweep drops the buckets that are full again, they are no different from new ones.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for k, b := range l.buckets {
+		limit := l.limitOf(k.method)
+		if b.tokens+now.Sub(b.last).Seconds()*limit.Rate >= float64(limit.Burst) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// caller identifies the caller by its ip address, so a peer can not get more calls by
+// changing ports or the host it claims.
+func caller(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,120 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const updatePeer = "/tracker.TrackerService/UpdatePeer"
+
+func TestAllow(t *testing.T) {
+	limiter := ratelimit.New(&ratelimit.Config{Limit: ratelimit.Limit{Rate: 0.01, Burst: 2}})
+
+	for i := range 2 {
+		if err := limiter.Allow("10.0.0.1", updatePeer); err != nil {
+			t.Fatalf("expected call %d to be allowed: %s", i, err)
+		}
+	}
+
+	err := limiter.Allow("10.0.0.1", updatePeer)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("code=%s, got %s", codes.ResourceExhausted, status.Code(err))
+	}
+
+	retryAfter, ok := ratelimit.RetryAfter(err)
+	if !ok {
+		t.Fatal("expected the error to tell when to retry")
+	}
+	//a token every 100s.
+	if retryAfter < 99*time.Second || retryAfter > 100*time.Second {
+		t.Fatalf("retryAfter=100s, got %s", retryAfter)
+	}
+
+	//other callers and other rpcs have their own buckets.
+	if err := limiter.Allow("10.0.0.2", updatePeer); err != nil {
+		t.Fatalf("expected another caller to be allowed: %s", err)
+	}
+	if err := limiter.Allow("10.0.0.1", "/tracker.TrackerService/Heartbeat"); err != nil {
+		t.Fatalf("expected another rpc to be allowed: %s", err)
+	}
+}
+
+func TestAllowRefills(t *testing.T) {
+	limiter := ratelimit.New(&ratelimit.Config{Limit: ratelimit.Limit{Rate: 20, Burst: 1}})
+
+	if err := limiter.Allow("10.0.0.1", updatePeer); err != nil {
+		t.Fatalf("expected the first call to be allowed: %s", err)
+	}
+	if err := limiter.Allow("10.0.0.1", updatePeer); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("code=%s, got %s", codes.ResourceExhausted, status.Code(err))
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if err := limiter.Allow("10.0.0.1", updatePeer); err != nil {
+		t.Fatalf("expected the bucket to refill: %s", err)
+	}
+}
+
+func TestMethodLimits(t *testing.T) {
+	methods, err := ratelimit.ParseMethods("UpdatePeer=0.01:1, GetStats=0")
+	if err != nil {
+		t.Fatalf("expected to parse limits: %s", err)
+	}
+
+	limiter := ratelimit.New(&ratelimit.Config{Limit: ratelimit.Limit{Rate: 0.01, Burst: 3}, Methods: methods})
+
+	if err := limiter.Allow("10.0.0.1", updatePeer); err != nil {
+		t.Fatalf("expected the first update to be allowed: %s", err)
+	}
+	if err := limiter.Allow("10.0.0.1", updatePeer); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("code=%s, got %s", codes.ResourceExhausted, status.Code(err))
+	}
+
+	//a rate of 0 is unlimited.
+	for i := range 10 {
+		if err := limiter.Allow("10.0.0.1", "/tracker.TrackerService/GetStats"); err != nil {
+			t.Fatalf("expected call %d to be allowed: %s", i, err)
+		}
+	}
+
+	//without a limit of their own, rpcs are left alone unless a limit is set.
+	limiter = ratelimit.New(&ratelimit.Config{Methods: methods})
+	for i := range 10 {
+		if err := limiter.Allow("10.0.0.1", "/tracker.TrackerService/Heartbeat"); err != nil {
+			t.Fatalf("expected call %d to be allowed: %s", i, err)
+		}
+	}
+
+	for _, def := range []string{"UpdatePeer", "UpdatePeer=fast", "UpdatePeer=1:0", "UpdatePeer=-1"} {
+		if _, err := ratelimit.ParseMethods(def); err == nil {
+			t.Errorf("expected %q to be rejected", def)
+		}
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	limiter := ratelimit.New(&ratelimit.Config{Limit: ratelimit.Limit{Rate: 0.01, Burst: 1}})
+	interceptor := limiter.UnaryInterceptor()
+
+	info := grpc.UnaryServerInfo{FullMethod: updatePeer}
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	//the same ip on another port is the same caller.
+	first := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9000}})
+	second := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9001}})
+
+	if _, err := interceptor(first, nil, &info, handler); err != nil {
+		t.Fatalf("expected the first call to be allowed: %s", err)
+	}
+	if _, err := interceptor(second, nil, &info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("code=%s, got %s", codes.ResourceExhausted, status.Code(err))
+	}
+}
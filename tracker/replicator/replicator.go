@@ -0,0 +1,209 @@
+// Package replicator keeps every content on the network served by a minimum number of
+// peers, asking healthy peers to copy the contents that fell below it.
+package replicator
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// DefaultInterval is the time between two repair rounds.
+	DefaultInterval = 30 * time.Second
+	// DefaultTimeout is how long a peer can take to copy a single content.
+	DefaultTimeout = 5 * time.Minute
+)
+
+// TokenKey is the gRPC metadata key the tracker sends the hash of the token of a peer in
+// when asking it for a copy. Besides the peer, only the tracker knows it.
+const TokenKey = "tracker-token"
+
+// Replicator repairs the contents served by fewer peers than the replication factor.
+type Replicator struct {
+	store    peerstore.Storer
+	factor   int
+	interval time.Duration
+	timeout  time.Duration
+	active   func() bool
+}
+
+// Config represents all the settings of a replicator, zero values fall back to defaults.
+type Config struct {
+	Store peerstore.Storer
+	// Factor is the minimum number of peers every content should be served by.
+	Factor   int
+	Interval time.Duration
+	Timeout  time.Duration
+	// Active reports whether repairs should run, like only on the leader of a cluster
+	// of trackers. Optional, repairs always run without it.
+	Active func() bool
+}
+
+// task is a copy of a content a peer is asked to make.
+type task struct {
+	target  string
+	replica peerstore.Replica
+}
+
+// New creates a new replicator.
+func New(conf *Config) *Replicator {
+	r := Replicator{
+		store:    conf.Store,
+		factor:   conf.Factor,
+		interval: conf.Interval,
+		timeout:  conf.Timeout,
+		active:   conf.Active,
+	}
+
+	if r.interval <= 0 {
+		r.interval = DefaultInterval
+	}
+
+	if r.timeout <= 0 {
+		r.timeout = DefaultTimeout
+	}
+	return &r
+}
+
+// Run repairs under replicated contents every interval until ctx is done.
+func (r *Replicator) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.RepairAll(ctx)
+		}
+	}
+}
+
+// RepairAll asks healthy peers to copy every under replicated content and waits for
+// all copies. Copies are announced by the peers themselves, so the store only reflects
+// them once the peers did.
+func (r *Replicator) RepairAll(ctx context.Context) {
+	tasks := r.plan()
+
+	var wg sync.WaitGroup
+	wg.Add(len(tasks))
+	for _, t := range tasks {
+		go func() {
+			defer wg.Done()
+			r.replicate(ctx, t)
+		}()
+	}
+	wg.Wait()
+}
+
+// plan picks the peers to copy every under replicated content to, spreading the copies
+// over the least busy healthy peers that do not serve the content yet. Copies only go to
+// peers whose namespaces all are namespaces of a source, so no new namespace sees the
+// content.
+func (r *Replicator) plan() []task {
+	if r.factor <= 1 || (r.active != nil && !r.active()) {
+		return nil
+	}
+
+	under := r.store.UnderReplicated(r.factor)
+	if len(under) == 0 {
+		return nil
+	}
+
+	var healthy []peerstore.Peer
+	namespaces := make(map[string][]string)
+	for _, p := range r.store.GetAllPeers() {
+		namespaces[p.Host] = p.Namespaces
+		if p.Health == peerstore.Healthy && !p.Draining {
+			healthy = append(healthy, p)
+		}
+	}
+
+	//number of copies handed to every peer in this round.
+	assigned := make(map[string]int)
+
+	var tasks []task
+	for _, replica := range under {
+		candidates := slices.DeleteFunc(slices.Clone(healthy), func(p peerstore.Peer) bool {
+			return slices.Contains(replica.Hosts, p.Host) || !slices.ContainsFunc(replica.Hosts, func(source string) bool {
+				return namespace.Within(p.Namespaces, namespaces[source])
+			})
+		})
+
+		rand.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		slices.SortStableFunc(candidates, func(a, b peerstore.Peer) int {
+			return cmp.Or(
+				cmp.Compare(assigned[a.Host], assigned[b.Host]),
+				cmp.Compare(a.Load.ActiveStreams, b.Load.ActiveStreams),
+			)
+		})
+
+		missing := min(r.factor-len(replica.Hosts), len(candidates))
+		for _, p := range candidates[:missing] {
+			assigned[p.Host]++
+			tasks = append(tasks, task{target: p.Host, replica: replica})
+		}
+	}
+	return tasks
+}
+
+func (r *Replicator) replicate(ctx context.Context, t task) {
+	if err := r.Copy(ctx, t.replica.File, t.replica.Hosts, t.target); err != nil {
+		log.Printf("replicate file[%s] to peer[%s]: %s\n", t.replica.File.Name, t.target, err)
+		return
+	}
+	log.Printf("replicated file[%s] to peer[%s]\n", t.replica.File.Name, t.target)
+}
+
+// Copy asks the target peer to pull a content from one of the sources and waits until
+// it is done. The target announces the copy to the tracker itself.
+func (r *Replicator) Copy(ctx context.Context, file peerstore.FileMetadata, sources []string, target string) error {
+	hash, err := r.store.TokenHash(target)
+	if err != nil {
+		return fmt.Errorf("token hash: %w", err)
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, TokenKey, hash)
+
+	in := peer.ReplicateFileRequest{
+		File: &peer.FileMetadata{
+			Name:     file.Name,
+			Size:     file.Size,
+			Checksum: file.Checksum,
+		},
+		Sources: sources,
+	}
+
+	resp, err := peer.NewPeerServiceClient(conn).ReplicateFile(ctx, &in)
+	if err != nil {
+		return fmt.Errorf("replicate file: %w", err)
+	}
+	if !resp.GetSuccess() {
+		return fmt.Errorf("replicate file: %s", resp.GetMessage())
+	}
+	return nil
+}
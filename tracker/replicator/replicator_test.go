@@ -0,0 +1,142 @@
+package replicator_test
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/peer"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/replicator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRepairAll(t *testing.T) {
+	store := peerstore.New()
+
+	report := peerstore.FileMetadata{Name: "report.pdf", Size: 100, Checksum: "report"}
+	notes := peerstore.FileMetadata{Name: "notes.txt", Size: 10, Checksum: "notes"}
+
+	holder := startPeer(t, store)
+	other := startPeer(t, store)
+	empty := startPeer(t, store)
+	dead := startPeer(t, store)
+
+	store.RegisterPeer(holder.host, []peerstore.FileMetadata{report, notes})
+	store.RegisterPeer(other.host, []peerstore.FileMetadata{notes})
+	store.RegisterPeer(empty.host, nil)
+	store.RegisterPeer(dead.host, []peerstore.FileMetadata{report})
+	for _, p := range []*peerServiceMock{holder, other, empty, dead} {
+		store.SetTokenHash(p.host, "hash-of-"+p.host)
+	}
+
+	//the dead peer neither counts as a replica nor receives copies.
+	if _, err := store.RecordProbeFailure(dead.host, peerstore.Thresholds{SuspectAfter: 1, DeadAfter: 1}); err != nil {
+		t.Fatalf("expected to record probe failure: %s", err)
+	}
+
+	r := replicator.New(&replicator.Config{Store: store, Factor: 2})
+	r.RepairAll(context.Background())
+
+	var copies []*peer.ReplicateFileRequest
+	for _, p := range []*peerServiceMock{holder, other, empty, dead} {
+		for _, in := range p.requests() {
+			if p == holder || p == dead {
+				t.Fatalf("expected peer[%s] not to be asked for a copy", p.host)
+			}
+			copies = append(copies, in)
+		}
+	}
+
+	if len(copies) != 1 {
+		t.Fatalf("copies=1, got %d", len(copies))
+	}
+	if copies[0].File.Checksum != report.Checksum || !slices.Equal(copies[0].Sources, []string{holder.host}) {
+		t.Fatalf("expected report to be copied from %s, got %v", holder.host, copies[0])
+	}
+
+	//the target is shown the hash of its own token, only the tracker knows it.
+	for _, p := range []*peerServiceMock{other, empty} {
+		for _, hash := range p.hashes() {
+			if want := "hash-of-" + p.host; hash != want {
+				t.Fatalf("token hash=%s, got %s", want, hash)
+			}
+		}
+	}
+
+	if under := store.UnderReplicated(2); len(under) != 0 {
+		t.Fatalf("expected every file to be replicated, got %v", under)
+	}
+
+	//nothing left to repair
+	r.RepairAll(context.Background())
+	if n := len(other.requests()) + len(empty.requests()); n != 1 {
+		t.Fatalf("copies=1, got %d", n)
+	}
+}
+
+// =============================================================================
+// utils
+func startPeer(t *testing.T, store *peerstore.Store) *peerServiceMock {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	mock := peerServiceMock{host: lis.Addr().String(), store: store}
+
+	server := grpc.NewServer()
+	peer.RegisterPeerServiceServer(server, &mock)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return &mock
+}
+
+// ==============================================================================
+// mocks
+
+// peerServiceMock announces every copy it is asked for straight into the store, the
+// way a peer announces it to the tracker.
+type peerServiceMock struct {
+	peer.UnimplementedPeerServiceServer
+	host  string
+	store *peerstore.Store
+
+	mu       sync.Mutex
+	received []*peer.ReplicateFileRequest
+	tokens   []string
+}
+
+func (ps *peerServiceMock) ReplicateFile(ctx context.Context, in *peer.ReplicateFileRequest) (*peer.ReplicateFileResponse, error) {
+	ps.mu.Lock()
+	ps.received = append(ps.received, in)
+	ps.tokens = append(ps.tokens, metadata.ValueFromIncomingContext(ctx, replicator.TokenKey)...)
+	ps.mu.Unlock()
+
+	file := peerstore.FileMetadata{Name: in.File.Name, Size: in.File.Size, Checksum: in.File.Checksum}
+	seq, _, err := ps.store.Catalog(ps.host)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ps.store.ApplyChanges(ps.host, seq+1, []peerstore.FileMetadata{file}, nil); err != nil {
+		return nil, err
+	}
+	return &peer.ReplicateFileResponse{Success: true, Message: "OK"}, nil
+}
+
+func (ps *peerServiceMock) requests() []*peer.ReplicateFileRequest {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return slices.Clone(ps.received)
+}
+
+func (ps *peerServiceMock) hashes() []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return slices.Clone(ps.tokens)
+}
@@ -0,0 +1,100 @@
+// Package selection decides in which order peers are handed out for a download.
+package selection
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+)
+
+// Policy orders the peers able to serve a file, the best candidate first.
+type Policy interface {
+	Order(peers []peerstore.Peer)
+}
+
+// Parse returns the policy with the given name: random, least-loaded, lowest-latency
+// or weighted.
+func Parse(name string) (Policy, error) {
+	switch name {
+	case "random":
+		return Random{}, nil
+	case "least-loaded":
+		return LeastLoaded{}, nil
+	case "lowest-latency":
+		return LowestLatency{}, nil
+	case "weighted":
+		return Weighted{}, nil
+	default:
+		return nil, fmt.Errorf("unknown selection policy: %q", name)
+	}
+}
+
+// Random spreads downloads evenly, regardless of how busy peers are.
+type Random struct{}
+
+// Order implements the Policy.
+func (Random) Order(peers []peerstore.Peer) {
+	shuffle(peers)
+}
+
+// LeastLoaded prefers peers serving the fewest downloads, then the ones sending
+// the fewest bytes. Equally loaded peers are in random order.
+type LeastLoaded struct{}
+
+// Order implements the Policy.
+func (LeastLoaded) Order(peers []peerstore.Peer) {
+	shuffle(peers)
+	slices.SortStableFunc(peers, func(a, b peerstore.Peer) int {
+		return cmp.Or(
+			cmp.Compare(a.Load.ActiveStreams, b.Load.ActiveStreams),
+			cmp.Compare(a.Load.BytesPerSecond, b.Load.BytesPerSecond),
+		)
+	})
+}
+
+// LowestLatency prefers peers with the shortest probe round trip, peers that were
+// not probed yet come last. Peers with the same latency are in random order.
+type LowestLatency struct{}
+
+// Order implements the Policy.
+func (LowestLatency) Order(peers []peerstore.Peer) {
+	shuffle(peers)
+	slices.SortStableFunc(peers, func(a, b peerstore.Peer) int {
+		if (a.RTT == 0) != (b.RTT == 0) {
+			if a.RTT == 0 {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(a.RTT, b.RTT)
+	})
+}
+
+// Weighted picks peers at random with a chance inversely proportional to the
+// downloads they serve, so busy peers get less traffic without being starved.
+type Weighted struct{}
+
+// Order implements the Policy.
+func (Weighted) Order(peers []peerstore.Peer) {
+	//weighted sampling without replacement: every peer draws u^(1/weight) and the
+	//highest draws go first.
+	keys := make(map[string]float64, len(peers))
+	for _, p := range peers {
+		weight := 1 / float64(1+max(p.Load.ActiveStreams, 0))
+		keys[p.Host] = math.Pow(rand.Float64(), 1/weight)
+	}
+
+	slices.SortFunc(peers, func(a, b peerstore.Peer) int {
+		return cmp.Compare(keys[b.Host], keys[a.Host])
+	})
+}
+
+func shuffle(peers []peerstore.Peer) {
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+}
@@ -0,0 +1,140 @@
+package selection_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/selection"
+)
+
+func TestPolicies(t *testing.T) {
+	peers := []peerstore.Peer{
+		{Host: "busy", Load: peerstore.Load{ActiveStreams: 5}, RTT: 2 * time.Millisecond},
+		{Host: "idle", Load: peerstore.Load{ActiveStreams: 0, BytesPerSecond: 10}, RTT: 30 * time.Millisecond},
+		{Host: "unprobed", Load: peerstore.Load{ActiveStreams: 1}},
+		{Host: "quiet", Load: peerstore.Load{ActiveStreams: 0}, RTT: 10 * time.Millisecond},
+	}
+
+	tests := map[string][]string{
+		"least-loaded":   {"quiet", "idle", "unprobed", "busy"},
+		"lowest-latency": {"busy", "quiet", "idle", "unprobed"},
+	}
+
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, err := selection.Parse(name)
+			if err != nil {
+				t.Fatalf("expected to parse policy: %s", err)
+			}
+
+			ordered := slices.Clone(peers)
+			policy.Order(ordered)
+
+			if got := hosts(ordered); !slices.Equal(got, expected) {
+				t.Fatalf("order=%v, got %v", expected, got)
+			}
+		})
+	}
+
+	for _, name := range []string{"random", "weighted"} {
+		t.Run(name, func(t *testing.T) {
+			policy, err := selection.Parse(name)
+			if err != nil {
+				t.Fatalf("expected to parse policy: %s", err)
+			}
+
+			ordered := slices.Clone(peers)
+			policy.Order(ordered)
+
+			got := hosts(ordered)
+			slices.Sort(got)
+			expected := hosts(peers)
+			slices.Sort(expected)
+			if !slices.Equal(got, expected) {
+				t.Fatalf("expected every peer once, got %v", got)
+			}
+		})
+	}
+
+	if _, err := selection.Parse("fastest"); err == nil {
+		t.Fatal("expected an unknown policy to fail")
+	}
+}
+
+func TestWeightedFavorsIdlePeers(t *testing.T) {
+	peers := []peerstore.Peer{
+		{Host: "busy", Load: peerstore.Load{ActiveStreams: 9}},
+		{Host: "idle"},
+	}
+
+	first := map[string]int{}
+	for range 1000 {
+		ordered := slices.Clone(peers)
+		selection.Weighted{}.Order(ordered)
+		first[ordered[0].Host]++
+	}
+
+	//idle is ten times as likely to be first
+	if first["idle"] < 800 || first["busy"] == 0 {
+		t.Fatalf("expected idle to be picked first most of the time without starving busy, got %v", first)
+	}
+}
+
+func hosts(peers []peerstore.Peer) []string {
+	hosts := make([]string, len(peers))
+	for i, p := range peers {
+		hosts[i] = p.Host
+	}
+	return hosts
+}
+
+func TestByProximity(t *testing.T) {
+	rack := map[string]string{"region": "eu", "zone": "eu-1", "rack": "r1"}
+	zone := map[string]string{"region": "eu", "zone": "eu-1", "rack": "r2"}
+	region := map[string]string{"region": "eu", "zone": "eu-2", "rack": "r1"}
+	remote := map[string]string{"region": "us", "zone": "eu-1", "rack": "r1"}
+
+	tests := map[string]struct {
+		peers    []peerstore.Peer
+		labels   map[string]string
+		expected []string
+	}{
+		"closest first and remote dropped": {
+			peers: []peerstore.Peer{
+				{Host: "remote", Labels: remote},
+				{Host: "region", Labels: region},
+				{Host: "unlabeled"},
+				{Host: "rack", Labels: rack},
+				{Host: "zone", Labels: zone},
+			},
+			labels:   rack,
+			expected: []string{"rack", "zone", "region"},
+		},
+		"remote when nothing is local": {
+			peers: []peerstore.Peer{
+				{Host: "remote", Labels: remote},
+				{Host: "unlabeled"},
+			},
+			labels:   rack,
+			expected: []string{"remote", "unlabeled"},
+		},
+		"requester without labels": {
+			peers: []peerstore.Peer{
+				{Host: "remote", Labels: remote},
+				{Host: "rack", Labels: rack},
+			},
+			expected: []string{"remote", "rack"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := hosts(selection.ByProximity(test.peers, test.labels))
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("order=%v, got %v", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package selection
+
+import (
+	"slices"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+)
+
+// TopologyLabels are the labels describing where a peer runs, from the widest to the
+// narrowest. A peer is only as close as the first label that differs.
+var TopologyLabels = []string{"region", "zone", "rack"}
+
+// Proximity returns how many topology levels two peers share, zero means they are
+// remote to each other.
+func Proximity(a map[string]string, b map[string]string) int {
+	shared := 0
+	for _, key := range TopologyLabels {
+		if a[key] == "" || a[key] != b[key] {
+			break
+		}
+		shared++
+	}
+	return shared
+}
+
+// ByProximity orders peers from the closest to the requester to the farthest, keeping
+// the order of equally close peers. Remote peers are dropped as long as a local one
+// is left. Peers are kept as they are when the requester has no topology labels.
+func ByProximity(peers []peerstore.Peer, labels map[string]string) []peerstore.Peer {
+	if Proximity(labels, labels) == 0 {
+		return peers
+	}
+
+	slices.SortStableFunc(peers, func(a, b peerstore.Peer) int {
+		return Proximity(labels, b.Labels) - Proximity(labels, a.Labels)
+	})
+
+	if len(peers) > 0 && Proximity(labels, peers[0].Labels) > 0 {
+		peers = slices.DeleteFunc(peers, func(p peerstore.Peer) bool {
+			return Proximity(labels, p.Labels) == 0
+		})
+	}
+	return peers
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenSize is the number of random bytes of a peer token.
+const tokenSize = 32
+
+// AdminTokenKey is the gRPC metadata key operators send the admin token in.
+const AdminTokenKey = "admin-token"
+
+// newToken creates a random secret for a peer.
+func newToken() (string, error) {
+	buf := make([]byte, tokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("read random: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken returns what the store keeps of a token, so a leaked store or log does
+// not hand out the tokens themselves.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// authorize checks that the caller presenting token owns the host.
+func (s *Service) authorize(host string, token string) error {
+	hash, err := s.store.TokenHash(host)
+	if err != nil {
+		if errors.Is(err, peerstore.ErrPeerNotFound) {
+			//drained peers are told so, instead of registering again on their own.
+			if s.isDrained(host) {
+				return status.Errorf(codes.FailedPrecondition, "peer %s was drained", host)
+			}
+			return status.Errorf(codes.NotFound, "peer %s, not found", host)
+		}
+		return status.Error(codes.Internal, codes.Internal.String())
+	}
+
+	//peers without a token, like the ones registered before tokens existed, can only register again.
+	if hash == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(hashToken(token))) != 1 {
+		return status.Errorf(codes.PermissionDenied, "invalid token for peer %s", host)
+	}
+	return nil
+}
+
+// claim returns the token the caller keeps using for the host once registered. A host
+// that is still registered to an owner can only be claimed with its token, any other
+// host gets a new one.
+func (s *Service) claim(host string, token string) (string, error) {
+	hash, err := s.store.TokenHash(host)
+	if err != nil && !errors.Is(err, peerstore.ErrPeerNotFound) {
+		return "", status.Error(codes.Internal, codes.Internal.String())
+	}
+
+	if hash != "" {
+		if err := s.authorize(host, token); err != nil {
+			return "", err
+		}
+		return token, nil
+	}
+
+	token, err = newToken()
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "new token: %s", err)
+	}
+	return token, nil
+}
+
+// isAdmin reports whether the caller presented the admin token, trackers without one
+// have no admins.
+func (s *Service) isAdmin(ctx context.Context) bool {
+	if s.adminToken == "" {
+		return false
+	}
+
+	for _, token := range metadata.ValueFromIncomingContext(ctx, AdminTokenKey) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// isLocal reports whether the caller runs on the same machine as the tracker.
+func isLocal(ctx context.Context) bool {
+	ip := net.ParseIP(callerIP(ctx))
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListConflicts lists the file names reachable peers in the caller's namespaces keep
+// different contents under, ordered by name. Results are paged, next_page_token of a response continues the list.
+func (s *Service) ListConflicts(ctx context.Context, in *tracker.ListConflictsRequest) (*tracker.ListConflictsResponse, error) {
+	offset, err := decodePageToken(in.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+
+	conflicts := s.store.Conflicts(namespace.FromIncomingContext(ctx)...)
+
+	//page
+	size := pageSize(in.GetPageSize())
+	start := min(offset, len(conflicts))
+	end := min(start+size, len(conflicts))
+
+	buffConflicts := make([]*tracker.Conflict, 0, end-start)
+	for _, c := range conflicts[start:end] {
+		buffConflicts = append(buffConflicts, &tracker.Conflict{
+			FileName: c.Name,
+			Versions: toBufferSummaries(c.Versions),
+		})
+	}
+
+	resp := tracker.ListConflictsResponse{
+		Conflicts: buffConflicts,
+	}
+	if end < len(conflicts) {
+		resp.NextPageToken = encodePageToken(end)
+	}
+	return &resp, nil
+}
+
+// GetFileVersions returns the distinct contents the peers in the caller's namespaces keep
+// under a file name, remote peers included. It is not counted as a lookup of the file.
+func (s *Service) GetFileVersions(ctx context.Context, in *tracker.GetFileVersionsRequest) (*tracker.GetFileVersionsResponse, error) {
+	namespaces := namespace.FromIncomingContext(ctx)
+
+	found := s.store.GetPeersForFile(in.GetFileName(), namespaces...)
+	all := slices.Clone(found)
+	for _, p := range s.remotePeers(in.GetFileName(), "", found, namespaces) {
+		all = append(all, p.Peer)
+	}
+
+	resp := tracker.GetFileVersionsResponse{
+		Versions: toBufferSummaries(versions(all, "")),
+	}
+	return &resp, nil
+}
+
+// versions groups the peers holding a name by the content they keep under it, the
+// most replicated content first.
+func versions(peers []peerstore.Peer, requester string) []peerstore.FileSummary {
+	var versions []peerstore.FileSummary
+	byChecksum := make(map[string]int)
+	for _, peer := range peers {
+		if peer.Health == peerstore.Dead || peer.Draining || peer.Host == requester || len(peer.Files) == 0 {
+			continue
+		}
+
+		file := peer.Files[0]
+		idx, ok := byChecksum[file.Checksum]
+		if !ok {
+			idx = len(versions)
+			byChecksum[file.Checksum] = idx
+			versions = append(versions, peerstore.FileSummary{File: file})
+		}
+		versions[idx].Peers++
+	}
+
+	peerstore.SortVersions(versions)
+	return versions
+}
+
+func toBufferSummaries(summaries []peerstore.FileSummary) []*tracker.FileSummary {
+	buffSummaries := make([]*tracker.FileSummary, len(summaries))
+	for i, r := range summaries {
+		buffSummaries[i] = &tracker.FileSummary{
+			File: &tracker.File{
+				Name:     r.File.Name,
+				Size:     r.File.Size,
+				Checksum: r.File.Checksum,
+			},
+			PeerCount: int64(r.Peers),
+		}
+	}
+	return buffSummaries
+}
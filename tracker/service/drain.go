@@ -0,0 +1,196 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/selection"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DrainPeer takes a peer out of the network without losing files. The peer is no longer
+// handed out for downloads, every file no other peer of its namespaces serves is copied
+// to one, then the peer is unregistered. When a file can not be copied the peer goes
+// back to serving. Either the peer or an admin can drain it.
+func (s *Service) DrainPeer(in *tracker.DrainPeerRequest, stream grpc.ServerStreamingServer[tracker.DrainProgress]) error {
+	host := in.GetHost()
+	if !s.isAdmin(stream.Context()) {
+		if err := s.authorize(host, in.GetToken()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.store.SetDraining(host, true); err != nil {
+		return storeError(host, err)
+	}
+
+	drained := false
+	defer func() {
+		if !drained {
+			//peer may have left in the meantime, nothing to restore then.
+			_ = s.store.SetDraining(host, false)
+		}
+	}()
+
+	files, err := s.store.GetPeerByHost(host)
+	if err != nil {
+		return storeError(host, err)
+	}
+
+	namespaces := s.namespacesOf(host)
+	sole := s.soleCopies(host, namespaces, files)
+	progress := tracker.DrainProgress{TotalFiles: int64(len(sole))}
+	if err := stream.Send(&progress); err != nil {
+		return status.Errorf(codes.Internal, "send progress: %s", err)
+	}
+
+	for _, file := range sole {
+		target, err := s.copyAway(stream, host, namespaces, file)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "copy file %s: %s", file.Name, err)
+		}
+		log.Printf("drain peer[%s]: copied file[%s] to peer[%s]\n", host, file.Name, target)
+
+		progress.CopiedFiles++
+		progress.File = &tracker.File{Name: file.Name, Size: file.Size, Checksum: file.Checksum}
+		progress.Target = target
+		if err := stream.Send(&progress); err != nil {
+			return status.Errorf(codes.Internal, "send progress: %s", err)
+		}
+	}
+
+	//every file is served elsewhere now, the peer can leave.
+	if err := s.store.RemovePeerByHost(host); err != nil {
+		return storeError(host, err)
+	}
+	s.setDrained(host, true)
+	drained = true
+
+	progress.File = nil
+	progress.Target = ""
+	progress.Done = true
+	if err := stream.Send(&progress); err != nil {
+		return status.Errorf(codes.Internal, "send progress: %s", err)
+	}
+	return nil
+}
+
+// soleCopies returns the files of host no other reachable peer serves in one of its
+// namespaces, one per content and ordered by name.
+func (s *Service) soleCopies(host string, namespaces []string, files peerstore.Files) []peerstore.FileMetadata {
+	seen := make(map[string]struct{}, len(files))
+
+	var sole []peerstore.FileMetadata
+	for _, file := range files {
+		if _, ok := seen[file.Checksum]; ok {
+			continue
+		}
+		seen[file.Checksum] = struct{}{}
+
+		uncovered := slices.ContainsFunc(namespaces, func(ns string) bool {
+			return !slices.ContainsFunc(s.store.GetPeersForChecksum(file.Checksum, ns), func(p peerstore.Peer) bool {
+				return p.Host != host && p.Health != peerstore.Dead && !p.Draining
+			})
+		})
+		if uncovered {
+			sole = append(sole, file)
+		}
+	}
+
+	slices.SortFunc(sole, func(a, b peerstore.FileMetadata) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Checksum, b.Checksum))
+	})
+	return sole
+}
+
+// copyAway copies a file of the draining host to the least loaded healthy peer able to
+// take it, trying the next one on failure, and returns the peer that took it. Peers in
+// namespaces the host is not in can not take it, that would hand the file out to them,
+// and peers in the same namespaces as the host are tried first.
+func (s *Service) copyAway(stream grpc.ServerStreamingServer[tracker.DrainProgress], host string, namespaces []string, file peerstore.FileMetadata) (string, error) {
+	candidates := slices.DeleteFunc(s.store.GetAllPeers(), func(p peerstore.Peer) bool {
+		return p.Host == host || p.Health != peerstore.Healthy || p.Draining || !namespace.Within(p.Namespaces, namespaces)
+	})
+	selection.LeastLoaded{}.Order(candidates)
+	slices.SortStableFunc(candidates, func(a, b peerstore.Peer) int {
+		return cmp.Compare(len(b.Namespaces), len(a.Namespaces))
+	})
+
+	if len(candidates) == 0 {
+		return "", errors.New("no healthy peer to copy to")
+	}
+
+	var errs []error
+	for _, p := range candidates {
+		err := s.replicator.Copy(stream.Context(), file, []string{host}, p.Host)
+		if err == nil {
+			return p.Host, nil
+		}
+
+		//the caller went away, stop draining.
+		if stream.Context().Err() != nil {
+			return "", stream.Context().Err()
+		}
+		errs = append(errs, fmt.Errorf("peer %s: %w", p.Host, err))
+	}
+	return "", errors.Join(errs...)
+}
+
+// namespacesOf returns the namespaces of a registered peer.
+func (s *Service) namespacesOf(host string) []string {
+	for _, p := range s.store.GetAllPeers() {
+		if p.Host == host {
+			return p.Namespaces
+		}
+	}
+	return namespace.Normalize(nil)
+}
+
+func (s *Service) setDrained(host string, drained bool) {
+	s.drainedMu.Lock()
+	defer s.drainedMu.Unlock()
+
+	if drained {
+		s.drained[host] = time.Now()
+	} else {
+		delete(s.drained, host)
+	}
+}
+
+// pruneDrained forgets the hosts drained longer than ttl ago, their lease would have
+// expired by now so they are told they are not found like any other.
+func (s *Service) pruneDrained(ttl time.Duration) {
+	s.drainedMu.Lock()
+	defer s.drainedMu.Unlock()
+
+	for host, at := range s.drained {
+		if time.Since(at) > ttl {
+			delete(s.drained, host)
+		}
+	}
+}
+
+func (s *Service) isDrained(host string) bool {
+	s.drainedMu.Lock()
+	defer s.drainedMu.Unlock()
+
+	_, ok := s.drained[host]
+	return ok
+}
+
+// storeError maps an error of the store about host to a status.
+func storeError(host string, err error) error {
+	if errors.Is(err, peerstore.ErrPeerNotFound) {
+		return status.Errorf(codes.NotFound, "peer %s, not found", host)
+	}
+	return status.Error(codes.Internal, codes.Internal.String())
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WatchEvents streams the changes of the peers in the caller's namespaces until the
+// client goes away. A watcher that falls behind is resumed from its last event, as long
+// as it is still in history.
+func (s *Service) WatchEvents(in *tracker.WatchEventsRequest, stream grpc.ServerStreamingServer[tracker.Event]) error {
+	match, err := matcher(in.GetPattern(), in.GetMatchMode())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid pattern: %s", err)
+	}
+
+	namespaces := namespace.FromIncomingContext(stream.Context())
+
+	cursor := in.GetCursor()
+	for {
+		w, err := s.store.Watch(cursor)
+		if err != nil {
+			if errors.Is(err, peerstore.ErrCursorExpired) {
+				return status.Error(codes.OutOfRange, "cursor is no longer in history, list the peers again and watch from zero")
+			}
+			return status.Errorf(codes.Internal, "watch: %s", err)
+		}
+
+		err = s.sendEvents(w, in.GetHost(), namespaces, match, stream)
+		cursor = w.Cursor()
+		w.Close()
+
+		if !errors.Is(err, peerstore.ErrWatcherLagged) {
+			return err
+		}
+	}
+}
+
+// sendEvents sends the events of a watcher that pass the filters.
+func (s *Service) sendEvents(w *peerstore.Watcher, host string, namespaces []string, match func(string) bool, stream grpc.ServerStreamingServer[tracker.Event]) error {
+	for {
+		ev, err := w.Next(stream.Context())
+		if err != nil {
+			if errors.Is(err, peerstore.ErrWatcherLagged) {
+				return err
+			}
+			return status.FromContextError(err).Err()
+		}
+
+		if (host != "" && ev.Host != host) || !namespace.Shares(ev.Namespaces, namespaces) {
+			continue
+		}
+
+		isFileEvent := ev.Kind == peerstore.EventFileAnnounced || ev.Kind == peerstore.EventFileRemoved
+		if isFileEvent && match != nil && !match(ev.File.Name) {
+			continue
+		}
+
+		if err := stream.Send(toBufferEvent(ev)); err != nil {
+			return status.Errorf(codes.Internal, "send event: %s", err)
+		}
+	}
+}
+
+func toBufferEvent(ev peerstore.Event) *tracker.Event {
+	buffEvent := tracker.Event{
+		Seq:  ev.Seq,
+		Kind: toBufferEventKind(ev.Kind),
+		Host: ev.Host,
+		Time: timestamppb.New(ev.Time),
+	}
+
+	if ev.Kind == peerstore.EventFileAnnounced || ev.Kind == peerstore.EventFileRemoved {
+		buffEvent.File = &tracker.File{
+			Name:     ev.File.Name,
+			Size:     ev.File.Size,
+			Checksum: ev.File.Checksum,
+		}
+	}
+	return &buffEvent
+}
+
+func toBufferEventKind(kind peerstore.EventKind) tracker.EventKind {
+	switch kind {
+	case peerstore.EventPeerUnregistered:
+		return tracker.EventKind_EVENT_KIND_PEER_UNREGISTERED
+	case peerstore.EventFileAnnounced:
+		return tracker.EventKind_EVENT_KIND_FILE_ANNOUNCED
+	case peerstore.EventFileRemoved:
+		return tracker.EventKind_EVENT_KIND_FILE_REMOVED
+	default:
+		return tracker.EventKind_EVENT_KIND_PEER_REGISTERED
+	}
+}
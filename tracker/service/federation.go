@@ -0,0 +1,102 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/federation"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+)
+
+// GetCatalogSummary lists every content served by the reachable peers of this tracker
+// in the caller's namespaces, ordered by name and checksum. Peers known through
+// federated trackers are left out, siblings pull them from their own tracker.
+func (s *Service) GetCatalogSummary(ctx context.Context, in *tracker.GetCatalogSummaryRequest) (*tracker.GetCatalogSummaryResponse, error) {
+	type key struct {
+		name     string
+		checksum string
+	}
+
+	namespaces := namespace.FromIncomingContext(ctx)
+
+	byContent := make(map[key]*tracker.CatalogEntry)
+	for _, peer := range s.store.GetAllPeers() {
+		if peer.Health == peerstore.Dead || peer.Draining || !namespace.Shares(peer.Namespaces, namespaces) {
+			continue
+		}
+
+		for _, file := range peer.Files {
+			k := key{name: file.Name, checksum: file.Checksum}
+			entry, ok := byContent[k]
+			if !ok {
+				entry = &tracker.CatalogEntry{
+					File: &tracker.File{
+						Name:     file.Name,
+						Size:     file.Size,
+						Checksum: file.Checksum,
+					},
+				}
+				byContent[k] = entry
+			}
+			entry.Hosts = append(entry.Hosts, peer.Host)
+		}
+	}
+
+	entries := make([]*tracker.CatalogEntry, 0, len(byContent))
+	for _, entry := range byContent {
+		slices.Sort(entry.Hosts)
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b *tracker.CatalogEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.File.Name, b.File.Name),
+			cmp.Compare(a.File.Checksum, b.File.Checksum),
+		)
+	})
+
+	return &tracker.GetCatalogSummaryResponse{Entries: entries}, nil
+}
+
+// remotePeers returns the peers of federated trackers serving name, skipping the
+// requester and the hosts registered with this tracker. Trackers only pull the default
+// namespace of their siblings, other namespaces have no remote peers.
+func (s *Service) remotePeers(name string, requester string, local []peerstore.Peer, namespaces []string) []federation.Peer {
+	if s.federation == nil || !slices.Contains(namespaces, namespace.Default) {
+		return nil
+	}
+
+	return slices.DeleteFunc(s.federation.PeersForFile(name), func(p federation.Peer) bool {
+		return p.Host == requester || slices.ContainsFunc(local, func(l peerstore.Peer) bool {
+			return l.Host == p.Host
+		})
+	})
+}
+
+// selectRemote orders remote peers by the selection policy and keeps up to n of them.
+func (s *Service) selectRemote(remote []federation.Peer, n int) []*tracker.Peer {
+	if n <= 0 || len(remote) == 0 {
+		return nil
+	}
+
+	trackers := make(map[string]string, len(remote))
+	peers := make([]peerstore.Peer, len(remote))
+	for i, p := range remote {
+		trackers[p.Host] = p.Tracker
+		peers[i] = p.Peer
+	}
+
+	s.selection.Order(peers)
+	if len(peers) > n {
+		peers = peers[:n]
+	}
+
+	buffPeers := toBufferPeers(peers)
+	for _, buffPeer := range buffPeers {
+		buffPeer.Remote = true
+		buffPeer.Tracker = trackers[buffPeer.Host]
+	}
+	return buffPeers
+}
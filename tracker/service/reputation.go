@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// penalties weigh the reasons of a report, serving corrupted content is the worst.
+var penalties = map[tracker.ReportReason]float64{
+	tracker.ReportReason_REPORT_REASON_CHECKSUM_MISMATCH: 5,
+	tracker.ReportReason_REPORT_REASON_TIMEOUT:           1,
+	tracker.ReportReason_REPORT_REASON_REFUSED:           2,
+}
+
+// handoutTTL is how long a requester handed a peer out can get it banned by reporting it.
+const handoutTTL = time.Hour
+
+// handout is a peer handed out to a requester.
+type handout struct {
+	requester string
+	host      string
+}
+
+// ReportPeer records that a peer misbehaved. Reports of registered peers presenting
+// their token weigh in full and can get the peer banned once several peers made them,
+// as long as the prober reached the reporter and this tracker handed it the peer for the
+// file, in the last hour. Reporters behind the same IP address count once towards a ban.
+// Anonymous ones, like the ones of clients, weigh half and only move the peer back in
+// lookups, like the ones of other reporters. Every reporter, told apart by its host or
+// else its IP address, only has its last report counted.
+func (s *Service) ReportPeer(ctx context.Context, in *tracker.ReportPeerRequest) (*tracker.ReportPeerResponse, error) {
+	host := in.GetHost()
+	if host == "" {
+		return nil, status.Error(codes.InvalidArgument, "host is required")
+	}
+
+	penalty, ok := penalties[in.GetReason()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid reason %s", in.GetReason())
+	}
+
+	report := peerstore.Report{Reporter: in.GetReporter(), Penalty: penalty, Source: callerIP(ctx)}
+	if report.Reporter != "" {
+		if report.Reporter == host {
+			return nil, status.Error(codes.InvalidArgument, "peers can not report themselves")
+		}
+		if err := s.authorize(report.Reporter, in.GetToken()); err != nil {
+			return nil, err
+		}
+
+		//hosts registered just to report can not ban anyone.
+		if !s.reachable(report.Reporter) || !s.handedOut(report.Reporter, host, in.GetFileName()) {
+			report.Penalty /= 2
+			report.Anonymous = true
+		}
+	} else {
+		report.Reporter = "anonymous/" + callerIP(ctx)
+		report.Penalty /= 2
+		report.Anonymous = true
+	}
+
+	standing, err := s.store.ReportPeer(host, report, s.reputation)
+	if err != nil {
+		return nil, storeError(host, err)
+	}
+
+	banned := standing.Banned(time.Now())
+	if banned {
+		log.Printf("peer[%s] reported for %s, banned until %s\n", host, in.GetReason(), standing.BannedUntil.Format(time.RFC3339))
+	}
+
+	resp := tracker.ReportPeerResponse{
+		StatusCode: int64(codes.OK),
+		Message:    codes.OK.String(),
+		Penalty:    standing.Penalty,
+	}
+	if banned {
+		resp.BannedUntil = timestamppb.New(standing.BannedUntil)
+	}
+	return &resp, nil
+}
+
+// reachable reports whether the prober got an answer from host and it is still healthy,
+// peers not probed yet have no RTT.
+func (s *Service) reachable(host string) bool {
+	for _, p := range s.store.GetAllPeers() {
+		if p.Host == host {
+			return p.Health == peerstore.Healthy && p.RTT > 0
+		}
+	}
+	return false
+}
+
+// handOut remembers the peers handed out to a requester for the file name, or for the
+// names they keep the content under when empty.
+func (s *Service) handOut(requester string, peers []peerstore.Peer, name string) {
+	if requester == "" {
+		return
+	}
+
+	s.handoutsMu.Lock()
+	defer s.handoutsMu.Unlock()
+
+	now := time.Now()
+	for _, p := range peers {
+		h := handout{requester: requester, host: p.Host}
+		names, ok := s.handouts[h]
+		if !ok {
+			names = make(map[string]time.Time)
+			s.handouts[h] = names
+		}
+
+		if name != "" {
+			names[name] = now
+			continue
+		}
+		for _, f := range p.Files {
+			names[f.Name] = now
+		}
+	}
+}
+
+// handedOut reports whether host was handed out to requester for the file name, or for
+// any file when empty, in the last handoutTTL.
+func (s *Service) handedOut(requester string, host string, name string) bool {
+	s.handoutsMu.Lock()
+	defer s.handoutsMu.Unlock()
+
+	now := time.Now()
+	for n, at := range s.handouts[handout{requester: requester, host: host}] {
+		if (name == "" || n == name) && now.Sub(at) < handoutTTL {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneHandouts forgets the peers handed out longer than handoutTTL before now.
+func (s *Service) pruneHandouts(now time.Time) {
+	s.handoutsMu.Lock()
+	defer s.handoutsMu.Unlock()
+
+	for h, names := range s.handouts {
+		for name, at := range names {
+			if now.Sub(at) >= handoutTTL {
+				delete(names, name)
+			}
+		}
+		if len(names) == 0 {
+			delete(s.handouts, h)
+		}
+	}
+}
+
+// callerIP returns the IP address of the caller of a call, empty when unknown.
+func callerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// excludeBanned filters out the peers banned at t.
+func excludeBanned(peers []peerstore.Peer, t time.Time) []peerstore.Peer {
+	return slices.DeleteFunc(peers, func(p peerstore.Peer) bool {
+		return t.Before(p.BannedUntil)
+	})
+}
+
+// byPenalty moves the peers reported the most to the back, keeping the order of peers
+// with penalties within a point of each other.
+func byPenalty(peers []peerstore.Peer) {
+	slices.SortStableFunc(peers, func(a, b peerstore.Peer) int {
+		return int(math.Floor(a.Penalty)) - int(math.Floor(b.Penalty))
+	})
+}
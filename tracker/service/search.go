@@ -0,0 +1,138 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultPageSize is used when the caller does not ask for a page size.
+	defaultPageSize = 100
+	// maxPageSize caps a single page to keep responses well under the message limit.
+	maxPageSize = 1000
+)
+
+// SearchFiles finds the files in the caller's namespaces matching a name pattern and a
+// size range.
+// Results are paged, next_page_token of a response continues the search.
+func (s *Service) SearchFiles(ctx context.Context, in *tracker.SearchFilesRequest) (*tracker.SearchFilesResponse, error) {
+	match, err := matcher(in.GetPattern(), in.GetMatchMode())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "pattern: %s", err)
+	}
+
+	if in.GetMaxSize() > 0 && in.GetMinSize() > in.GetMaxSize() {
+		return nil, status.Error(codes.InvalidArgument, "min size is bigger than max size")
+	}
+
+	offset, err := decodePageToken(in.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+
+	q := peerstore.Query{
+		Match:      match,
+		MinSize:    in.GetMinSize(),
+		MaxSize:    in.GetMaxSize(),
+		Namespaces: namespace.FromIncomingContext(ctx),
+	}
+	results := s.store.SearchFiles(q)
+	sortSummaries(results, in.GetOrder())
+
+	//page
+	size := pageSize(in.GetPageSize())
+	start := min(offset, len(results))
+	end := min(start+size, len(results))
+
+	resp := tracker.SearchFilesResponse{
+		Files: toBufferSummaries(results[start:end]),
+	}
+	if end < len(results) {
+		resp.NextPageToken = encodePageToken(end)
+	}
+	return &resp, nil
+}
+
+// matcher returns the function matching file names for a pattern, an empty pattern
+// matches every name.
+func matcher(pattern string, mode tracker.MatchMode) (func(string) bool, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+
+	switch mode {
+	case tracker.MatchMode_MATCH_MODE_PREFIX:
+		return func(name string) bool { return strings.HasPrefix(name, pattern) }, nil
+	case tracker.MatchMode_MATCH_MODE_GLOB:
+		//surface bad patterns up front instead of on every name.
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, err
+		}
+		return func(name string) bool {
+			ok, _ := path.Match(pattern, name)
+			return ok
+		}, nil
+	default:
+		return func(name string) bool { return strings.Contains(name, pattern) }, nil
+	}
+}
+
+// sortSummaries orders results in place, ties are broken by name and checksum so pages
+// are stable between calls.
+func sortSummaries(results []peerstore.FileSummary, order tracker.SearchOrder) {
+	slices.SortFunc(results, func(a, b peerstore.FileSummary) int {
+		if order == tracker.SearchOrder_SEARCH_ORDER_AVAILABILITY {
+			if c := cmp.Compare(b.Peers, a.Peers); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(
+			cmp.Compare(a.File.Name, b.File.Name),
+			cmp.Compare(a.File.Checksum, b.File.Checksum),
+		)
+	})
+}
+
+func pageSize(requested int32) int {
+	switch {
+	case requested <= 0:
+		return defaultPageSize
+	case requested > maxPageSize:
+		return maxPageSize
+	default:
+		return int(requested)
+	}
+}
+
+// encodePageToken returns an opaque token pointing at the result at offset.
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return offset, nil
+}
@@ -2,28 +2,163 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"log"
+	"math"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/federation"
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/replicator"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/selection"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultLeaseTTL is how long a peer stays registered without heartbeats.
+	DefaultLeaseTTL = 30 * time.Second
+	// DefaultHeartbeatInterval is how often peers are asked to send heartbeats.
+	DefaultHeartbeatInterval = 10 * time.Second
+	// DefaultMaxPeers is how many peers are handed out for a single file.
+	DefaultMaxPeers = 10
+	// DefaultMaxFiles is how many files a peer can announce in a single call.
+	DefaultMaxFiles = 10000
 )
 
 // Service represents set of rpc calls related to tracker.
 type Service struct {
 	tracker.UnimplementedTrackerServiceServer
-	store *peerstore.Store
+	store             peerstore.Storer
+	leaseTTL          time.Duration
+	heartbeatInterval time.Duration
+	lookups           *lookups
+	selection         selection.Policy
+	maxPeers          int
+	maxFiles          int
+	reputation        peerstore.Reputation
+	replicator        *replicator.Replicator
+	federation        *federation.Federation
+	adminToken        string
+
+	// registerMu serializes registrations, so two callers can not both claim the same host.
+	registerMu sync.Mutex
+
+	// drainedMu guards drained, the hosts unregistered by a drain that did not register
+	// again yet, with when they were drained.
+	drainedMu sync.Mutex
+	drained   map[string]time.Time
+
+	// handoutsMu guards handouts, the file names every peer was handed out for to every
+	// requester and when. Only requesters handed a peer can get it banned.
+	handoutsMu sync.Mutex
+	handouts   map[handout]map[string]time.Time
 }
 
-func New(store *peerstore.Store) *Service {
+// Config represents all the settings required by the tracker service.
+type Config struct {
+	Store peerstore.Storer
+	// LeaseTTL is the duration a peer is kept without a heartbeat, defaults to DefaultLeaseTTL.
+	LeaseTTL time.Duration
+	// HeartbeatInterval is handed to peers on registration, defaults to DefaultHeartbeatInterval.
+	HeartbeatInterval time.Duration
+	// Selection orders the peers handed out for a file, defaults to selection.Random.
+	Selection selection.Policy
+	// MaxPeers caps the peers handed out for a file, defaults to DefaultMaxPeers.
+	MaxPeers int
+	// MaxFiles caps the files of a registration, an update or the added files of an
+	// announcement, defaults to DefaultMaxFiles.
+	MaxFiles int
+	// Reputation sets how reports against peers fade and when they ban a peer, defaults
+	// to peerstore.DefaultReputation.
+	Reputation peerstore.Reputation
+	// Replicator copies the files of draining peers, defaults to one with default settings.
+	Replicator *replicator.Replicator
+	// Federation hands out the peers of sibling trackers for files, optional.
+	Federation *federation.Federation
+	// AdminToken is the secret operators send in the AdminTokenKey metadata to import
+	// and export state. Without it no caller can import, and only callers on the same
+	// machine can export.
+	AdminToken string
+}
+
+// New creates a new tracker service.
+func New(conf *Config) *Service {
+	leaseTTL := conf.LeaseTTL
+	if leaseTTL <= 0 {
+		leaseTTL = DefaultLeaseTTL
+	}
+
+	heartbeatInterval := conf.HeartbeatInterval
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = DefaultHeartbeatInterval
+	}
+
+	policy := conf.Selection
+	if policy == nil {
+		policy = selection.Random{}
+	}
+
+	maxPeers := conf.MaxPeers
+	if maxPeers <= 0 {
+		maxPeers = DefaultMaxPeers
+	}
+
+	maxFiles := conf.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+
+	reputation := conf.Reputation
+	if reputation == (peerstore.Reputation{}) {
+		reputation = peerstore.DefaultReputation
+	}
+
+	r := conf.Replicator
+	if r == nil {
+		r = replicator.New(&replicator.Config{Store: conf.Store})
+	}
+
 	return &Service{
-		store: store,
+		store:             conf.Store,
+		leaseTTL:          leaseTTL,
+		heartbeatInterval: heartbeatInterval,
+		lookups:           newLookups(),
+		selection:         policy,
+		maxPeers:          maxPeers,
+		maxFiles:          maxFiles,
+		reputation:        reputation,
+		replicator:        r,
+		federation:        conf.Federation,
+		adminToken:        conf.AdminToken,
+		drained:           make(map[string]time.Time),
+		handouts:          make(map[handout]map[string]time.Time),
 	}
 }
 
-// RegisterPeer will add a new peer into store.
+// RegisterPeer will add a new peer into store and hand out the token its following
+// calls have to present. A host that is still registered can only be registered again
+// with its token. The peer joins the namespaces sent with the call.
 func (s *Service) RegisterPeer(ctx context.Context, in *tracker.RegisterPeerRequest) (*tracker.RegisterPeerResponse, error) {
+	if err := s.checkFiles(len(in.GetFiles())); err != nil {
+		return nil, err
+	}
+
+	s.registerMu.Lock()
+	defer s.registerMu.Unlock()
+
+	token, err := s.claim(in.GetHost(), in.GetToken())
+	if err != nil {
+		return nil, err
+	}
+
 	files := make([]peerstore.FileMetadata, len(in.Files))
 
 	for i, file := range in.Files {
@@ -35,14 +170,44 @@ func (s *Service) RegisterPeer(ctx context.Context, in *tracker.RegisterPeerRequ
 		files[i] = fileMeta
 	}
 
-	s.store.RegisterPeer(in.Host, files)
+	if err := s.store.RegisterPeer(in.Host, files, namespace.FromIncomingContext(ctx)...); err != nil {
+		return nil, status.Errorf(codes.Internal, "register peer: %s", err)
+	}
+
+	if err := s.store.SetCatalogSeq(in.Host, in.GetCatalogSeq()); err != nil {
+		return nil, status.Errorf(codes.Internal, "set catalog seq: %s", err)
+	}
+
+	if err := s.store.SetTokenHash(in.Host, hashToken(token)); err != nil {
+		return nil, status.Errorf(codes.Internal, "set token: %s", err)
+	}
 
-	return &tracker.RegisterPeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+	if err := s.store.SetLabels(in.Host, in.GetLabels()); err != nil {
+		return nil, status.Errorf(codes.Internal, "set labels: %s", err)
+	}
+	s.setDrained(in.Host, false)
+
+	return &tracker.RegisterPeerResponse{
+		StatusCode:               int64(codes.OK),
+		Message:                  codes.OK.String(),
+		LeaseTtlSeconds:          int64(s.leaseTTL / time.Second),
+		HeartbeatIntervalSeconds: int64(s.heartbeatInterval / time.Second),
+		Token:                    token,
+	}, nil
 }
 
 // UnRegisterPeer willl remove a peer from network.
 func (s *Service) UnRegisterPeer(ctx context.Context, in *tracker.UnRegisterPeerRequest) (*tracker.UnRegisterPeerResponse, error) {
+	if err := s.authorize(in.GetHost(), in.GetToken()); err != nil {
+		code := status.Code(err)
+		return &tracker.UnRegisterPeerResponse{
+			StatusCode: int64(code),
+			Message:    code.String(),
+		}, err
+	}
+
 	err := s.store.RemovePeerByHost(in.Host)
+	s.setDrained(in.Host, false)
 	if err != nil {
 		if errors.Is(err, peerstore.ErrPeerNotFound) {
 			return &tracker.UnRegisterPeerResponse{
@@ -63,50 +228,342 @@ func (s *Service) UnRegisterPeer(ctx context.Context, in *tracker.UnRegisterPeer
 	}, nil
 }
 
-// GetPeers returns all peers with their file metadata.
-func (s *Service) GetPeers(ctx context.Context, _ *tracker.GetPeersRequest) (*tracker.GetPeersResponse, error) {
-	peers := s.store.GetAllPeers()
+// GetPeers returns a page of the peers in the caller's namespaces with their file
+// metadata, ordered by host.
+func (s *Service) GetPeers(ctx context.Context, in *tracker.GetPeersRequest) (*tracker.GetPeersResponse, error) {
+	after, err := decodeHostToken(in.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
 
-	buffPeers := toBufferPeers(peers)
-	return &tracker.GetPeersResponse{
-		Peers: buffPeers,
-	}, nil
+	//callers not asking for pages get every peer at once, like before paging.
+	limit := pageSize(in.GetPageSize())
+	if in.GetPageSize() <= 0 {
+		limit = math.MaxInt
+	}
+	peers, more := s.store.GetPeersPage(after, limit, namespace.FromIncomingContext(ctx)...)
+
+	resp := tracker.GetPeersResponse{
+		Peers: toBufferPeers(excludeDead(slices.Clone(peers))),
+	}
+	if more {
+		resp.NextPageToken = encodeHostToken(peers[len(peers)-1].Host)
+	}
+	return &resp, nil
 }
 
-// GetPeersForFile will returns all peers that contain the requested file.
+// ListPeers streams every peer in the caller's namespaces with its file metadata,
+// ordered by host.
+func (s *Service) ListPeers(_ *tracker.ListPeersRequest, stream grpc.ServerStreamingServer[tracker.Peer]) error {
+	namespaces := namespace.FromIncomingContext(stream.Context())
+
+	var after string
+	for {
+		peers, more := s.store.GetPeersPage(after, defaultPageSize, namespaces...)
+
+		for _, peer := range toBufferPeers(excludeDead(slices.Clone(peers))) {
+			if err := stream.Send(peer); err != nil {
+				return status.Errorf(codes.Internal, "send peer: %s", err)
+			}
+		}
+
+		if !more {
+			return nil
+		}
+		after = peers[len(peers)-1].Host
+	}
+}
+
+// GetPeersForFile will returns the peers in the caller's namespaces that contain the
+// requested file, best candidates first according to the selection policy. Peers may
+// keep different contents under the same name, versions tells them apart.
 func (s *Service) GetPeersForFile(ctx context.Context, in *tracker.GetPeersForFileRequest) (*tracker.GetPeersResponse, error) {
+	namespaces := namespace.FromIncomingContext(ctx)
+	s.lookups.inc(namespaces, in.GetFileName())
+
+	found := s.store.GetPeersForFile(in.GetFileName(), namespaces...)
+	remote := s.remotePeers(in.GetFileName(), in.GetRequester(), found, namespaces)
+
+	//remote peers count for the versions, downloads may pick their content.
+	all := slices.Clone(found)
+	for _, p := range remote {
+		all = append(all, p.Peer)
+	}
+	versions := versions(all, in.GetRequester())
+
+	labels := s.requesterLabels(in.GetRequester(), in.GetLabels())
+	peers := s.selectPeers(found, in.GetRequester(), labels, in.GetLimit())
+	s.handOut(in.GetRequester(), peers, in.GetFileName())
 
-	peers := s.store.GetPeersForFile(in.GetFileName())
+	//local peers come first, remote ones fill the rest.
 	buffPeers := toBufferPeers(peers)
+	buffPeers = append(buffPeers, s.selectRemote(remote, s.peersLimit(in.GetLimit())-len(peers))...)
 
 	resp := tracker.GetPeersResponse{
-		Peers: buffPeers,
+		Peers:    buffPeers,
+		Versions: toBufferSummaries(versions),
+	}
+	return &resp, nil
+}
+
+// GetPeersForChecksum will return all peers in the caller's namespaces that contain the
+// exact content matching the checksum.
+func (s *Service) GetPeersForChecksum(ctx context.Context, in *tracker.GetPeersForChecksumRequest) (*tracker.GetPeersResponse, error) {
+	if in.GetChecksum() == "" {
+		return nil, status.Error(codes.InvalidArgument, "checksum is required")
+	}
+
+	labels := s.requesterLabels(in.GetRequester(), in.GetLabels())
+	found := s.store.GetPeersForChecksum(in.GetChecksum(), namespace.FromIncomingContext(ctx)...)
+	peers := s.selectPeers(found, in.GetRequester(), labels, in.GetLimit())
+	s.handOut(in.GetRequester(), peers, "")
+
+	resp := tracker.GetPeersResponse{
+		Peers: toBufferPeers(peers),
 	}
 	return &resp, nil
 }
 
 // UpdatePeer will update the files related to a peer.
 func (s *Service) UpdatePeer(ctx context.Context, in *tracker.UpdatePeerRequest) (*tracker.UpdatePeerResponse, error) {
-	//get the peer
-	_, err := s.store.GetPeerByHost(in.GetHost())
+	if err := s.authorize(in.GetHost(), in.GetToken()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkFiles(len(in.GetFiles())); err != nil {
+		return nil, err
+	}
+
+	files := make([]peerstore.FileMetadata, len(in.GetFiles()))
+	for i, f := range in.GetFiles() {
+		files[i] = peerstore.FileMetadata{
+			Name:     f.GetName(),
+			Size:     f.GetSize(),
+			Checksum: f.GetChecksum(),
+		}
+	}
+	if err := s.store.UpdatePeer(in.GetHost(), files); err != nil {
+		return nil, status.Errorf(codes.Internal, "update peer: %s", err)
+	}
+
+	if err := s.store.SetCatalogSeq(in.GetHost(), in.GetCatalogSeq()); err != nil {
+		return nil, status.Errorf(codes.Internal, "set catalog seq: %s", err)
+	}
+	return &tracker.UpdatePeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+}
+
+// Heartbeat renews the lease of a peer, peers that are not registered get a NotFound
+// and are expected to register again.
+func (s *Service) Heartbeat(ctx context.Context, in *tracker.HeartbeatRequest) (*tracker.HeartbeatResponse, error) {
+	if err := s.authorize(in.GetHost(), in.GetToken()); err != nil {
+		return nil, err
+	}
+
+	err := s.store.RenewLease(in.GetHost())
 	if err != nil {
 		if errors.Is(err, peerstore.ErrPeerNotFound) {
 			return nil, status.Errorf(codes.NotFound, "peer %s, not found", in.GetHost())
 		}
-		//internal
 		return nil, status.Error(codes.Internal, codes.Internal.String())
 	}
 
-	files := make([]peerstore.FileMetadata, len(in.GetFiles()))
-	for i, f := range in.GetFiles() {
-		files[i] = peerstore.FileMetadata{
+	if load := in.GetLoad(); load != nil {
+		err := s.store.RecordLoad(in.GetHost(), peerstore.Load{
+			ActiveStreams:  load.GetActiveStreams(),
+			BytesPerSecond: load.GetBytesPerSecond(),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, codes.Internal.String())
+		}
+	}
+
+	resp := tracker.HeartbeatResponse{
+		StatusCode:      int64(codes.OK),
+		Message:         codes.OK.String(),
+		LeaseTtlSeconds: int64(s.leaseTTL / time.Second),
+	}
+
+	//peers that send their catalog digest get told when the tracker's copy drifted.
+	if in.GetCatalogDigest() != "" {
+		seq, digest, err := s.store.Catalog(in.GetHost())
+		if err != nil {
+			return nil, status.Error(codes.Internal, codes.Internal.String())
+		}
+		resp.ResyncRequired = seq != in.GetCatalogSeq() || digest != in.GetCatalogDigest()
+	}
+
+	return &resp, nil
+}
+
+// AnnounceChanges applies the files a peer added or removed. Whenever a change is
+// missing or the resulting catalogs differ, the peer is asked to send its catalog whole.
+func (s *Service) AnnounceChanges(ctx context.Context, in *tracker.AnnounceChangesRequest) (*tracker.AnnounceChangesResponse, error) {
+	if err := s.authorize(in.GetHost(), in.GetToken()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkFiles(len(in.GetAdded())); err != nil {
+		return nil, err
+	}
+
+	added := make([]peerstore.FileMetadata, len(in.GetAdded()))
+	for i, f := range in.GetAdded() {
+		added[i] = peerstore.FileMetadata{
 			Name:     f.GetName(),
 			Size:     f.GetSize(),
 			Checksum: f.GetChecksum(),
 		}
 	}
-	s.store.UpdatePeer(in.GetHost(), files)
-	return &tracker.UpdatePeerResponse{StatusCode: int64(codes.OK), Message: codes.OK.String()}, nil
+
+	digest, err := s.store.ApplyChanges(in.GetHost(), in.GetCatalogSeq(), added, in.GetRemoved())
+	if err != nil {
+		switch {
+		case errors.Is(err, peerstore.ErrPeerNotFound):
+			return nil, status.Errorf(codes.NotFound, "peer %s, not found", in.GetHost())
+		case errors.Is(err, peerstore.ErrSequenceGap):
+			return s.resync(in.GetHost())
+		default:
+			return nil, status.Errorf(codes.Internal, "apply changes: %s", err)
+		}
+	}
+
+	if in.GetCatalogDigest() != "" && digest != in.GetCatalogDigest() {
+		log.Printf("peer[%s] catalog drifted at seq %d\n", in.GetHost(), in.GetCatalogSeq())
+		return s.resync(in.GetHost())
+	}
+
+	return &tracker.AnnounceChangesResponse{
+		StatusCode: int64(codes.OK),
+		Message:    codes.OK.String(),
+		CatalogSeq: in.GetCatalogSeq(),
+	}, nil
+}
+
+// checkFiles rejects announcements of more files than a peer can send at once. They are
+// not rejected with ResourceExhausted, peers take it for rate limiting and retry as is.
+func (s *Service) checkFiles(n int) error {
+	if n > s.maxFiles {
+		return status.Errorf(codes.InvalidArgument, "%d files announced, at most %d are accepted", n, s.maxFiles)
+	}
+	return nil
+}
+
+// resync tells a peer that its catalog must be sent whole.
+func (s *Service) resync(host string) (*tracker.AnnounceChangesResponse, error) {
+	seq, _, err := s.store.Catalog(host)
+	if err != nil {
+		return nil, status.Error(codes.Internal, codes.Internal.String())
+	}
+
+	return &tracker.AnnounceChangesResponse{
+		StatusCode:     int64(codes.FailedPrecondition),
+		Message:        "catalog out of sync, resync required",
+		ResyncRequired: true,
+		CatalogSeq:     seq,
+	}, nil
+}
+
+// RunReaper evicts peers with expired leases every interval until ctx is done, intervals
+// that are not positive fall back to the heartbeat interval of the service.
+func (s *Service) RunReaper(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = s.heartbeatInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := s.store.RemoveExpired(s.leaseTTL)
+			if err != nil {
+				log.Printf("remove expired peers: %s\n", err)
+			}
+			for _, host := range expired {
+				log.Printf("peer[%s] lease expired, removed from tracker\n", host)
+			}
+			s.pruneDrained(s.leaseTTL)
+			s.pruneHandouts(time.Now())
+		}
+	}
+}
+
+// encodeHostToken returns an opaque token continuing right after host.
+func encodeHostToken(host string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(host))
+}
+
+func decodeHostToken(token string) (string, error) {
+	host, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", err
+	}
+	return string(host), nil
+}
+
+// selectPeers hands out the peers to download from: the requester, dead, draining and
+// banned peers are left out, the rest is ordered by the selection policy, then by
+// proximity to the requester, then peers reported the most go last, and capped.
+func (s *Service) selectPeers(peers []peerstore.Peer, requester string, labels map[string]string, limit int32) []peerstore.Peer {
+	peers = slices.DeleteFunc(excludeDead(peers), func(p peerstore.Peer) bool {
+		return p.Host == requester || p.Draining
+	})
+	peers = excludeBanned(peers, time.Now())
+
+	s.selection.Order(peers)
+	peers = selection.ByProximity(peers, labels)
+	byPenalty(peers)
+
+	n := s.peersLimit(limit)
+	if len(peers) > n {
+		peers = peers[:n]
+	}
+	return peers
+}
+
+// peersLimit returns the number of peers handed out for a request asking for limit.
+func (s *Service) peersLimit(limit int32) int {
+	if limit > 0 && int(limit) < s.maxPeers {
+		return int(limit)
+	}
+	return s.maxPeers
+}
+
+// requesterLabels returns the topology labels of a requester, the ones it sent or
+// else the ones it registered with.
+func (s *Service) requesterLabels(requester string, labels map[string]string) map[string]string {
+	if len(labels) > 0 || requester == "" {
+		return labels
+	}
+
+	//unknown requesters, like clients, get no locality.
+	registered, _ := s.store.Labels(requester)
+	return registered
+}
+
+// excludeDead filters out peers that are registered but unreachable.
+func excludeDead(peers []peerstore.Peer) []peerstore.Peer {
+	alive := peers[:0]
+	for _, peer := range peers {
+		if peer.Health != peerstore.Dead {
+			alive = append(alive, peer)
+		}
+	}
+	return alive
+}
+
+func toBufferHealth(health peerstore.Health) tracker.PeerHealth {
+	switch health {
+	case peerstore.Suspect:
+		return tracker.PeerHealth_PEER_HEALTH_SUSPECT
+	case peerstore.Dead:
+		return tracker.PeerHealth_PEER_HEALTH_DEAD
+	default:
+		return tracker.PeerHealth_PEER_HEALTH_HEALTHY
+	}
 }
 
 func toBufferPeers(peers []peerstore.Peer) []*tracker.Peer {
@@ -114,6 +571,21 @@ func toBufferPeers(peers []peerstore.Peer) []*tracker.Peer {
 	for i, peer := range peers {
 		buffPeer := tracker.Peer{}
 		buffPeer.Host = peer.Host
+		buffPeer.Health = toBufferHealth(peer.Health)
+		buffPeer.RttMillis = peer.RTT.Milliseconds()
+		buffPeer.Labels = peer.Labels
+		buffPeer.Draining = peer.Draining
+		buffPeer.Load = &tracker.Load{
+			ActiveStreams:  peer.Load.ActiveStreams,
+			BytesPerSecond: peer.Load.BytesPerSecond,
+		}
+		if !peer.LastSeen.IsZero() {
+			buffPeer.LastSeen = timestamppb.New(peer.LastSeen)
+		}
+		buffPeer.Penalty = peer.Penalty
+		if time.Now().Before(peer.BannedUntil) {
+			buffPeer.BannedUntil = timestamppb.New(peer.BannedUntil)
+		}
 
 		buffFiles := make([]*tracker.File, len(peer.Files))
 		for i, file := range peer.Files {
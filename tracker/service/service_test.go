@@ -2,20 +2,31 @@ package service_test
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"reflect"
 	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/peer"
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/selection"
 	"github.com/hamidoujand/P2P-file-sharing-network/tracker/service"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcpeer "google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestRegisterPeer(t *testing.T) {
 	store := peerstore.New()
-	service := service.New(store)
+	service := service.New(&service.Config{Store: store})
 
 	in := tracker.RegisterPeerRequest{
 		Host: "127.0.0.1:50051",
@@ -40,7 +51,7 @@ func TestRegisterPeer(t *testing.T) {
 
 func TestUnRegisterPeer(t *testing.T) {
 	store := peerstore.New()
-	service := service.New(store)
+	service := service.New(&service.Config{Store: store})
 
 	host := "127.0.0.1:50051"
 	in := tracker.RegisterPeerRequest{
@@ -64,7 +75,8 @@ func TestUnRegisterPeer(t *testing.T) {
 	}
 
 	input := tracker.UnRegisterPeerRequest{
-		Host: host,
+		Host:  host,
+		Token: resp.Token,
 	}
 
 	response, err := service.UnRegisterPeer(context.Background(), &input)
@@ -110,7 +122,7 @@ func TestGetPeers(t *testing.T) {
 
 	//seed
 	store := peerstore.New()
-	service := service.New(store)
+	service := service.New(&service.Config{Store: store})
 
 	for host, file := range peers {
 		in := tracker.RegisterPeerRequest{
@@ -162,7 +174,7 @@ func TestGetPeersForFile(t *testing.T) {
 	}
 
 	store := peerstore.New()
-	service := service.New(store)
+	service := service.New(&service.Config{Store: store})
 	p1 := tracker.RegisterPeerRequest{
 		Host:  "127.0.0.1:9000",
 		Files: []*tracker.File{&meta},
@@ -209,7 +221,7 @@ func TestUpdatePeer(t *testing.T) {
 	}
 
 	store := peerstore.New()
-	service := service.New(store)
+	service := service.New(&service.Config{Store: store})
 
 	host := "127.0.0.1:9000"
 
@@ -218,7 +230,7 @@ func TestUpdatePeer(t *testing.T) {
 		Files: []*tracker.File{&meta},
 	}
 
-	_, err := service.RegisterPeer(context.Background(), &p)
+	registered, err := service.RegisterPeer(context.Background(), &p)
 	if err != nil {
 		t.Fatalf("expected to register peer %s", p.Host)
 	}
@@ -232,6 +244,7 @@ func TestUpdatePeer(t *testing.T) {
 	in := &tracker.UpdatePeerRequest{
 		Host:  host,
 		Files: []*tracker.File{&meta, &meta2},
+		Token: registered.Token,
 	}
 
 	resp, err := service.UpdatePeer(context.Background(), in)
@@ -263,3 +276,1543 @@ func TestUpdatePeer(t *testing.T) {
 		}
 	}
 }
+
+func TestHeartbeat(t *testing.T) {
+	store := peerstore.New()
+	conf := service.Config{
+		Store:             store,
+		LeaseTTL:          20 * time.Second,
+		HeartbeatInterval: 5 * time.Second,
+	}
+	service := service.New(&conf)
+
+	host := "127.0.0.1:9000"
+	resp, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host})
+	if err != nil {
+		t.Fatalf("expected to register peer %s: %s", host, err)
+	}
+
+	if resp.LeaseTtlSeconds != 20 {
+		t.Errorf("lease=%d, got %d", 20, resp.LeaseTtlSeconds)
+	}
+
+	if resp.HeartbeatIntervalSeconds != 5 {
+		t.Errorf("interval=%d, got %d", 5, resp.HeartbeatIntervalSeconds)
+	}
+
+	hb, err := service.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: host, Token: resp.Token})
+	if err != nil {
+		t.Fatalf("expected heartbeat to succeed: %s", err)
+	}
+
+	if hb.StatusCode != int64(codes.OK) {
+		t.Fatalf("code=%d, got %d", codes.OK, hb.StatusCode)
+	}
+
+	//unknown peers must register again
+	_, err = service.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: "0.0.0.0:9000"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("status=%d, got %d", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestRunReaper(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store, LeaseTTL: time.Millisecond, HeartbeatInterval: 10 * time.Millisecond})
+
+	host := "127.0.0.1:9000"
+	if _, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host}); err != nil {
+		t.Fatalf("expected to register peer %s: %s", host, err)
+	}
+
+	//intervals that are not positive fall back to the heartbeat interval.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go service.RunReaper(ctx, 0)
+
+	deadline := time.Now().Add(time.Second)
+	for len(store.Hosts()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected peer %s to be reaped", host)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGetPeersExcludesDead(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	meta := tracker.File{Name: "file.txt", Size: 10, Checksum: "some-checksum"}
+	hosts := []string{"127.0.0.1:9000", "127.0.0.1:8000"}
+	for _, host := range hosts {
+		in := tracker.RegisterPeerRequest{Host: host, Files: []*tracker.File{&meta}}
+		if _, err := service.RegisterPeer(context.Background(), &in); err != nil {
+			t.Fatalf("expected to register peer %s", host)
+		}
+	}
+
+	//second peer stops answering probes
+	dead := peerstore.Thresholds{SuspectAfter: 1, DeadAfter: 1}
+	if _, err := store.RecordProbeFailure(hosts[1], dead); err != nil {
+		t.Fatalf("expected to record probe failure: %s", err)
+	}
+
+	all, err := service.GetPeers(context.Background(), &tracker.GetPeersRequest{})
+	if err != nil {
+		t.Fatalf("expected to get all peers: %s", err)
+	}
+
+	forFile, err := service.GetPeersForFile(context.Background(), &tracker.GetPeersForFileRequest{FileName: meta.Name})
+	if err != nil {
+		t.Fatalf("expected to get peers for file: %s", err)
+	}
+
+	for _, resp := range []*tracker.GetPeersResponse{all, forFile} {
+		if len(resp.Peers) != 1 {
+			t.Fatalf("length=%d, got %d", 1, len(resp.Peers))
+		}
+
+		if resp.Peers[0].Host != hosts[0] {
+			t.Errorf("host=%s, got %s", hosts[0], resp.Peers[0].Host)
+		}
+
+		if resp.Peers[0].Health != tracker.PeerHealth_PEER_HEALTH_HEALTHY {
+			t.Errorf("health=%s, got %s", tracker.PeerHealth_PEER_HEALTH_HEALTHY, resp.Peers[0].Health)
+		}
+
+		if resp.Peers[0].LastSeen == nil {
+			t.Error("expected last seen to be set")
+		}
+	}
+}
+
+func TestGetPeersForChecksum(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	requests := []*tracker.RegisterPeerRequest{
+		{Host: "127.0.0.1:9000", Files: []*tracker.File{{Name: "report.pdf", Size: 10, Checksum: "v1"}}},
+		{Host: "127.0.0.1:8000", Files: []*tracker.File{{Name: "report-copy.pdf", Size: 10, Checksum: "v1"}}},
+		{Host: "127.0.0.1:7000", Files: []*tracker.File{{Name: "report.pdf", Size: 12, Checksum: "v2"}}},
+	}
+	for _, in := range requests {
+		if _, err := service.RegisterPeer(context.Background(), in); err != nil {
+			t.Fatalf("expected to register peer %s", in.Host)
+		}
+	}
+
+	resp, err := service.GetPeersForChecksum(context.Background(), &tracker.GetPeersForChecksumRequest{Checksum: "v1"})
+	if err != nil {
+		t.Fatalf("expected to get peers for checksum: %s", err)
+	}
+
+	if len(resp.Peers) != 2 {
+		t.Fatalf("length=%d, got %d", 2, len(resp.Peers))
+	}
+
+	names := make(map[string]string)
+	for _, p := range resp.Peers {
+		if len(p.Files) != 1 || p.Files[0].Checksum != "v1" {
+			t.Fatalf("expected peer %s to only list the v1 content, got %v", p.Host, p.Files)
+		}
+		names[p.Host] = p.Files[0].Name
+	}
+
+	if names["127.0.0.1:8000"] != "report-copy.pdf" {
+		t.Errorf("name=%s, got %s", "report-copy.pdf", names["127.0.0.1:8000"])
+	}
+
+	_, err = service.GetPeersForChecksum(context.Background(), &tracker.GetPeersForChecksumRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("status=%s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestAnnounceChanges(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	host := "127.0.0.1:9000"
+	file1 := &tracker.File{Name: "file1.txt", Size: 10, Checksum: "hash-1"}
+	file2 := &tracker.File{Name: "file2.txt", Size: 20, Checksum: "hash-2"}
+
+	in := tracker.RegisterPeerRequest{Host: host, Files: []*tracker.File{file1}, CatalogSeq: 1}
+	registered, err := service.RegisterPeer(context.Background(), &in)
+	if err != nil {
+		t.Fatalf("expected to register peer %s", host)
+	}
+	token := registered.Token
+
+	//digest the peer would compute after adding file2
+	expected := peerstore.New()
+	expected.RegisterPeer(host, []peerstore.FileMetadata{
+		{Name: file1.Name, Size: file1.Size, Checksum: file1.Checksum},
+		{Name: file2.Name, Size: file2.Size, Checksum: file2.Checksum},
+	})
+	_, digest, _ := expected.Catalog(host)
+
+	announce := tracker.AnnounceChangesRequest{
+		Host:          host,
+		Added:         []*tracker.File{file2},
+		CatalogSeq:    2,
+		CatalogDigest: digest,
+		Token:         token,
+	}
+	resp, err := service.AnnounceChanges(context.Background(), &announce)
+	if err != nil {
+		t.Fatalf("expected to announce changes: %s", err)
+	}
+
+	if resp.ResyncRequired {
+		t.Fatal("expected changes to apply without a resync")
+	}
+
+	files, err := store.GetPeerByHost(host)
+	if err != nil {
+		t.Fatalf("expected to fetch peer's files: %s", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("length=%d, got %d", 2, len(files))
+	}
+
+	//heartbeat with the same catalog does not need a resync
+	hb, err := service.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: host, CatalogSeq: 2, CatalogDigest: digest, Token: token})
+	if err != nil {
+		t.Fatalf("expected heartbeat to succeed: %s", err)
+	}
+	if hb.ResyncRequired {
+		t.Fatal("expected catalogs to be in sync")
+	}
+
+	//a missed change
+	announce = tracker.AnnounceChangesRequest{Host: host, Removed: []string{file1.Name}, CatalogSeq: 4, Token: token}
+	resp, err = service.AnnounceChanges(context.Background(), &announce)
+	if err != nil {
+		t.Fatalf("expected to announce changes: %s", err)
+	}
+
+	if !resp.ResyncRequired || resp.CatalogSeq != 2 {
+		t.Fatalf("expected a resync from seq 2, got resync=%t seq=%d", resp.ResyncRequired, resp.CatalogSeq)
+	}
+
+	//a change that leaves both sides with different catalogs
+	announce = tracker.AnnounceChangesRequest{Host: host, Removed: []string{file1.Name}, CatalogSeq: 3, CatalogDigest: digest, Token: token}
+	resp, err = service.AnnounceChanges(context.Background(), &announce)
+	if err != nil {
+		t.Fatalf("expected to announce changes: %s", err)
+	}
+
+	if !resp.ResyncRequired {
+		t.Fatal("expected a digest mismatch to require a resync")
+	}
+
+	hb, err = service.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: host, CatalogSeq: 3, CatalogDigest: digest, Token: token})
+	if err != nil {
+		t.Fatalf("expected heartbeat to succeed: %s", err)
+	}
+	if !hb.ResyncRequired {
+		t.Fatal("expected heartbeat to detect the drift")
+	}
+
+	_, err = service.AnnounceChanges(context.Background(), &tracker.AnnounceChangesRequest{Host: "0.0.0.0:9000", CatalogSeq: 1})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("status=%s, got %s", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestSearchFiles(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	report := &tracker.File{Name: "report.pdf", Size: 100, Checksum: "report-v1"}
+	reportV2 := &tracker.File{Name: "report.pdf", Size: 120, Checksum: "report-v2"}
+	notes := &tracker.File{Name: "notes.txt", Size: 10, Checksum: "notes"}
+	slides := &tracker.File{Name: "report-slides.pdf", Size: 5000, Checksum: "slides"}
+
+	requests := []*tracker.RegisterPeerRequest{
+		{Host: "127.0.0.1:9000", Files: []*tracker.File{report, notes, slides}},
+		{Host: "127.0.0.1:8000", Files: []*tracker.File{report, notes}},
+		{Host: "127.0.0.1:7000", Files: []*tracker.File{reportV2, notes}},
+	}
+	for _, in := range requests {
+		if _, err := service.RegisterPeer(context.Background(), in); err != nil {
+			t.Fatalf("expected to register peer %s", in.Host)
+		}
+	}
+
+	tests := map[string]struct {
+		in       *tracker.SearchFilesRequest
+		expected []string
+	}{
+		"substring": {
+			in:       &tracker.SearchFilesRequest{Pattern: "port"},
+			expected: []string{"report-slides.pdf:slides", "report.pdf:report-v1", "report.pdf:report-v2"},
+		},
+		"prefix": {
+			in:       &tracker.SearchFilesRequest{Pattern: "report.", MatchMode: tracker.MatchMode_MATCH_MODE_PREFIX},
+			expected: []string{"report.pdf:report-v1", "report.pdf:report-v2"},
+		},
+		"glob": {
+			in:       &tracker.SearchFilesRequest{Pattern: "*.pdf", MatchMode: tracker.MatchMode_MATCH_MODE_GLOB},
+			expected: []string{"report-slides.pdf:slides", "report.pdf:report-v1", "report.pdf:report-v2"},
+		},
+		"size range": {
+			in:       &tracker.SearchFilesRequest{MinSize: 50, MaxSize: 110},
+			expected: []string{"report.pdf:report-v1"},
+		},
+		"availability": {
+			in:       &tracker.SearchFilesRequest{Order: tracker.SearchOrder_SEARCH_ORDER_AVAILABILITY},
+			expected: []string{"notes.txt:notes", "report.pdf:report-v1", "report-slides.pdf:slides", "report.pdf:report-v2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := service.SearchFiles(context.Background(), test.in)
+			if err != nil {
+				t.Fatalf("expected to search files: %s", err)
+			}
+
+			got := make([]string, len(resp.Files))
+			for i, f := range resp.Files {
+				got[i] = f.File.Name + ":" + f.File.Checksum
+			}
+
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("files=%v, got %v", test.expected, got)
+			}
+		})
+	}
+
+	//availability is counted per content
+	resp, err := service.SearchFiles(context.Background(), &tracker.SearchFilesRequest{Pattern: "notes.txt"})
+	if err != nil {
+		t.Fatalf("expected to search files: %s", err)
+	}
+	if resp.Files[0].PeerCount != 3 {
+		t.Fatalf("peers=%d, got %d", 3, resp.Files[0].PeerCount)
+	}
+
+	//page through everything two at a time
+	var pages [][]string
+	in := &tracker.SearchFilesRequest{PageSize: 2}
+	for {
+		resp, err := service.SearchFiles(context.Background(), in)
+		if err != nil {
+			t.Fatalf("expected to search files: %s", err)
+		}
+
+		var page []string
+		for _, f := range resp.Files {
+			page = append(page, f.File.Checksum)
+		}
+		pages = append(pages, page)
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		in.PageToken = resp.NextPageToken
+	}
+
+	expectedPages := [][]string{{"notes", "slides"}, {"report-v1", "report-v2"}}
+	if !reflect.DeepEqual(pages, expectedPages) {
+		t.Fatalf("pages=%v, got %v", expectedPages, pages)
+	}
+
+	//bad input
+	bad := []*tracker.SearchFilesRequest{
+		{Pattern: "[", MatchMode: tracker.MatchMode_MATCH_MODE_GLOB},
+		{MinSize: 10, MaxSize: 5},
+		{PageToken: "not-a-token"},
+	}
+	for _, in := range bad {
+		_, err := service.SearchFiles(context.Background(), in)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("status=%s, got %s", codes.InvalidArgument, status.Code(err))
+		}
+	}
+}
+
+func TestGetPeersPagination(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	hosts := []string{"127.0.0.1:5000", "127.0.0.1:1000", "127.0.0.1:4000", "127.0.0.1:2000", "127.0.0.1:3000"}
+	for _, host := range hosts {
+		in := tracker.RegisterPeerRequest{Host: host}
+		if _, err := service.RegisterPeer(context.Background(), &in); err != nil {
+			t.Fatalf("expected to register peer %s: %s", host, err)
+		}
+	}
+
+	var pages [][]string
+	in := &tracker.GetPeersRequest{PageSize: 2}
+	for {
+		resp, err := service.GetPeers(context.Background(), in)
+		if err != nil {
+			t.Fatalf("expected to get peers: %s", err)
+		}
+
+		var page []string
+		for _, peer := range resp.Peers {
+			page = append(page, peer.Host)
+		}
+		pages = append(pages, page)
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		in.PageToken = resp.NextPageToken
+	}
+
+	expectedPages := [][]string{
+		{"127.0.0.1:1000", "127.0.0.1:2000"},
+		{"127.0.0.1:3000", "127.0.0.1:4000"},
+		{"127.0.0.1:5000"},
+	}
+	if !reflect.DeepEqual(pages, expectedPages) {
+		t.Fatalf("pages=%v, got %v", expectedPages, pages)
+	}
+
+	//a peer leaving between pages must not shift the ones after it
+	first, err := service.GetPeers(context.Background(), &tracker.GetPeersRequest{PageSize: 2})
+	if err != nil {
+		t.Fatalf("expected to get peers: %s", err)
+	}
+	if err := store.RemovePeerByHost("127.0.0.1:1000"); err != nil {
+		t.Fatalf("expected to remove peer: %s", err)
+	}
+	second, err := service.GetPeers(context.Background(), &tracker.GetPeersRequest{PageSize: 2, PageToken: first.NextPageToken})
+	if err != nil {
+		t.Fatalf("expected to get peers: %s", err)
+	}
+	if second.Peers[0].Host != "127.0.0.1:3000" {
+		t.Fatalf("host=%s, got %s", "127.0.0.1:3000", second.Peers[0].Host)
+	}
+
+	_, err = service.GetPeers(context.Background(), &tracker.GetPeersRequest{PageToken: "!!!"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("status=%s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+
+	//callers not asking for pages get every peer at once.
+	for i := range 150 {
+		store.RegisterPeer(fmt.Sprintf("10.0.0.1:%d", 10000+i), nil)
+	}
+	all, err := service.GetPeers(context.Background(), &tracker.GetPeersRequest{})
+	if err != nil {
+		t.Fatalf("expected to get peers: %s", err)
+	}
+	if len(all.Peers) != 154 || all.NextPageToken != "" {
+		t.Fatalf("expected 154 peers in a single page, got %d and token %q", len(all.Peers), all.NextPageToken)
+	}
+}
+
+func TestListPeers(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	var hosts []string
+	for i := range 250 {
+		host := fmt.Sprintf("10.0.%d.%d:50051", i/100, i%100)
+		in := tracker.RegisterPeerRequest{Host: host}
+		if _, err := service.RegisterPeer(context.Background(), &in); err != nil {
+			t.Fatalf("expected to register peer %s: %s", host, err)
+		}
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+
+	stream := &peerStream{ctx: context.Background()}
+	if err := service.ListPeers(&tracker.ListPeersRequest{}, stream); err != nil {
+		t.Fatalf("expected to list peers: %s", err)
+	}
+
+	var got []string
+	for _, peer := range stream.peers {
+		got = append(got, peer.Host)
+	}
+	if !slices.Equal(got, hosts) {
+		t.Fatalf("expected %d peers in host order, got %d", len(hosts), len(got))
+	}
+}
+
+// peerStream collects the peers sent by ListPeers.
+type peerStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	peers []*tracker.Peer
+}
+
+func (ps *peerStream) Context() context.Context {
+	return ps.ctx
+}
+
+func (ps *peerStream) Send(peer *tracker.Peer) error {
+	ps.peers = append(ps.peers, peer)
+	return nil
+}
+
+func TestWatchEvents(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	requests := []*tracker.RegisterPeerRequest{
+		{Host: "127.0.0.1:7000"},
+		{Host: "127.0.0.1:9000", Files: []*tracker.File{{Name: "report.pdf", Checksum: "report"}}},
+		{Host: "127.0.0.1:8000", Files: []*tracker.File{{Name: "report.pdf", Checksum: "report"}, {Name: "notes.txt", Checksum: "notes"}}},
+	}
+	for _, req := range requests {
+		if _, err := service.RegisterPeer(context.Background(), req); err != nil {
+			t.Fatalf("expected to register peer %s", req.Host)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//resume after the first registration, the rest comes from history
+	stream := &eventStream{ctx: ctx, events: make(chan *tracker.Event, 16)}
+	in := &tracker.WatchEventsRequest{
+		Pattern:   "*.pdf",
+		MatchMode: tracker.MatchMode_MATCH_MODE_GLOB,
+		Host:      "127.0.0.1:8000",
+		Cursor:    1,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- service.WatchEvents(in, stream)
+	}()
+
+	expected := []tracker.EventKind{
+		tracker.EventKind_EVENT_KIND_PEER_REGISTERED,
+		tracker.EventKind_EVENT_KIND_FILE_ANNOUNCED,
+	}
+	for _, kind := range expected {
+		select {
+		case ev := <-stream.events:
+			if ev.Kind != kind || ev.Host != "127.0.0.1:8000" {
+				t.Fatalf("event=%s of %s, got %s of %s", kind, "127.0.0.1:8000", ev.Kind, ev.Host)
+			}
+			if ev.Kind == tracker.EventKind_EVENT_KIND_FILE_ANNOUNCED && ev.File.GetName() != "report.pdf" {
+				t.Fatalf("file=%s, got %s", "report.pdf", ev.File.GetName())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s event", kind)
+		}
+	}
+
+	cancel()
+	if err := <-done; status.Code(err) != codes.Canceled {
+		t.Fatalf("status=%s, got %s", codes.Canceled, status.Code(err))
+	}
+
+	//resuming from a cursor the tracker never handed out
+	err := service.WatchEvents(&tracker.WatchEventsRequest{Cursor: 1000}, stream)
+	if status.Code(err) != codes.OutOfRange {
+		t.Fatalf("status=%s, got %s", codes.OutOfRange, status.Code(err))
+	}
+}
+
+// eventStream forwards the events sent by WatchEvents.
+type eventStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *tracker.Event
+}
+
+func (es *eventStream) Context() context.Context {
+	return es.ctx
+}
+
+func (es *eventStream) Send(ev *tracker.Event) error {
+	es.events <- ev
+	return nil
+}
+
+func TestGetStats(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	report := &tracker.File{Name: "report.pdf", Size: 100, Checksum: "report"}
+	copied := &tracker.File{Name: "report-copy.pdf", Size: 100, Checksum: "report"}
+	notes := &tracker.File{Name: "notes.txt", Size: 10, Checksum: "notes"}
+	slides := &tracker.File{Name: "slides.pdf", Size: 1000, Checksum: "slides"}
+
+	requests := []*tracker.RegisterPeerRequest{
+		{Host: "127.0.0.1:9000", Files: []*tracker.File{report, copied, notes}},
+		{Host: "127.0.0.1:8000", Files: []*tracker.File{report, notes}},
+		{Host: "127.0.0.1:7000", Files: []*tracker.File{report, slides}},
+	}
+	for _, in := range requests {
+		if _, err := service.RegisterPeer(context.Background(), in); err != nil {
+			t.Fatalf("expected to register peer %s", in.Host)
+		}
+	}
+
+	for _, name := range []string{"notes.txt", "report.pdf", "notes.txt", "missing.txt", "notes.txt", "report.pdf"} {
+		if _, err := service.GetPeersForFile(context.Background(), &tracker.GetPeersForFileRequest{FileName: name}); err != nil {
+			t.Fatalf("expected to get peers for %s: %s", name, err)
+		}
+	}
+
+	stats, err := service.GetStats(context.Background(), &tracker.GetStatsRequest{TopLookups: 2})
+	if err != nil {
+		t.Fatalf("expected to get stats: %s", err)
+	}
+
+	if stats.PeerCount != 3 || stats.FileCount != 3 {
+		t.Fatalf("peers=3 files=3, got peers=%d files=%d", stats.PeerCount, stats.FileCount)
+	}
+
+	if stats.TotalBytes != 1420 || stats.UniqueBytes != 1110 {
+		t.Fatalf("total=1420 unique=1110, got total=%d unique=%d", stats.TotalBytes, stats.UniqueBytes)
+	}
+
+	var replicas []string
+	for _, bucket := range stats.Replicas {
+		replicas = append(replicas, fmt.Sprintf("%d:%d", bucket.Replicas, bucket.FileCount))
+	}
+	if expected := []string{"1:1", "2:1", "3:1"}; !slices.Equal(replicas, expected) {
+		t.Fatalf("replicas=%v, got %v", expected, replicas)
+	}
+
+	if len(stats.SingleReplicaFiles) != 1 || stats.SingleReplicaFiles[0].Checksum != "slides" {
+		t.Fatalf("expected slides to be the only single replica file, got %v", stats.SingleReplicaFiles)
+	}
+
+	var lookups []string
+	for _, lookup := range stats.Lookups {
+		lookups = append(lookups, fmt.Sprintf("%s:%d", lookup.FileName, lookup.Count))
+	}
+	if expected := []string{"notes.txt:3", "report.pdf:2"}; !slices.Equal(lookups, expected) {
+		t.Fatalf("lookups=%v, got %v", expected, lookups)
+	}
+}
+
+func TestPeerTokens(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	host := "127.0.0.1:9000"
+	files := []*tracker.File{{Name: "file1.txt", Size: 10, Checksum: "hash-1"}}
+
+	resp, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Files: files})
+	if err != nil {
+		t.Fatalf("expected to register peer %s: %s", host, err)
+	}
+	if resp.Token == "" {
+		t.Fatal("expected registration to hand out a token")
+	}
+
+	//spoofing the host without its token, or with the token of another peer
+	other, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: "127.0.0.1:8000"})
+	if err != nil {
+		t.Fatalf("expected to register peer: %s", err)
+	}
+
+	for _, token := range []string{"", "not-the-token", other.Token} {
+		calls := map[string]func() error{
+			"register": func() error {
+				_, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Token: token})
+				return err
+			},
+			"update": func() error {
+				_, err := service.UpdatePeer(context.Background(), &tracker.UpdatePeerRequest{Host: host, Token: token})
+				return err
+			},
+			"unregister": func() error {
+				_, err := service.UnRegisterPeer(context.Background(), &tracker.UnRegisterPeerRequest{Host: host, Token: token})
+				return err
+			},
+			"heartbeat": func() error {
+				_, err := service.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: host, Token: token})
+				return err
+			},
+			"announce": func() error {
+				_, err := service.AnnounceChanges(context.Background(), &tracker.AnnounceChangesRequest{Host: host, CatalogSeq: 1, Removed: []string{"file1.txt"}, Token: token})
+				return err
+			},
+		}
+
+		for name, call := range calls {
+			if code := status.Code(call()); code != codes.PermissionDenied {
+				t.Errorf("%s with token %q: status=%s, got %s", name, token, codes.PermissionDenied, code)
+			}
+		}
+	}
+
+	//nothing changed for the real owner
+	peerFiles, err := store.GetPeerByHost(host)
+	if err != nil {
+		t.Fatalf("expected peer to still be registered: %s", err)
+	}
+	if len(peerFiles) != 1 {
+		t.Fatalf("length=%d, got %d", 1, len(peerFiles))
+	}
+
+	//the owner registers again and keeps its token
+	again, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Files: files, Token: resp.Token})
+	if err != nil {
+		t.Fatalf("expected owner to register again: %s", err)
+	}
+	if again.Token != resp.Token {
+		t.Fatal("expected the token to stay the same")
+	}
+
+	if _, err := service.UnRegisterPeer(context.Background(), &tracker.UnRegisterPeerRequest{Host: host, Token: resp.Token}); err != nil {
+		t.Fatalf("expected owner to unregister: %s", err)
+	}
+
+	//once gone, the host is free to be claimed by anyone
+	claimed, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host})
+	if err != nil {
+		t.Fatalf("expected to register a free host: %s", err)
+	}
+	if claimed.Token == resp.Token {
+		t.Fatal("expected a new token for a new registration")
+	}
+}
+
+func TestGetPeersForFileSelection(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store, Selection: selection.LeastLoaded{}, MaxPeers: 3})
+
+	file := &tracker.File{Name: "file.txt", Size: 10, Checksum: "hash"}
+	streams := map[string]int64{
+		"127.0.0.1:9000": 4,
+		"127.0.0.1:8000": 0,
+		"127.0.0.1:7000": 2,
+		"127.0.0.1:6000": 1,
+		"127.0.0.1:5000": 3,
+	}
+
+	for host, active := range streams {
+		resp, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Files: []*tracker.File{file}})
+		if err != nil {
+			t.Fatalf("expected to register peer %s: %s", host, err)
+		}
+
+		hb := tracker.HeartbeatRequest{Host: host, Token: resp.Token, Load: &tracker.Load{ActiveStreams: active}}
+		if _, err := service.Heartbeat(context.Background(), &hb); err != nil {
+			t.Fatalf("expected heartbeat to succeed: %s", err)
+		}
+	}
+
+	tests := map[string]struct {
+		in       *tracker.GetPeersForFileRequest
+		expected []string
+	}{
+		"capped by the tracker": {
+			in:       &tracker.GetPeersForFileRequest{FileName: file.Name},
+			expected: []string{"127.0.0.1:8000", "127.0.0.1:6000", "127.0.0.1:7000"},
+		},
+		"requester left out": {
+			in:       &tracker.GetPeersForFileRequest{FileName: file.Name, Requester: "127.0.0.1:8000"},
+			expected: []string{"127.0.0.1:6000", "127.0.0.1:7000", "127.0.0.1:5000"},
+		},
+		"limit": {
+			in:       &tracker.GetPeersForFileRequest{FileName: file.Name, Limit: 1},
+			expected: []string{"127.0.0.1:8000"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := service.GetPeersForFile(context.Background(), test.in)
+			if err != nil {
+				t.Fatalf("expected to get peers for file: %s", err)
+			}
+
+			var got []string
+			for _, peer := range resp.Peers {
+				got = append(got, peer.Host)
+			}
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("peers=%v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetPeersForFileLocality(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	file := &tracker.File{Name: "file.txt", Size: 10, Checksum: "hash"}
+	peers := map[string]map[string]string{
+		"127.0.0.1:9000": {"region": "eu", "zone": "eu-1"},
+		"127.0.0.1:8000": {"region": "eu", "zone": "eu-2"},
+		"127.0.0.1:7000": {"region": "us", "zone": "us-1"},
+	}
+	for host, labels := range peers {
+		in := tracker.RegisterPeerRequest{Host: host, Files: []*tracker.File{file}, Labels: labels}
+		if _, err := service.RegisterPeer(context.Background(), &in); err != nil {
+			t.Fatalf("expected to register peer %s: %s", host, err)
+		}
+	}
+
+	//a requester without the file, known to the tracker only by its registration
+	in := tracker.RegisterPeerRequest{Host: "127.0.0.1:6000", Labels: map[string]string{"region": "eu", "zone": "eu-1"}}
+	if _, err := service.RegisterPeer(context.Background(), &in); err != nil {
+		t.Fatalf("expected to register peer: %s", err)
+	}
+
+	//peers equally close come in random order, only the closest one is fixed.
+	tests := map[string]struct {
+		in       *tracker.GetPeersForFileRequest
+		first    string
+		expected []string
+	}{
+		"registered labels": {
+			in:       &tracker.GetPeersForFileRequest{FileName: file.Name, Requester: "127.0.0.1:6000"},
+			first:    "127.0.0.1:9000",
+			expected: []string{"127.0.0.1:8000", "127.0.0.1:9000"},
+		},
+		"sent labels": {
+			in:       &tracker.GetPeersForFileRequest{FileName: file.Name, Labels: map[string]string{"region": "us"}},
+			first:    "127.0.0.1:7000",
+			expected: []string{"127.0.0.1:7000"},
+		},
+		"no local replica": {
+			in:       &tracker.GetPeersForFileRequest{FileName: file.Name, Labels: map[string]string{"region": "ap"}},
+			expected: []string{"127.0.0.1:7000", "127.0.0.1:8000", "127.0.0.1:9000"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := service.GetPeersForFile(context.Background(), test.in)
+			if err != nil {
+				t.Fatalf("expected to get peers for file: %s", err)
+			}
+
+			var got []string
+			for _, peer := range resp.Peers {
+				got = append(got, peer.Host)
+			}
+
+			if test.first != "" && (len(got) == 0 || got[0] != test.first) {
+				t.Fatalf("expected %s first, got %v", test.first, got)
+			}
+
+			slices.Sort(got)
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("peers=%v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestListConflicts(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	v1 := &tracker.File{Name: "report.pdf", Size: 100, Checksum: "v1"}
+	v2 := &tracker.File{Name: "report.pdf", Size: 120, Checksum: "v2"}
+	v3 := &tracker.File{Name: "report.pdf", Size: 130, Checksum: "v3"}
+	notesA := &tracker.File{Name: "notes.txt", Size: 10, Checksum: "notes-a"}
+	notesB := &tracker.File{Name: "notes.txt", Size: 11, Checksum: "notes-b"}
+	slides := &tracker.File{Name: "slides.pdf", Size: 1000, Checksum: "slides"}
+
+	requests := []*tracker.RegisterPeerRequest{
+		{Host: "127.0.0.1:9000", Files: []*tracker.File{v1, notesA, slides}},
+		{Host: "127.0.0.1:8000", Files: []*tracker.File{v2, notesB, slides}},
+		{Host: "127.0.0.1:7000", Files: []*tracker.File{v2}},
+		{Host: "127.0.0.1:6000", Files: []*tracker.File{v3}},
+	}
+	for _, in := range requests {
+		if _, err := service.RegisterPeer(context.Background(), in); err != nil {
+			t.Fatalf("expected to register peer %s", in.Host)
+		}
+	}
+
+	//unreachable peers do not count.
+	dead := peerstore.Thresholds{SuspectAfter: 1, DeadAfter: 1}
+	if _, err := store.RecordProbeFailure("127.0.0.1:6000", dead); err != nil {
+		t.Fatalf("expected to record probe failure: %s", err)
+	}
+
+	resp, err := service.GetPeersForFile(context.Background(), &tracker.GetPeersForFileRequest{FileName: "report.pdf", Limit: 1})
+	if err != nil {
+		t.Fatalf("expected to get peers for file: %s", err)
+	}
+	if len(resp.Peers) != 1 {
+		t.Fatalf("expected the peers to be capped, got %d", len(resp.Peers))
+	}
+	if got := versionStrings(resp.Versions); !slices.Equal(got, []string{"v2:2", "v1:1"}) {
+		t.Fatalf("versions=[v2:2 v1:1], got %v", got)
+	}
+
+	//versions alone do not count as a lookup.
+	only, err := service.GetFileVersions(context.Background(), &tracker.GetFileVersionsRequest{FileName: "report.pdf"})
+	if err != nil {
+		t.Fatalf("expected to get file versions: %s", err)
+	}
+	if got := versionStrings(only.Versions); !slices.Equal(got, []string{"v2:2", "v1:1"}) {
+		t.Fatalf("versions=[v2:2 v1:1], got %v", got)
+	}
+	stats, err := service.GetStats(context.Background(), &tracker.GetStatsRequest{})
+	if err != nil {
+		t.Fatalf("expected to get stats: %s", err)
+	}
+	if len(stats.Lookups) != 1 || stats.Lookups[0].Count != 1 {
+		t.Fatalf("expected a single lookup of report.pdf, got %v", stats.Lookups)
+	}
+
+	page, err := service.ListConflicts(context.Background(), &tracker.ListConflictsRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("expected to list conflicts: %s", err)
+	}
+	if len(page.Conflicts) != 1 || page.Conflicts[0].FileName != "notes.txt" || page.NextPageToken == "" {
+		t.Fatalf("expected notes.txt first with more to come, got %v", page)
+	}
+	if got := versionStrings(page.Conflicts[0].Versions); !slices.Equal(got, []string{"notes-a:1", "notes-b:1"}) {
+		t.Fatalf("versions=[notes-a:1 notes-b:1], got %v", got)
+	}
+
+	page, err = service.ListConflicts(context.Background(), &tracker.ListConflictsRequest{PageSize: 1, PageToken: page.NextPageToken})
+	if err != nil {
+		t.Fatalf("expected to list conflicts: %s", err)
+	}
+	if len(page.Conflicts) != 1 || page.Conflicts[0].FileName != "report.pdf" || page.NextPageToken != "" {
+		t.Fatalf("expected report.pdf last, got %v", page)
+	}
+
+	_, err = service.ListConflicts(context.Background(), &tracker.ListConflictsRequest{PageToken: "!"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an invalid token to fail with %s, got %v", codes.InvalidArgument, err)
+	}
+}
+
+func TestGetCatalogSummary(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	v1 := &tracker.File{Name: "report.pdf", Size: 100, Checksum: "v1"}
+	v2 := &tracker.File{Name: "report.pdf", Size: 120, Checksum: "v2"}
+	notes := &tracker.File{Name: "notes.txt", Size: 10, Checksum: "notes"}
+
+	requests := []*tracker.RegisterPeerRequest{
+		{Host: "127.0.0.1:9000", Files: []*tracker.File{v1, notes}},
+		{Host: "127.0.0.1:8000", Files: []*tracker.File{v1}},
+		{Host: "127.0.0.1:7000", Files: []*tracker.File{v2}},
+		{Host: "127.0.0.1:6000", Files: []*tracker.File{notes}},
+	}
+	for _, in := range requests {
+		if _, err := service.RegisterPeer(context.Background(), in); err != nil {
+			t.Fatalf("expected to register peer %s", in.Host)
+		}
+	}
+
+	//unreachable peers are left out.
+	dead := peerstore.Thresholds{SuspectAfter: 1, DeadAfter: 1}
+	if _, err := store.RecordProbeFailure("127.0.0.1:6000", dead); err != nil {
+		t.Fatalf("expected to record probe failure: %s", err)
+	}
+
+	resp, err := service.GetCatalogSummary(context.Background(), &tracker.GetCatalogSummaryRequest{})
+	if err != nil {
+		t.Fatalf("expected to get catalog summary: %s", err)
+	}
+
+	var got []string
+	for _, e := range resp.Entries {
+		got = append(got, fmt.Sprintf("%s:%s%v", e.File.Name, e.File.Checksum, e.Hosts))
+	}
+	expected := []string{
+		"notes.txt:notes[127.0.0.1:9000]",
+		"report.pdf:v1[127.0.0.1:8000 127.0.0.1:9000]",
+		"report.pdf:v2[127.0.0.1:7000]",
+	}
+	if !slices.Equal(got, expected) {
+		t.Fatalf("entries=%v, got %v", expected, got)
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	teamA := metadata.NewIncomingContext(context.Background(), metadata.Pairs("namespace", "team-a"))
+	teamB := metadata.NewIncomingContext(context.Background(), metadata.Pairs("namespace", "team-b"))
+	both := metadata.NewIncomingContext(context.Background(), metadata.Pairs("namespace", "team-a", "namespace", "team-b"))
+
+	register := func(ctx context.Context, host string) {
+		t.Helper()
+		in := tracker.RegisterPeerRequest{
+			Host:  host,
+			Files: []*tracker.File{{Name: "report.pdf", Size: 100, Checksum: "report"}},
+		}
+		if _, err := service.RegisterPeer(ctx, &in); err != nil {
+			t.Fatalf("expected to register peer %s: %s", host, err)
+		}
+	}
+	register(teamA, "127.0.0.1:9000")
+	register(teamB, "127.0.0.1:9001")
+	register(context.Background(), "127.0.0.1:9002")
+
+	tests := map[string]struct {
+		ctx      context.Context
+		expected []string
+	}{
+		"team-a":    {ctx: teamA, expected: []string{"127.0.0.1:9000"}},
+		"team-b":    {ctx: teamB, expected: []string{"127.0.0.1:9001"}},
+		"both":      {ctx: both, expected: []string{"127.0.0.1:9000", "127.0.0.1:9001"}},
+		"no header": {ctx: context.Background(), expected: []string{"127.0.0.1:9002"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			found, err := service.GetPeersForFile(test.ctx, &tracker.GetPeersForFileRequest{FileName: "report.pdf"})
+			if err != nil {
+				t.Fatalf("expected to get peers for file: %s", err)
+			}
+			if got := peerHosts(found.Peers); !slices.Equal(got, test.expected) {
+				t.Errorf("peers for file=%v, got %v", test.expected, got)
+			}
+
+			page, err := service.GetPeers(test.ctx, &tracker.GetPeersRequest{})
+			if err != nil {
+				t.Fatalf("expected to get peers: %s", err)
+			}
+			if got := peerHosts(page.Peers); !slices.Equal(got, test.expected) {
+				t.Errorf("peers=%v, got %v", test.expected, got)
+			}
+
+			stats, err := service.GetStats(test.ctx, &tracker.GetStatsRequest{})
+			if err != nil {
+				t.Fatalf("expected to get stats: %s", err)
+			}
+			if stats.PeerCount != int64(len(test.expected)) {
+				t.Errorf("peer count=%d, got %d", len(test.expected), stats.PeerCount)
+			}
+		})
+	}
+}
+
+func peerHosts(peers []*tracker.Peer) []string {
+	hosts := make([]string, len(peers))
+	for i, p := range peers {
+		hosts[i] = p.Host
+	}
+	slices.Sort(hosts)
+	return hosts
+}
+
+func TestMaxFiles(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store, MaxFiles: 2})
+
+	files := []*tracker.File{
+		{Name: "report.pdf", Size: 100, Checksum: "report"},
+		{Name: "notes.txt", Size: 10, Checksum: "notes"},
+		{Name: "slides.pdf", Size: 50, Checksum: "slides"},
+	}
+
+	_, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: "127.0.0.1:9000", Files: files})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+	if len(store.GetAllPeers()) != 0 {
+		t.Fatal("expected the peer not to be registered")
+	}
+
+	registered, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: "127.0.0.1:9000", Files: files[:2]})
+	if err != nil {
+		t.Fatalf("expected to register peer: %s", err)
+	}
+
+	_, err = service.UpdatePeer(context.Background(), &tracker.UpdatePeerRequest{Host: "127.0.0.1:9000", Token: registered.Token, Files: files})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+
+	_, err = service.AnnounceChanges(context.Background(), &tracker.AnnounceChangesRequest{Host: "127.0.0.1:9000", Token: registered.Token, CatalogSeq: 1, Added: files})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestExportImportState(t *testing.T) {
+	admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs(service.AdminTokenKey, "secret"))
+	local := grpcpeer.NewContext(context.Background(), &grpcpeer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40000}})
+
+	fresh := peerstore.New()
+	freshService := service.New(&service.Config{Store: fresh, AdminToken: "secret"})
+
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store, AdminToken: "secret"})
+
+	in := tracker.RegisterPeerRequest{
+		Host:   "127.0.0.1:9000",
+		Files:  []*tracker.File{{Name: "report.pdf", Size: 100, Checksum: "report"}},
+		Labels: map[string]string{"zone": "a"},
+	}
+	registered, err := service.RegisterPeer(context.Background(), &in)
+	if err != nil {
+		t.Fatalf("expected to register peer: %s", err)
+	}
+
+	//reports and unproven contents move along with the peers.
+	if err := store.SetUnproven(in.Host, "report", true); err != nil {
+		t.Fatalf("expected to mark the content unproven: %s", err)
+	}
+	if _, err := store.ReportPeer(in.Host, peerstore.Report{Reporter: "anonymous/10.0.0.1", Penalty: 2, Anonymous: true}, peerstore.DefaultReputation); err != nil {
+		t.Fatalf("expected to report peer: %s", err)
+	}
+
+	//remote callers without the admin token can neither export nor import.
+	if _, err := service.ExportState(context.Background(), &tracker.ExportStateRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("code=%s, got %s", codes.PermissionDenied, status.Code(err))
+	}
+	if _, err := freshService.ImportState(local, &tracker.ImportStateRequest{Snapshot: []byte("{}")}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("code=%s, got %s", codes.PermissionDenied, status.Code(err))
+	}
+
+	//local callers get the snapshot without token hashes.
+	withoutTokens, err := service.ExportState(local, &tracker.ExportStateRequest{})
+	if err != nil {
+		t.Fatalf("expected to export state: %s", err)
+	}
+	if strings.Contains(string(withoutTokens.Snapshot), "token_hash") {
+		t.Fatalf("expected no token hashes, got %s", withoutTokens.Snapshot)
+	}
+
+	exported, err := service.ExportState(admin, &tracker.ExportStateRequest{})
+	if err != nil {
+		t.Fatalf("expected to export state: %s", err)
+	}
+	if !strings.Contains(string(exported.Snapshot), "token_hash") {
+		t.Fatalf("expected token hashes, got %s", exported.Snapshot)
+	}
+
+	//a tracker with peers does not take a snapshot.
+	_, err = service.ImportState(admin, &tracker.ImportStateRequest{Snapshot: exported.Snapshot})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("code=%s, got %s", codes.FailedPrecondition, status.Code(err))
+	}
+
+	_, err = service.ImportState(admin, &tracker.ImportStateRequest{Snapshot: []byte("{")})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("code=%s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+
+	resp, err := freshService.ImportState(admin, &tracker.ImportStateRequest{Snapshot: exported.Snapshot})
+	if err != nil {
+		t.Fatalf("expected to import state: %s", err)
+	}
+	if resp.PeerCount != 1 {
+		t.Fatalf("peers=1, got %d", resp.PeerCount)
+	}
+
+	peers := fresh.GetAllPeers()
+	if len(peers) != 1 || peers[0].Host != in.Host || peers[0].Labels["zone"] != "a" || len(peers[0].Files) != 1 {
+		t.Fatalf("expected the exported peer, got %+v", peers)
+	}
+	if !slices.Equal(peers[0].Unproven, []string{"report"}) || peers[0].Penalty < 1.9 {
+		t.Fatalf("expected the record of the exported peer, got %+v", peers[0])
+	}
+
+	//the peer keeps its token on the new tracker.
+	hb := tracker.HeartbeatRequest{Host: in.Host, Token: registered.Token}
+	if _, err := freshService.Heartbeat(context.Background(), &hb); err != nil {
+		t.Fatalf("expected the imported peer to keep its token: %s", err)
+	}
+}
+
+func versionStrings(versions []*tracker.FileSummary) []string {
+	var got []string
+	for _, v := range versions {
+		got = append(got, fmt.Sprintf("%s:%d", v.File.Checksum, v.PeerCount))
+	}
+	return got
+}
+
+func TestDrainPeer(t *testing.T) {
+	admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs(service.AdminTokenKey, "secret"))
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store, AdminToken: "secret", LeaseTTL: 50 * time.Millisecond})
+
+	unique := &tracker.File{Name: "unique.txt", Size: 10, Checksum: "unique"}
+	shared := &tracker.File{Name: "shared.txt", Size: 20, Checksum: "shared"}
+	broken := &tracker.File{Name: "broken.txt", Size: 30, Checksum: "broken"}
+
+	target := startPeer(t, store)
+	drained := "127.0.0.1:9000"
+	failing := "127.0.0.1:8000"
+
+	requests := []*tracker.RegisterPeerRequest{
+		{Host: target, Files: []*tracker.File{shared}},
+		{Host: drained, Files: []*tracker.File{unique, shared}},
+		{Host: failing, Files: []*tracker.File{broken}},
+	}
+	tokens := make(map[string]string)
+	for _, in := range requests {
+		resp, err := service.RegisterPeer(context.Background(), in)
+		if err != nil {
+			t.Fatalf("expected to register peer %s", in.Host)
+		}
+		tokens[in.Host] = resp.Token
+	}
+
+	//draining peers are no longer handed out
+	if err := store.SetDraining(drained, true); err != nil {
+		t.Fatalf("expected to set draining: %s", err)
+	}
+	resp, err := service.GetPeersForFile(context.Background(), &tracker.GetPeersForFileRequest{FileName: shared.Name})
+	if err != nil {
+		t.Fatalf("expected to get peers for file: %s", err)
+	}
+	if len(resp.Peers) != 1 || resp.Peers[0].Host != target {
+		t.Fatalf("expected only %s to be handed out, got %v", target, resp.Peers)
+	}
+
+	stream := drainStream{ctx: context.Background()}
+	if err := service.DrainPeer(&tracker.DrainPeerRequest{Host: drained, Token: tokens[drained]}, &stream); err != nil {
+		t.Fatalf("expected to drain peer: %s", err)
+	}
+
+	var got []string
+	for _, p := range stream.progress {
+		got = append(got, fmt.Sprintf("%d/%d %s %s %t", p.CopiedFiles, p.TotalFiles, p.GetFile().GetName(), p.Target, p.Done))
+	}
+	expected := []string{
+		"0/1   false",
+		"1/1 unique.txt " + target + " false",
+		"1/1   true",
+	}
+	if !slices.Equal(got, expected) {
+		t.Fatalf("progress=%v, got %v", expected, got)
+	}
+
+	if _, err := store.GetPeerByHost(drained); err == nil {
+		t.Fatal("expected drained peer to be unregistered")
+	}
+	if files, _ := store.GetPeerByHost(target); files[unique.Name].Checksum != unique.Checksum {
+		t.Fatalf("expected %s to serve %s, got %v", target, unique.Name, files)
+	}
+
+	//the drained peer is told so instead of registering again on its own
+	_, err = service.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: drained, Token: tokens[drained]})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected heartbeat to fail with %s, got %v", codes.FailedPrecondition, err)
+	}
+
+	//a file that can not be copied keeps the peer serving
+	stream = drainStream{ctx: context.Background()}
+	err = service.DrainPeer(&tracker.DrainPeerRequest{Host: failing, Token: tokens[failing]}, &stream)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected drain to fail with %s, got %v", codes.Unavailable, err)
+	}
+	resp, err = service.GetPeersForFile(context.Background(), &tracker.GetPeersForFileRequest{FileName: broken.Name})
+	if err != nil {
+		t.Fatalf("expected to get peers for file: %s", err)
+	}
+	if len(resp.Peers) != 1 || resp.Peers[0].Host != failing {
+		t.Fatalf("expected %s to be handed out again, got %v", failing, resp.Peers)
+	}
+
+	err = service.DrainPeer(&tracker.DrainPeerRequest{Host: failing, Token: "not-the-token"}, &drainStream{ctx: context.Background()})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected drain to fail with %s, got %v", codes.PermissionDenied, err)
+	}
+
+	//admins drain any peer without its token
+	err = service.DrainPeer(&tracker.DrainPeerRequest{Host: failing}, &drainStream{ctx: admin})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected admin drain to get to copying and fail with %s, got %v", codes.Unavailable, err)
+	}
+
+	//once its lease would have expired, the drained peer is forgotten like any other
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go service.RunReaper(ctx, 10*time.Millisecond)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err = service.Heartbeat(context.Background(), &tracker.HeartbeatRequest{Host: drained, Token: tokens[drained]})
+		if status.Code(err) == codes.NotFound {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected heartbeat to fail with %s, got %v", codes.NotFound, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDrainPeerWhileAnnouncing(t *testing.T) {
+	store := peerstore.New()
+	service := service.New(&service.Config{Store: store})
+
+	target := startPeer(t, store)
+	drained := "127.0.0.1:9000"
+
+	var files []*tracker.File
+	for i := range 2000 {
+		files = append(files, &tracker.File{Name: fmt.Sprintf("file-%d.txt", i), Size: 10, Checksum: fmt.Sprintf("hash-%d", i)})
+	}
+	if _, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: target, Files: files}); err != nil {
+		t.Fatalf("expected to register peer %s: %s", target, err)
+	}
+	resp, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: drained, Files: files})
+	if err != nil {
+		t.Fatalf("expected to register peer %s: %s", drained, err)
+	}
+
+	//the peer keeps changing its catalog while it is drained, run with -race
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(started)
+		for seq := uint64(1); ctx.Err() == nil; seq++ {
+			in := tracker.AnnounceChangesRequest{Host: drained, CatalogSeq: seq, Token: resp.Token}
+			if seq%2 == 1 {
+				in.Added = []*tracker.File{{Name: "new.txt", Size: 1, Checksum: "new"}}
+			} else {
+				in.Removed = []string{"new.txt"}
+			}
+			if _, err := service.AnnounceChanges(context.Background(), &in); err != nil {
+				return
+			}
+			if seq == 1 {
+				started <- struct{}{}
+			}
+		}
+	}()
+	<-started
+
+	err = service.DrainPeer(&tracker.DrainPeerRequest{Host: drained, Token: resp.Token}, &drainStream{ctx: context.Background()})
+	cancel()
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("expected to drain peer: %s", err)
+	}
+}
+
+// drainStream collects the progress sent by DrainPeer.
+type drainStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	progress []*tracker.DrainProgress
+}
+
+func (ds *drainStream) Context() context.Context {
+	return ds.ctx
+}
+
+func (ds *drainStream) Send(p *tracker.DrainProgress) error {
+	ds.progress = append(ds.progress, proto.Clone(p).(*tracker.DrainProgress))
+	return nil
+}
+
+// startPeer serves a peer that announces every copy it is asked for straight into
+// the store, the way a peer announces it to the tracker.
+func startPeer(t *testing.T, store *peerstore.Store) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	server := grpc.NewServer()
+	peer.RegisterPeerServiceServer(server, &peerServiceMock{host: lis.Addr().String(), store: store})
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+type peerServiceMock struct {
+	peer.UnimplementedPeerServiceServer
+	host  string
+	store *peerstore.Store
+}
+
+func (ps *peerServiceMock) ReplicateFile(ctx context.Context, in *peer.ReplicateFileRequest) (*peer.ReplicateFileResponse, error) {
+	if in.File.Checksum == "broken" {
+		return nil, status.Error(codes.DataLoss, "checksum mismatch")
+	}
+
+	seq, _, err := ps.store.Catalog(ps.host)
+	if err != nil {
+		return nil, err
+	}
+	file := peerstore.FileMetadata{Name: in.File.Name, Size: in.File.Size, Checksum: in.File.Checksum}
+	if _, err := ps.store.ApplyChanges(ps.host, seq+1, []peerstore.FileMetadata{file}, nil); err != nil {
+		return nil, err
+	}
+	return &peer.ReplicateFileResponse{Success: true, Message: "OK"}, nil
+}
+
+func TestReportPeer(t *testing.T) {
+	store := peerstore.New()
+	reputation := peerstore.Reputation{HalfLife: time.Hour, BanThreshold: 10, BanDuration: time.Minute, MinReporters: 2}
+	service := service.New(&service.Config{Store: store, Selection: selection.LeastLoaded{}, Reputation: reputation})
+
+	file := &tracker.File{Name: "file.txt", Size: 10, Checksum: "hash"}
+	tokens := make(map[string]string)
+	for _, host := range []string{"127.0.0.1:6000", "127.0.0.1:7000", "127.0.0.1:8000", "127.0.0.1:9000"} {
+		resp, err := service.RegisterPeer(context.Background(), &tracker.RegisterPeerRequest{Host: host, Files: []*tracker.File{file}})
+		if err != nil {
+			t.Fatalf("expected to register peer %s: %s", host, err)
+		}
+		tokens[host] = resp.Token
+
+		//the prober never reaches the first one.
+		if host != "127.0.0.1:6000" {
+			if err := store.RecordProbeSuccess(host, time.Millisecond); err != nil {
+				t.Fatalf("expected to record probe success: %s", err)
+			}
+		}
+	}
+
+	from := func(ip string) context.Context {
+		return grpcpeer.NewContext(context.Background(), &grpcpeer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}})
+	}
+	handOut := func(requester string) {
+		in := tracker.GetPeersForFileRequest{FileName: file.Name, Requester: requester}
+		if _, err := service.GetPeersForFile(context.Background(), &in); err != nil {
+			t.Fatalf("expected to get peers for file: %s", err)
+		}
+	}
+
+	hostsForFile := func() []string {
+		resp, err := service.GetPeersForFile(context.Background(), &tracker.GetPeersForFileRequest{FileName: file.Name})
+		if err != nil {
+			t.Fatalf("expected to get peers for file: %s", err)
+		}
+		var got []string
+		for _, peer := range resp.Peers {
+			got = append(got, peer.Host)
+		}
+		return got
+	}
+
+	handOut("127.0.0.1:8000")
+	mismatch := tracker.ReportPeerRequest{
+		Host:     "127.0.0.1:7000",
+		Reason:   tracker.ReportReason_REPORT_REASON_CHECKSUM_MISMATCH,
+		FileName: file.Name,
+		Reporter: "127.0.0.1:8000",
+		Token:    tokens["127.0.0.1:8000"],
+	}
+	resp, err := service.ReportPeer(from("10.0.0.8"), &mismatch)
+	if err != nil {
+		t.Fatalf("expected to report peer: %s", err)
+	}
+	if resp.Penalty < 4.9 || resp.BannedUntil != nil {
+		t.Fatalf("expected a penalty of 5 without a ban, got %f banned until %v", resp.Penalty, resp.BannedUntil)
+	}
+
+	//reported peers are handed out last.
+	if got := hostsForFile(); len(got) != 4 || got[3] != "127.0.0.1:7000" {
+		t.Fatalf("expected 127.0.0.1:7000 to come last, got %v", got)
+	}
+
+	//anonymous reports weigh half, and like signed ones only count once per reporter.
+	anonymous := tracker.ReportPeerRequest{Host: "127.0.0.1:7000", Reason: tracker.ReportReason_REPORT_REASON_CHECKSUM_MISMATCH}
+	for range 4 {
+		resp, err = service.ReportPeer(context.Background(), &anonymous)
+		if err != nil {
+			t.Fatalf("expected to report peer: %s", err)
+		}
+		if _, err := service.ReportPeer(from("10.0.0.8"), &mismatch); err != nil {
+			t.Fatalf("expected to report peer: %s", err)
+		}
+	}
+	if resp.Penalty < 7.4 || resp.Penalty > 7.6 || resp.BannedUntil != nil {
+		t.Fatalf("expected a penalty of 7.5 without a ban, got %f banned until %v", resp.Penalty, resp.BannedUntil)
+	}
+
+	//reporters never handed the peer, never reached by the prober or behind the address
+	//of another reporter can not ban it.
+	second := tracker.ReportPeerRequest{
+		Host:     "127.0.0.1:7000",
+		Reason:   tracker.ReportReason_REPORT_REASON_CHECKSUM_MISMATCH,
+		Reporter: "127.0.0.1:9000",
+		Token:    tokens["127.0.0.1:9000"],
+	}
+	unreachable := tracker.ReportPeerRequest{
+		Host:     "127.0.0.1:7000",
+		Reason:   tracker.ReportReason_REPORT_REASON_CHECKSUM_MISMATCH,
+		Reporter: "127.0.0.1:6000",
+		Token:    tokens["127.0.0.1:6000"],
+	}
+	handOut("127.0.0.1:6000")
+
+	steps := []struct {
+		name   string
+		ip     string
+		report *tracker.ReportPeerRequest
+	}{
+		{name: "not handed out", ip: "10.0.0.9", report: &second},
+		{name: "never probed", ip: "10.0.0.6", report: &unreachable},
+		{name: "same address", ip: "10.0.0.8", report: &second},
+	}
+	for _, step := range steps {
+		if step.name == "same address" {
+			handOut("127.0.0.1:9000")
+		}
+		resp, err = service.ReportPeer(from(step.ip), step.report)
+		if err != nil {
+			t.Fatalf("%s: expected to report peer: %s", step.name, err)
+		}
+		if resp.BannedUntil != nil {
+			t.Fatalf("%s: expected peer not to be banned", step.name)
+		}
+	}
+
+	//past the threshold, peers reported by several peers are banned.
+	resp, err = service.ReportPeer(from("10.0.0.9"), &second)
+	if err != nil {
+		t.Fatalf("expected to report peer: %s", err)
+	}
+	if resp.BannedUntil == nil {
+		t.Fatal("expected peer to be banned")
+	}
+	if got := hostsForFile(); len(got) != 3 || slices.Contains(got, "127.0.0.1:7000") {
+		t.Fatalf("expected 127.0.0.1:7000 to be left out, got %v", got)
+	}
+
+	tests := map[string]struct {
+		in   *tracker.ReportPeerRequest
+		code codes.Code
+	}{
+		"missing host": {
+			in:   &tracker.ReportPeerRequest{Reason: tracker.ReportReason_REPORT_REASON_TIMEOUT},
+			code: codes.InvalidArgument,
+		},
+		"missing reason": {
+			in:   &tracker.ReportPeerRequest{Host: "127.0.0.1:9000"},
+			code: codes.InvalidArgument,
+		},
+		"reporting itself": {
+			in:   &tracker.ReportPeerRequest{Host: "127.0.0.1:9000", Reason: tracker.ReportReason_REPORT_REASON_TIMEOUT, Reporter: "127.0.0.1:9000", Token: tokens["127.0.0.1:9000"]},
+			code: codes.InvalidArgument,
+		},
+		"invalid token": {
+			in:   &tracker.ReportPeerRequest{Host: "127.0.0.1:9000", Reason: tracker.ReportReason_REPORT_REASON_TIMEOUT, Reporter: "127.0.0.1:8000", Token: "invalid"},
+			code: codes.PermissionDenied,
+		},
+		"unknown peer": {
+			in:   &tracker.ReportPeerRequest{Host: "0.0.0.0:9000", Reason: tracker.ReportReason_REPORT_REASON_TIMEOUT},
+			code: codes.NotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.ReportPeer(context.Background(), test.in)
+			if status.Code(err) != test.code {
+				t.Fatalf("code=%s, got %s", test.code, status.Code(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/peerstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ExportState dumps every peer of the store, along with its files, catalog sequence,
+// token hash and labels, and the records of the reported hosts into a versioned JSON
+// snapshot. Only admins and callers on the same machine as the tracker can export, and
+// only admins get the token hashes: peers restored without one register again under a
+// new token.
+func (s *Service) ExportState(ctx context.Context, in *tracker.ExportStateRequest) (*tracker.ExportStateResponse, error) {
+	admin := s.isAdmin(ctx)
+	if !admin && !isLocal(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "exporting state requires the admin token")
+	}
+
+	snap, err := peerstore.TakeSnapshot(s.store)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "take snapshot: %s", err)
+	}
+
+	if !admin {
+		for i := range snap.Peers {
+			snap.Peers[i].TokenHash = ""
+		}
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "marshal snapshot: %s", err)
+	}
+	return &tracker.ExportStateResponse{Snapshot: data}, nil
+}
+
+// ImportState loads a snapshot made by ExportState, only admins can import. Only trackers
+// without peers accept one, so restored peers never replace peers that registered on
+// their own. Peers keep their tokens and get a fresh lease.
+func (s *Service) ImportState(ctx context.Context, in *tracker.ImportStateRequest) (*tracker.ImportStateResponse, error) {
+	if !s.isAdmin(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "importing state requires the admin token")
+	}
+
+	var snap peerstore.Snapshot
+	if err := json.Unmarshal(in.GetSnapshot(), &snap); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid snapshot: %s", err)
+	}
+
+	if snap.Version != peerstore.SnapshotVersion {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported snapshot version: %d", snap.Version)
+	}
+
+	//no peer can register while the snapshot is loaded.
+	s.registerMu.Lock()
+	defer s.registerMu.Unlock()
+
+	if hosts := s.store.Hosts(); len(hosts) > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "tracker already has %d peers, import into a fresh tracker", len(hosts))
+	}
+
+	if err := peerstore.Restore(s.store, snap); err != nil {
+		return nil, status.Errorf(codes.Internal, "restore snapshot: %s", err)
+	}
+
+	return &tracker.ImportStateResponse{
+		StatusCode: int64(codes.OK),
+		Message:    codes.OK.String(),
+		PeerCount:  int64(len(snap.Peers)),
+	}, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/hamidoujand/P2P-file-sharing-network/namespace"
+	"github.com/hamidoujand/P2P-file-sharing-network/tracker/pb/tracker"
+)
+
+const (
+	// defaultTopLookups is the number of most looked up files reported by default.
+	defaultTopLookups = 10
+	// maxLookupNames bounds the names lookups are counted for, so clients asking for
+	// random names can not grow it forever.
+	maxLookupNames = 10_000
+)
+
+// lookupKey identifies a file name looked up in a namespace.
+type lookupKey struct {
+	namespace string
+	name      string
+}
+
+// lookups counts how many times every file name was looked up in every namespace.
+type lookups struct {
+	mu     sync.Mutex
+	counts map[lookupKey]uint64
+}
+
+func newLookups() *lookups {
+	return &lookups{
+		counts: make(map[lookupKey]uint64),
+	}
+}
+
+// inc counts a lookup of the name in each of the namespaces, names past maxLookupNames
+// are not counted.
+func (l *lookups) inc(namespaces []string, name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, ns := range namespaces {
+		k := lookupKey{namespace: ns, name: name}
+		if _, ok := l.counts[k]; !ok && len(l.counts) >= maxLookupNames {
+			return
+		}
+		l.counts[k]++
+	}
+}
+
+// top returns the n most looked up names in the namespaces, ties are ordered by name.
+func (l *lookups) top(namespaces []string, n int) []*tracker.FileLookups {
+	l.mu.Lock()
+	counts := make(map[string]uint64)
+	for k, count := range l.counts {
+		if slices.Contains(namespaces, k.namespace) {
+			counts[k.name] += count
+		}
+	}
+	l.mu.Unlock()
+
+	results := make([]*tracker.FileLookups, 0, len(counts))
+	for name, count := range counts {
+		results = append(results, &tracker.FileLookups{FileName: name, Count: count})
+	}
+
+	slices.SortFunc(results, func(a, b *tracker.FileLookups) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.FileName, b.FileName))
+	})
+
+	if len(results) > n {
+		results = results[:n]
+	}
+	return results
+}
+
+// GetStats reports the size of the caller's namespaces, how well their files are
+// replicated and which files are looked up the most in them.
+func (s *Service) GetStats(ctx context.Context, in *tracker.GetStatsRequest) (*tracker.GetStatsResponse, error) {
+	namespaces := namespace.FromIncomingContext(ctx)
+	stats := s.store.Stats(namespaces...)
+
+	resp := tracker.GetStatsResponse{
+		PeerCount:   int64(stats.Peers),
+		FileCount:   int64(stats.Files),
+		TotalBytes:  stats.TotalBytes,
+		UniqueBytes: stats.UniqueBytes,
+	}
+
+	for replicas, files := range stats.Replicas {
+		resp.Replicas = append(resp.Replicas, &tracker.ReplicaBucket{
+			Replicas:  int64(replicas),
+			FileCount: int64(files),
+		})
+	}
+	slices.SortFunc(resp.Replicas, func(a, b *tracker.ReplicaBucket) int {
+		return cmp.Compare(a.Replicas, b.Replicas)
+	})
+
+	for _, file := range stats.SingleReplica {
+		resp.SingleReplicaFiles = append(resp.SingleReplicaFiles, &tracker.File{
+			Name:     file.Name,
+			Size:     file.Size,
+			Checksum: file.Checksum,
+		})
+	}
+
+	top := int(in.GetTopLookups())
+	if top <= 0 {
+		top = defaultTopLookups
+	}
+	resp.Lookups = s.lookups.top(namespaces, top)
+
+	return &resp, nil
+}